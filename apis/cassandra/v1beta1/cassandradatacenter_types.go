@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Jeffail/gabs"
 	"github.com/k8ssandra/cass-operator/pkg/serverconfig"
@@ -37,12 +38,53 @@ const (
 	// PromMetricsLabel is a service label that can be selected for prometheus metrics scraping
 	PromMetricsLabel = "cassandra.datastax.com/prom-metrics"
 
+	// ClusterAnnotation is the operator's annotation for the cluster name
+	ClusterAnnotation = ClusterLabel
+
 	// DatacenterAnnotation is the operator's annotation for the datacenter name
 	DatacenterAnnotation = DatacenterLabel
 
+	// RackAnnotation is the operator's annotation for the rack name
+	RackAnnotation = RackLabel
+
 	// ConfigHashAnnotation is the operator's annotation for the hash of the ConfigSecret
 	ConfigHashAnnotation = "cassandra.datastax.com/config-hash"
 
+	// PodOrdinalAnnotation is the operator's annotation for the StatefulSet ordinal of a pod
+	PodOrdinalAnnotation = "cassandra.datastax.com/pod-ordinal"
+
+	// RollingRestartAnnotation requests a rolling restart of the datacenter when set on it, its value
+	// being the timestamp of the request. A request is processed once, see LastRollingRestartAnnotation.
+	RollingRestartAnnotation = "cassandra.datastax.com/rolling-restart"
+
+	// CleanupAnnotation requests a nodetool cleanup of every node of the datacenter, one node at a time,
+	// when set on it, its value being the timestamp of the request. See LastCleanupAnnotation.
+	CleanupAnnotation = "cassandra.datastax.com/cleanup"
+
+	// ReconciledGenerationAnnotation records on a StatefulSet the generation of the datacenter, and of
+	// the StatefulSet itself, for which the StatefulSet was last found up to date
+	ReconciledGenerationAnnotation = "cassandra.datastax.com/reconciled-generation"
+
+	// ReplaceAddressRemovedAnnotation records on a StatefulSet the replace address removed from its
+	// pod template once the replaced nodes rejoined the ring
+	ReplaceAddressRemovedAnnotation = "cassandra.datastax.com/replace-address-removed"
+
+	// DecommissionAttemptsAnnotation records on a decommissioning pod how many decommission requests
+	// were sent to it, and DecommissionLastAttemptAnnotation when the last one was sent
+	DecommissionAttemptsAnnotation    = "cassandra.datastax.com/decommission-attempts"
+	DecommissionLastAttemptAnnotation = "cassandra.datastax.com/decommission-last-attempt"
+
+	// DecommissionJobAnnotation records on a decommissioning pod the id of its decommission job
+	DecommissionJobAnnotation = "cassandra.datastax.com/decommission-job"
+
+	// DetectedRacksAnnotation records on the datacenter the names of the racks detected by
+	// AutoDetectRacks, so that their addition can be told apart from a user edit
+	DetectedRacksAnnotation = "cassandra.datastax.com/detected-racks"
+
+	// VolumeOwnerAnnotation records on a server data PersistentVolume, and on its claim, the UID of
+	// the datacenter the volume was bound to
+	VolumeOwnerAnnotation = "cassandra.datastax.com/datacenter-uid"
+
 	// SkipUserCreationAnnotation tells the operator to skip creating any Cassandra users
 	// including the default superuser. This is for multi-dc deployments when adding a
 	// DC to an existing cluster where the superuser has already been created.
@@ -164,6 +206,11 @@ type CassandraDatacenterSpec struct {
 	// will re-attach when the CassandraDatacenter workload is resumed.
 	Stopped bool `json:"stopped,omitempty"`
 
+	// AutoDetectRacks creates one rack per zone of the Kubernetes worker nodes, using their
+	// topology.kubernetes.io/zone label, when no racks are defined. The racks are only detected
+	// before the datacenter is initialized, and are then kept in the racks field.
+	AutoDetectRacks bool `json:"autoDetectRacks,omitempty"`
+
 	// Container image for the config builder init container. Overrides value from ImageConfig ConfigBuilderImage
 	ConfigBuilderImage string `json:"configBuilderImage,omitempty"`
 
@@ -213,6 +260,12 @@ type CassandraDatacenterSpec struct {
 
 	AdditionalSeeds []string `json:"additionalSeeds,omitempty"`
 
+	// PruneStaleSeeds drops from the endpoints of the additional seed service the addresses added by
+	// other controllers for pods of the cluster in this namespace, once these pods are being deleted
+	// or run with another IP, as well as the duplicated addresses.
+	// +optional
+	PruneStaleSeeds bool `json:"pruneStaleSeeds,omitempty"`
+
 	// Configuration for disabling the simple log tailing sidecar container. Our default is to have it enabled.
 	DisableSystemLoggerSidecar bool `json:"disableSystemLoggerSidecar,omitempty"`
 
@@ -226,11 +279,196 @@ type CassandraDatacenterSpec struct {
 	// Tolerations applied to the Cassandra pod. Note that these cannot be overridden with PodTemplateSpec.
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
-	// Additional Labels allows to define additional labels that will be included in all objects created by the operator. Note, user can override values set by default from the cass-operator and doing so could break cass-operator functionality.
+	// Additional Labels allows to define additional labels that will be included in all objects created by the operator. The labels set by the operator
+	// take precedence on conflict. Labels removed from this map are removed from the statefulsets, pods and PVCs on the next reconcile.
 	AdditionalLabels map[string]string `json:"additionalLabels,omitempty"`
 
+	// AdditionalAnnotations are added to the statefulsets, their PVCs and the services created by the operator. The
+	// annotation prefixes cassandra.datastax.com and k8ssandra.io are reserved. Annotations removed from this map are
+	// removed from the resources on the next reconcile.
+	AdditionalAnnotations map[string]string `json:"additionalAnnotations,omitempty"`
+
 	// CDC allows configuration of the change data capture agent which can run within the Management API container. Use it to send data to Pulsar.
 	CDC *CDCConfiguration `json:"cdc,omitempty"`
+
+	// PostStartHook is an optional postStart lifecycle hook for the Cassandra container. It is set alongside
+	// the preStop hook the operator uses to drain the node, and is ignored if the PodTemplateSpec already
+	// defines a postStart hook for the container.
+	PostStartHook *corev1.LifecycleHandler `json:"postStartHook,omitempty"`
+
+	// Take a snapshot on the affected nodes before a scale down decommission or a server image upgrade.
+	// The name of the last snapshot taken is recorded in the status.
+	SnapshotBeforeDestructive bool `json:"snapshotBeforeDestructive,omitempty"`
+
+	// PodAnnotations are added to the Cassandra pods only, unlike AdditionalLabels they are not
+	// applied to the services or other objects created by the operator. Useful for example to
+	// control sidecar injection with sidecar.istio.io/inject.
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// AllowAutoscalerEviction lets the cluster autoscaler evict the Cassandra pods to scale down their
+	// nodes. By default the pods are annotated with cluster-autoscaler.kubernetes.io/safe-to-evict=false.
+	AllowAutoscalerEviction bool `json:"allowAutoscalerEviction,omitempty"`
+
+	// EnforceGuaranteedQoS sets the resource requests of the Cassandra container equal to its limits, which
+	// is required for the pod to get the Guaranteed QoS class (for example to use the static CPU manager policy).
+	// Limits must be set when this is enabled. Note that other containers of the pod need matching requests
+	// and limits as well for the pod to be Guaranteed.
+	EnforceGuaranteedQoS bool `json:"enforceGuaranteedQoS,omitempty"`
+
+	// Do a rolling restart when the superuser secret is modified, for setups where the credentials
+	// are passed to the pods through environment variables or files and are only read on startup.
+	RestartOnCredentialChange bool `json:"restartOnCredentialChange,omitempty"`
+
+	// Automatically tolerate the taints shared by all the nodes matching the node affinity labels
+	// of a rack, so that pods can be scheduled on dedicated tainted nodes without repeating the
+	// taints in Tolerations. The pods keep their tolerations while no node of the rack is found.
+	AutoDiscoverTolerations bool `json:"autoDiscoverTolerations,omitempty"`
+
+	// SeedServiceName overrides the name of the headless service resolving to the seed nodes, which
+	// defaults to <clusterName>-seed-service. Additional labels for the service can be set in
+	// AdditionalServiceConfig.SeedService.
+	SeedServiceName string `json:"seedServiceName,omitempty"`
+
+	// RebindReleasedVolumes recreates the PVC of a Cassandra node that was deleted while its
+	// PersistentVolume was retained, bound to that volume, instead of provisioning an empty one.
+	// Only the volumes recorded as bound to this datacenter are rebound. Only useful with a storage
+	// class using the Retain reclaim policy.
+	RebindReleasedVolumes bool `json:"rebindReleasedVolumes,omitempty"`
+
+	// ReclaimRemovedRackPVCs deletes the PVCs left behind by a rack that is no longer part of the
+	// datacenter, once none of its pods remain. Off by default, as the data of the rack is lost.
+	ReclaimRemovedRackPVCs bool `json:"reclaimRemovedRackPVCs,omitempty"`
+
+	// AutoCleanupAfterScaleUp runs a cleanup of the nodes, one at a time, once a scale up of the
+	// datacenter has completed, removing the data the existing nodes no longer own. The progress
+	// is tracked by a CassandraTask, see TrackedTasks in the status. Defaults to true.
+	// +optional
+	AutoCleanupAfterScaleUp *bool `json:"autoCleanupAfterScaleUp,omitempty"`
+
+	// SkipUnchangedStatefulSets skips building the desired StatefulSets while the generation of the
+	// datacenter is the one last reconciled and the StatefulSets weren't modified since. This saves CPU
+	// with many datacenters, but changes to the operator configuration are then only applied on the next
+	// change of the datacenter.
+	SkipUnchangedStatefulSets bool `json:"skipUnchangedStatefulSets,omitempty"`
+
+	// RemoveReplaceAddressAfterReplace removes the replace address (REPLACE_ADDRESS,
+	// REPLACE_ADDRESS_FIRST_BOOT or the cassandra.replace_address JVM options) from the pod template
+	// of a rack once all its nodes are up and normal, so that restarts don't attempt the replacement
+	// again. Removing it restarts the pods of the rack.
+	RemoveReplaceAddressAfterReplace bool `json:"removeReplaceAddressAfterReplace,omitempty"`
+
+	// HotReloadTLS applies the certificates rotated in the keystore secret without restarting the
+	// nodes, by reloading their SSL contexts through the management API. A rolling restart is done
+	// instead if the management API of a node doesn't support it. The change is applied two minutes
+	// after it is noticed, once the kubelet updated the secret mounted in the pods.
+	HotReloadTLS bool `json:"hotReloadTLS,omitempty"`
+
+	// GenerateClientConfigSecret creates a secret with the configuration for CQL clients once the
+	// datacenter is ready: the contact points, the port, the local datacenter, the superuser
+	// credentials and, if client encryption is enabled, the CA certificate.
+	GenerateClientConfigSecret bool `json:"generateClientConfigSecret,omitempty"`
+
+	// PodRebalancing spreads again the pods of each rack across the zones, and the pods that had to
+	// share a worker node, see AllowMultipleNodesPerWorker, once nodes they can be scheduled on are
+	// available
+	PodRebalancing *PodRebalancingConfig `json:"podRebalancing,omitempty"`
+
+	// ScaleCooldownSeconds is the time to wait after a scale operation finished before starting the
+	// next one, to let the ring stabilize. When scaling down, it applies between each decommissioned node.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ScaleCooldownSeconds int32 `json:"scaleCooldownSeconds,omitempty"`
+
+	// StrictRackBalance rejects a Size that is not a multiple of the number of racks, which
+	// leaves the racks with different node counts. Otherwise, only a warning is logged.
+	StrictRackBalance bool `json:"strictRackBalance,omitempty"`
+
+	// AnnotatePodOrdinals adds an annotation with the StatefulSet ordinal to the pods. With the rack
+	// annotation of the pods, it helps correlate them with their position in the ring when debugging.
+	AnnotatePodOrdinals bool `json:"annotatePodOrdinals,omitempty"`
+
+	// CreateNetworkPolicy creates a NetworkPolicy restricting the traffic to the datacenter pods. Internode
+	// traffic is allowed from the pods of the cluster and client traffic from the namespaces matching
+	// NetworkPolicyClientNamespaceLabels, or from the datacenter's namespace if unset. The management API
+	// and metrics ports stay open for the operator and the monitoring. Disabling it deletes the NetworkPolicy.
+	CreateNetworkPolicy bool `json:"createNetworkPolicy,omitempty"`
+
+	// NetworkPolicyClientNamespaceLabels selects the namespaces allowed to connect to the native port
+	// when CreateNetworkPolicy is enabled
+	// +optional
+	NetworkPolicyClientNamespaceLabels map[string]string `json:"networkPolicyClientNamespaceLabels,omitempty"`
+
+	// ClockSkewThresholdSeconds enables the comparison of the clocks of the nodes, through their management
+	// API, and sets the ClockSkewDetected condition when they differ by more than this amount of seconds.
+	// The clocks are read every five minutes with a precision of one second, so the condition is only
+	// set when they differ by two more seconds, and cleared once they are within the threshold.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ClockSkewThresholdSeconds int32 `json:"clockSkewThresholdSeconds,omitempty"`
+
+	// GossipHealthCheck withholds the Healthy condition until every started node sees the other nodes of
+	// the datacenter as alive and NORMAL in its gossip state. The GossipInconsistency condition names the
+	// node that doesn't, and the nodes it sees as unreachable.
+	// +optional
+	GossipHealthCheck bool `json:"gossipHealthCheck,omitempty"`
+
+	// SeedsPerRack is the number of seeds of each rack. When unset, the datacenter has one seed per 10
+	// nodes, up to 10, with at least 3 seeds and one seed per rack.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SeedsPerRack int32 `json:"seedsPerRack,omitempty"`
+
+	// ClusterFormationTimeoutSeconds sets the ClusterFormationTimeout condition when the first seed
+	// of a new cluster is not ready this amount of seconds after Cassandra was started on it, instead
+	// of waiting for it without any indication. The condition has the status of the pod.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ClusterFormationTimeoutSeconds int32 `json:"clusterFormationTimeoutSeconds,omitempty"`
+
+	// PodManagementPolicy of the rack StatefulSets. With Parallel, the default, all the pods of a rack
+	// are created at once. With OrderedReady, a pod is only created once the previous one is ready.
+	// Either way, the operator starts Cassandra on one node at a time, so that only one node
+	// bootstraps at once. Changing it recreates the StatefulSets, their pods are kept.
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	// +optional
+	PodManagementPolicy string `json:"podManagementPolicy,omitempty"`
+}
+
+// PodRebalancingConfig configures the rebalancing of the pods. During the maintenance window, a pod
+// is deleted so that it can be rescheduled on a node of its rack hosting no pod of the datacenter,
+// when its zone hosts at least two more pods of its rack than the zone of that node, or when it
+// shares a worker node and that node is in its zone. Pods are rebalanced one at a time, once all
+// the pods exist and are ready.
+// This is only useful when the data volumes are not bound to a node.
+type PodRebalancingConfig struct {
+	// Enabled turns on the rebalancing of the pods
+	Enabled bool `json:"enabled,omitempty"`
+
+	// WindowStartHour is the hour, in UTC, at which the maintenance window starts
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=23
+	// +optional
+	WindowStartHour int `json:"windowStartHour,omitempty"`
+
+	// WindowEndHour is the hour, in UTC, at which the maintenance window ends. The window goes
+	// past midnight when it is lower than WindowStartHour, and lasts the whole day when both are equal.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=23
+	// +optional
+	WindowEndHour int `json:"windowEndHour,omitempty"`
+}
+
+// InMaintenanceWindow returns true if pods can be rebalanced at the given time
+func (c *PodRebalancingConfig) InMaintenanceWindow(now time.Time) bool {
+	hour := now.UTC().Hour()
+	switch {
+	case c.WindowStartHour == c.WindowEndHour:
+		return true
+	case c.WindowStartHour < c.WindowEndHour:
+		return hour >= c.WindowStartHour && hour < c.WindowEndHour
+	default:
+		return hour >= c.WindowStartHour || hour < c.WindowEndHour
+	}
 }
 
 type NetworkingConfig struct {
@@ -261,7 +499,8 @@ type DseWorkloads struct {
 	SearchEnabled    bool `json:"searchEnabled,omitempty"`
 }
 
-// AdditionalVolumes StorageConfig defines additional storage configurations
+// AdditionalVolumes StorageConfig defines additional storage configurations, for example a
+// dedicated volume for the commitlog. Each one gets its own PVC per pod, deleted with the data PVC.
 type AdditionalVolumes struct {
 	// Mount path into cassandra container
 	MountPath string `json:"mountPath"`
@@ -277,6 +516,17 @@ type AdditionalVolumesSlice []AdditionalVolumes
 type StorageConfig struct {
 	CassandraDataVolumeClaimSpec *corev1.PersistentVolumeClaimSpec `json:"cassandraDataVolumeClaimSpec,omitempty"`
 	AdditionalVolumes            AdditionalVolumesSlice            `json:"additionalVolumes,omitempty"`
+
+	// FixPermissions adds an init container that changes the ownership of the data directory to
+	// the Cassandra user before the server starts. Use this with storage classes that do not
+	// honor the fsGroup of the pod. Like the rest of the StorageConfig, it can only be set when the
+	// datacenter is created.
+	FixPermissions bool `json:"fixPermissions,omitempty"`
+
+	// UseEphemeralStorage marks the data of the datacenter as disposable, for example in test
+	// environments. Deleting such a datacenter skips the graceful decommission of the nodes
+	// and removes the StatefulSets immediately. It can only be set when the datacenter is created.
+	UseEphemeralStorage bool `json:"useEphemeralStorage,omitempty"`
 }
 
 // GetRacks is a getter for the Rack slice in the spec
@@ -317,6 +567,11 @@ type Rack struct {
 
 	//NodeAffinityLabels to pin the rack, using node affinity
 	NodeAffinityLabels map[string]string `json:"nodeAffinityLabels,omitempty"`
+
+	// Paused stops the operator from modifying the rack's StatefulSet (scaling, updates and
+	// decommissions), so that the rack can be manually intervened on. Other racks are
+	// reconciled normally. Listing the rack in ForceUpgradeRacks still updates it.
+	Paused bool `json:"paused,omitempty"`
 }
 
 type CassandraNodeStatus struct {
@@ -325,6 +580,70 @@ type CassandraNodeStatus struct {
 
 type CassandraStatusMap map[string]CassandraNodeStatus
 
+type ManagedObjectCounts struct {
+	StatefulSets           int `json:"statefulSets"`
+	Services               int `json:"services"`
+	PersistentVolumeClaims int `json:"persistentVolumeClaims"`
+}
+
+// RackPhase summarizes the state of the nodes of a rack
+type RackPhase string
+
+const (
+	RackPhaseReady       RackPhase = "Ready"
+	RackPhasePending     RackPhase = "Pending"
+	RackPhaseScalingDown RackPhase = "ScalingDown"
+	RackPhaseDown        RackPhase = "Down"
+)
+
+type RackStatus struct {
+	Name string `json:"name"`
+
+	// Number of Cassandra nodes desired in the rack
+	DesiredNodes int `json:"desiredNodes"`
+
+	// Number of Cassandra nodes of the rack that are ready
+	ReadyNodes int `json:"readyNodes"`
+
+	Phase RackPhase `json:"phase,omitempty"`
+
+	// ObservedImage is the image of the cassandra container of the pods of the rack. While the pods
+	// run different images, during an upgrade, it is one that is not the ServerImage of the status.
+	// +optional
+	ObservedImage string `json:"observedImage,omitempty"`
+}
+
+// RollingOperationType is the kind of operation replacing the pods of the datacenter one at a time
+type RollingOperationType string
+
+const (
+	// RollingOperationRestart is a rolling restart of the pods
+	RollingOperationRestart RollingOperationType = "restart"
+	// RollingOperationUpgrade is the replacement of the pods with an updated pod template
+	RollingOperationUpgrade RollingOperationType = "upgrade"
+)
+
+// RollingOperation describes the rolling operation in progress
+type RollingOperation struct {
+	Type RollingOperationType `json:"type"`
+
+	// Pod is the name of the pod being processed
+	// +optional
+	Pod string `json:"pod,omitempty"`
+}
+
+// MaxReconcileErrors is the number of failed reconciles kept in the status
+const MaxReconcileErrors = 10
+
+// ReconcileError is a reconcile of the datacenter that returned an error
+type ReconcileError struct {
+	// Time is when the reconcile failed
+	Time metav1.Time `json:"time"`
+
+	// Message is the error returned by the reconcile
+	Message string `json:"message"`
+}
+
 type DatacenterConditionType string
 
 const (
@@ -340,6 +659,77 @@ const (
 	DatacenterValid          DatacenterConditionType = "Valid"
 	DatacenterDecommission   DatacenterConditionType = "Decommission"
 
+	// DatacenterRecoveringSeeds indicates that the pods labelled as seeds did not overlap with
+	// the seeds the operator would pick, and the seed labels are being re-converged.
+	DatacenterRecoveringSeeds DatacenterConditionType = "RecoveringSeeds"
+
+	// DatacenterConfigBuilderFailed indicates that the server config init container failed on at
+	// least one pod, which then never starts Cassandra. The message has the termination message
+	// of the init container.
+	DatacenterConfigBuilderFailed DatacenterConditionType = "ConfigBuilderFailed"
+
+	// DatacenterImagePullFailed indicates that the image of a container could not be pulled on at
+	// least one pod. The message names the pod and the image.
+	DatacenterImagePullFailed DatacenterConditionType = "ImagePullFailed"
+
+	// DatacenterCassandraOOMKilled indicates that the cassandra container of at least one pod was
+	// recently killed for running out of memory. The message names the pod.
+	DatacenterCassandraOOMKilled DatacenterConditionType = "CassandraOOMKilled"
+
+	// DatacenterPodEvictedDiskPressure indicates that at least one pod was evicted by the kubelet
+	// because its node ran low on disk. The message names the pod and the eviction reason.
+	DatacenterPodEvictedDiskPressure DatacenterConditionType = "PodEvictedDiskPressure"
+
+	// DatacenterSchedulingDeadlock indicates that a pod can't be scheduled on any node of its rack
+	// while its WaitForFirstConsumer volume waits for the pod. The message names the pod and what
+	// would unblock it.
+	DatacenterSchedulingDeadlock DatacenterConditionType = "SchedulingDeadlock"
+
+	// DatacenterResizingVolumes indicates that the server data PVCs are being expanded to the size
+	// requested in the StorageConfig. The message names the PVCs whose capacity is not updated yet.
+	DatacenterResizingVolumes DatacenterConditionType = "ResizingVolumes"
+
+	// DatacenterVolumeExpansionNotAllowed indicates that the storage class of some server data PVCs
+	// does not allow volume expansion, they keep their size. The message names the PVCs.
+	DatacenterVolumeExpansionNotAllowed DatacenterConditionType = "VolumeExpansionNotAllowed"
+
+	// DatacenterManagementApiUnreachable indicates that a lifecycle command was not issued because
+	// the management API of the pod could not be reached. The message names the pod.
+	DatacenterManagementApiUnreachable DatacenterConditionType = "ManagementApiUnreachable"
+
+	// DatacenterDecommissionFailed indicates that the decommission of a node still failed after
+	// being retried. The message names the pod and the last error.
+	DatacenterDecommissionFailed DatacenterConditionType = "DecommissionFailed"
+
+	// DatacenterScaleDownRefused indicates that the scale down would leave fewer nodes than the
+	// replication factor of a keyspace. It is not retried until the size is changed, the message names
+	// the keyspace.
+	DatacenterScaleDownRefused DatacenterConditionType = "ScaleDownRefused"
+
+	// DatacenterClockSkewDetected indicates that the clocks of the nodes differ by more than
+	// ClockSkewThresholdSeconds. The message names the pods with the most distant clocks.
+	DatacenterClockSkewDetected DatacenterConditionType = "ClockSkewDetected"
+
+	// DatacenterRackDown indicates that none of the pods of at least one rack are ready. The
+	// message names the racks that are down. The other racks keep being reconciled.
+	DatacenterRackDown DatacenterConditionType = "RackDown"
+
+	// DatacenterUnschedulableRacks indicates that the scheduler can't place a pod of at least one
+	// rack, usually because no node matches its node affinity labels. The message names the racks.
+	DatacenterUnschedulableRacks DatacenterConditionType = "UnschedulableRacks"
+
+	// DatacenterGossipInconsistency indicates that a node doesn't see another node of the datacenter
+	// as alive and NORMAL, see GossipHealthCheck. The message names the nodes.
+	DatacenterGossipInconsistency DatacenterConditionType = "GossipInconsistency"
+
+	// DatacenterClusterFormationTimeout indicates that the first seed of a new cluster is not ready
+	// ClusterFormationTimeoutSeconds after being started. The message has the status of the pod.
+	DatacenterClusterFormationTimeout DatacenterConditionType = "ClusterFormationTimeout"
+
+	// DatacenterUnsupportedAccessModes indicates that the server data volumes use access modes
+	// Cassandra doesn't support, the message lists them. It doesn't stop the reconciliation.
+	DatacenterUnsupportedAccessModes DatacenterConditionType = "UnsupportedAccessModes"
+
 	// DatacenterHealthy indicates if QUORUM can be reached from all deployed nodes.
 	// If this check fails, certain operations such as scaling up will not proceed.
 	DatacenterHealthy DatacenterConditionType = "Healthy"
@@ -399,6 +789,25 @@ type CassandraDatacenterStatus struct {
 	// +optional
 	LastRollingRestart metav1.Time `json:"lastRollingRestart,omitempty"`
 
+	// LastRollingRestartAnnotation is the value of the rolling-restart annotation for which the last
+	// rolling restart was requested
+	// +optional
+	LastRollingRestartAnnotation string `json:"lastRollingRestartAnnotation,omitempty"`
+
+	// LastCleanupAnnotation is the value of the cleanup annotation for which the last cleanup
+	// was requested
+	// +optional
+	LastCleanupAnnotation string `json:"lastCleanupAnnotation,omitempty"`
+
+	// The timestamp when the last scale operation finished, used to enforce ScaleCooldownSeconds
+	// +optional
+	LastScaleOperation metav1.Time `json:"lastScaleOperation,omitempty"`
+
+	// The timestamp of the last reconcile that completed without error. It is refreshed at most
+	// once a minute. A stale value can indicate that the operator is stuck.
+	// +optional
+	LastReconciledTime metav1.Time `json:"lastReconciledTime,omitempty"`
+
 	// +optional
 	NodeStatuses CassandraStatusMap `json:"nodeStatuses"`
 
@@ -414,6 +823,89 @@ type CassandraDatacenterStatus struct {
 	// TrackedTasks tracks the tasks for completion that were created by the cass-operator
 	// +optional
 	TrackedTasks []corev1.ObjectReference `json:"trackedTasks,omitempty"`
+
+	// LastSnapshotName is the name of the last snapshot taken before a decommission or upgrade
+	// when SnapshotBeforeDestructive is enabled
+	// +optional
+	LastSnapshotName string `json:"lastSnapshotName,omitempty"`
+
+	// SuperuserSecretResourceVersion is the last seen resourceVersion of the superuser secret
+	// when RestartOnCredentialChange is enabled
+	// +optional
+	SuperuserSecretResourceVersion string `json:"superuserSecretResourceVersion,omitempty"`
+
+	// KeystoreSecretResourceVersion is the last seen resourceVersion of the keystore secret
+	// when HotReloadTLS is enabled
+	// +optional
+	KeystoreSecretResourceVersion string `json:"keystoreSecretResourceVersion,omitempty"`
+
+	// KeystoreSecretReloadTime is when the nodes apply the last change of the keystore secret, once
+	// the kubelet had the time to update the secret mounted in the pods
+	// +optional
+	KeystoreSecretReloadTime metav1.Time `json:"keystoreSecretReloadTime,omitempty"`
+
+	// ProgressPercent is the percentage of the desired nodes that are ready and running
+	// the latest pod template
+	// +optional
+	ProgressPercent int `json:"progressPercent,omitempty"`
+
+	// RackStatuses contains the desired and ready node counts of each rack
+	// +optional
+	RackStatuses []RackStatus `json:"rackStatuses,omitempty"`
+
+	// ServerImage is the image of the cassandra container the pods are desired to run, derived from
+	// the serverImage or the serverType and serverVersion. Compare it with the ObservedImage of the
+	// RackStatuses to follow an upgrade.
+	// +optional
+	ServerImage string `json:"serverImage,omitempty"`
+
+	// ManagedObjects has the number of objects of each kind the operator manages for the datacenter
+	// +optional
+	ManagedObjects ManagedObjectCounts `json:"managedObjects,omitempty"`
+
+	// UnhealthyPods has the names of the pods that are not ready, crash looping or running on
+	// a node that is not ready
+	// +optional
+	UnhealthyPods []string `json:"unhealthyPods,omitempty"`
+
+	// ConfigHash is the hash of the current server configuration. Pods with a different
+	// config-hash annotation are pending a restart to pick up the configuration.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// DecommissioningNode is the name of the pod being decommissioned during a scale down
+	// +optional
+	DecommissioningNode string `json:"decommissioningNode,omitempty"`
+
+	// RollingOperation is the restart or upgrade in progress, it is cleared once the operation is done
+	// +optional
+	RollingOperation *RollingOperation `json:"rollingOperation,omitempty"`
+
+	// ReconcileErrors has the last failed reconciles, oldest first, so that intermittent failures
+	// are visible without going through the operator logs. At most MaxReconcileErrors are kept.
+	// +optional
+	ReconcileErrors []ReconcileError `json:"reconcileErrors,omitempty"`
+
+	// AdditionalLabelKeys are the keys of the additional labels applied to the managed resources,
+	// used to remove the labels that are dropped from the spec
+	// +optional
+	AdditionalLabelKeys []string `json:"additionalLabelKeys,omitempty"`
+
+	// AdditionalAnnotationKeys are the keys of the additional annotations applied to the managed
+	// resources, used to remove the annotations that are dropped from the spec
+	// +optional
+	AdditionalAnnotationKeys []string `json:"additionalAnnotationKeys,omitempty"`
+
+	// MaxReplicationFactor is the highest replication factor of the keyspaces in this datacenter,
+	// as last read by the operator before decommissioning a node. The size can't be decreased below it.
+	// +optional
+	MaxReplicationFactor int32 `json:"maxReplicationFactor,omitempty"`
+
+	// ConfigBuilderFeatures are the features supported by the config builder image of the pods, such as
+	// encryption and custom-config-sections, according to the version in its tag. It is informational,
+	// the operator renders the same configuration whatever the image.
+	// +optional
+	ConfigBuilderFeatures []string `json:"configBuilderFeatures,omitempty"`
 }
 
 // CassandraDatacenter is the Schema for the cassandradatacenters API
@@ -445,6 +937,32 @@ type ManagementApiAuthConfig struct {
 	// other strategy configs (e.g. Cert Manager) go here
 }
 
+var (
+	ErrManagementApiAuthBothModes     = errors.New("both insecure and manual managementApiAuth")
+	ErrManagementApiAuthNoMode        = errors.New("no managementApiAuth mode")
+	ErrManagementApiAuthManualSecrets = errors.New("manual managementApiAuth without client and server secret names")
+)
+
+// IsEmpty returns true if no authentication mode is configured, which the operator treats as Insecure
+func (c ManagementApiAuthConfig) IsEmpty() bool {
+	return c.Insecure == nil && c.Manual == nil
+}
+
+// Validate checks that exactly one authentication mode is configured, and that a Manual mode names
+// its secrets
+func (c ManagementApiAuthConfig) Validate() error {
+	if c.Insecure != nil && c.Manual != nil {
+		return ErrManagementApiAuthBothModes
+	}
+	if c.IsEmpty() {
+		return ErrManagementApiAuthNoMode
+	}
+	if c.Manual != nil && (c.Manual.ClientSecretName == "" || c.Manual.ServerSecretName == "") {
+		return ErrManagementApiAuthManualSecrets
+	}
+	return nil
+}
+
 //+kubebuilder:object:root=true
 
 // CassandraDatacenterList contains a list of CassandraDatacenter
@@ -498,6 +1016,14 @@ func (status *CassandraDatacenterStatus) AddTaskToTrack(objectMeta metav1.Object
 	})
 }
 
+// AddReconcileError records a failed reconcile, evicting the oldest ones past MaxReconcileErrors
+func (status *CassandraDatacenterStatus) AddReconcileError(message string, time metav1.Time) {
+	status.ReconcileErrors = append(status.ReconcileErrors, ReconcileError{Time: time, Message: message})
+	if overflow := len(status.ReconcileErrors) - MaxReconcileErrors; overflow > 0 {
+		status.ReconcileErrors = append([]ReconcileError(nil), status.ReconcileErrors[overflow:]...)
+	}
+}
+
 func (status *CassandraDatacenterStatus) RemoveTrackedTask(objectMeta metav1.ObjectMeta) {
 	for index, task := range status.TrackedTasks {
 		if task.Name == objectMeta.Name && task.Namespace == objectMeta.Namespace {
@@ -510,6 +1036,11 @@ func (dc *CassandraDatacenter) GetConditionStatus(conditionType DatacenterCondit
 	return (&dc.Status).GetConditionStatus(conditionType)
 }
 
+// RollingOperationInProgress returns true if a rolling restart or upgrade of the pods is in progress
+func (dc *CassandraDatacenter) RollingOperationInProgress() bool {
+	return dc.Status.RollingOperation != nil
+}
+
 func (dc *CassandraDatacenter) GetCondition(conditionType DatacenterConditionType) (DatacenterCondition, bool) {
 	for _, condition := range dc.Status.Conditions {
 		if condition.Type == conditionType {
@@ -520,17 +1051,27 @@ func (dc *CassandraDatacenter) GetCondition(conditionType DatacenterConditionTyp
 	return DatacenterCondition{}, false
 }
 
+// SetCondition adds or replaces the condition of the same type. The LastTransitionTime only
+// changes when the status of the condition does, it's set to now if the new condition has none.
 func (status *CassandraDatacenterStatus) SetCondition(condition DatacenterCondition) {
 	conditions := status.Conditions
 	added := false
 	for i := range status.Conditions {
 		if status.Conditions[i].Type == condition.Type {
+			if status.Conditions[i].Status == condition.Status {
+				condition.LastTransitionTime = status.Conditions[i].LastTransitionTime
+			} else if condition.LastTransitionTime.IsZero() {
+				condition.LastTransitionTime = metav1.Now()
+			}
 			status.Conditions[i] = condition
 			added = true
 		}
 	}
 
 	if !added {
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.Now()
+		}
 		conditions = append(conditions, condition)
 	}
 
@@ -584,6 +1125,9 @@ func CleanupForKubernetes(input string) string {
 }
 
 func (dc *CassandraDatacenter) GetSeedServiceName() string {
+	if dc.Spec.SeedServiceName != "" {
+		return dc.Spec.SeedServiceName
+	}
 	return CleanupForKubernetes(dc.Spec.ClusterName) + "-seed-service"
 }
 
@@ -599,10 +1143,19 @@ func (dc *CassandraDatacenter) GetDatacenterServiceName() string {
 	return CleanupForKubernetes(dc.Spec.ClusterName) + "-" + dc.Name + "-service"
 }
 
+func (dc *CassandraDatacenter) GetClientConfigSecretName() string {
+	return CleanupForKubernetes(dc.Spec.ClusterName) + "-" + dc.Name + "-client-config"
+}
+
 func (dc *CassandraDatacenter) GetNodePortServiceName() string {
 	return CleanupForKubernetes(dc.Spec.ClusterName) + "-" + dc.Name + "-node-port-service"
 }
 
+// ShouldCleanupAfterScaleUp returns true unless AutoCleanupAfterScaleUp is explicitly disabled
+func (dc *CassandraDatacenter) ShouldCleanupAfterScaleUp() bool {
+	return dc.Spec.AutoCleanupAfterScaleUp == nil || *dc.Spec.AutoCleanupAfterScaleUp
+}
+
 func (dc *CassandraDatacenter) ShouldGenerateSuperuserSecret() bool {
 	return len(dc.Spec.SuperuserSecretName) == 0
 }
@@ -832,6 +1385,31 @@ func (dc *CassandraDatacenter) FullQueryEnabled() (bool, error) {
 	return false, nil
 }
 
+// ClientEncryptionEnabled returns true if client_encryption_options are enabled in the cassandra-yaml
+func (dc *CassandraDatacenter) ClientEncryptionEnabled() (bool, error) {
+	if dc.Spec.Config != nil {
+		var dcConfig map[string]interface{}
+		if err := json.Unmarshal(dc.Spec.Config, &dcConfig); err != nil {
+			return false, err
+		}
+		casYaml, found := dcConfig["cassandra-yaml"]
+		if !found {
+			return false, nil
+		}
+		casYamlMap, ok := casYaml.(map[string]interface{})
+		if !ok {
+			err := fmt.Errorf("failed to parse cassandra-yaml")
+			return false, err
+		}
+		if options, ok := casYamlMap["client_encryption_options"].(map[string]interface{}); ok {
+			enabled, _ := options["enabled"].(bool)
+			return enabled, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (dc *CassandraDatacenter) DeploymentSupportsFQL() bool {
 	serverMajorVersion, err := strconv.ParseInt(strings.Split(dc.Spec.ServerVersion, ".")[0], 10, 8)
 	if err != nil {
@@ -845,6 +1423,15 @@ func (dc *CassandraDatacenter) DeploymentSupportsFQL() bool {
 	return true
 }
 
+// ReplicasPerRack returns the number of nodes of each rack when size nodes are spread over
+// rackCount racks. When size is not a multiple of rackCount, the first racks get one more node.
+func ReplicasPerRack(size int, rackCount int) []int {
+	if rackCount <= 0 {
+		return nil
+	}
+	return SplitRacks(size, rackCount)
+}
+
 func SplitRacks(nodeCount, rackCount int) []int {
 	nodesPerRack, extraNodes := nodeCount/rackCount, nodeCount%rackCount
 
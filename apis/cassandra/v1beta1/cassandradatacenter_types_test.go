@@ -0,0 +1,99 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package v1beta1
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetCondition_LastTransitionTime(t *testing.T) {
+	dc := &CassandraDatacenter{}
+
+	_, found := dc.GetCondition(DatacenterReady)
+	assert.False(t, found)
+	assert.Equal(t, corev1.ConditionUnknown, dc.GetConditionStatus(DatacenterReady))
+
+	// A new condition without a transition time gets one
+	dc.SetCondition(DatacenterCondition{Type: DatacenterReady, Status: corev1.ConditionFalse})
+	condition, found := dc.GetCondition(DatacenterReady)
+	assert.True(t, found)
+	assert.False(t, condition.LastTransitionTime.IsZero())
+
+	transitioned := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	dc.Status.Conditions[0].LastTransitionTime = transitioned
+
+	// Same status, the reason and message are updated but not the transition time
+	dc.SetCondition(*NewDatacenterConditionWithReason(DatacenterReady, corev1.ConditionFalse, "Starting", "waiting for the pods"))
+	condition, _ = dc.GetCondition(DatacenterReady)
+	assert.Equal(t, "Starting", condition.Reason)
+	assert.Equal(t, "waiting for the pods", condition.Message)
+	assert.Equal(t, transitioned, condition.LastTransitionTime)
+
+	// The status changes, so does the transition time
+	dc.SetCondition(*NewDatacenterCondition(DatacenterReady, corev1.ConditionTrue))
+	condition, _ = dc.GetCondition(DatacenterReady)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.True(t, condition.LastTransitionTime.After(transitioned.Time))
+	assert.Len(t, dc.Status.Conditions, 1)
+}
+
+func TestAddReconcileError(t *testing.T) {
+	status := &CassandraDatacenterStatus{}
+	start := time.Now().Truncate(time.Second)
+
+	for i := 0; i < MaxReconcileErrors; i++ {
+		status.AddReconcileError(fmt.Sprintf("error %d", i), metav1.NewTime(start.Add(time.Duration(i)*time.Second)))
+	}
+	assert.Len(t, status.ReconcileErrors, MaxReconcileErrors)
+	assert.Equal(t, "error 0", status.ReconcileErrors[0].Message)
+
+	// Past the cap, the oldest errors are evicted
+	status.AddReconcileError("error 10", metav1.NewTime(start.Add(10*time.Second)))
+	status.AddReconcileError("error 11", metav1.NewTime(start.Add(11*time.Second)))
+	assert.Len(t, status.ReconcileErrors, MaxReconcileErrors)
+	assert.Equal(t, "error 2", status.ReconcileErrors[0].Message)
+	assert.Equal(t, metav1.NewTime(start.Add(2*time.Second)), status.ReconcileErrors[0].Time)
+	assert.Equal(t, "error 11", status.ReconcileErrors[MaxReconcileErrors-1].Message)
+}
+
+func TestManagementApiAuthConfig_Validate(t *testing.T) {
+	manual := &ManagementApiAuthManualConfig{
+		ClientSecretName: "mgmt-api-client",
+		ServerSecretName: "mgmt-api-server",
+	}
+
+	tests := []struct {
+		name   string
+		config ManagementApiAuthConfig
+		err    error
+	}{
+		{"none set", ManagementApiAuthConfig{}, ErrManagementApiAuthNoMode},
+		{"both set", ManagementApiAuthConfig{Insecure: &ManagementApiAuthInsecureConfig{}, Manual: manual}, ErrManagementApiAuthBothModes},
+		{"insecure only", ManagementApiAuthConfig{Insecure: &ManagementApiAuthInsecureConfig{}}, nil},
+		{"manual only", ManagementApiAuthConfig{Manual: manual}, nil},
+		{"manual without secrets", ManagementApiAuthConfig{Manual: &ManagementApiAuthManualConfig{ClientSecretName: "mgmt-api-client"}}, ErrManagementApiAuthManualSecrets},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, tt.config.Validate())
+		})
+	}
+
+	// The webhook accepts an empty config, the operator defaults it to insecure
+	dc := CassandraDatacenter{Spec: CassandraDatacenterSpec{ServerType: "cassandra", ServerVersion: "4.0.1"}}
+	assert.True(t, dc.Spec.ManagementApiAuth.IsEmpty())
+	assert.NoError(t, ValidateSingleDatacenter(dc))
+
+	dc.Spec.ManagementApiAuth.Manual = &ManagementApiAuthManualConfig{}
+	assert.EqualError(t, ValidateSingleDatacenter(dc),
+		"CassandraDatacenter write rejected, attempted to use manual managementApiAuth without client and server secret names")
+}
@@ -20,12 +20,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"reflect"
 	"strings"
 
 	"github.com/k8ssandra/cass-operator/pkg/images"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -119,6 +122,25 @@ func ValidateSingleDatacenter(dc CassandraDatacenter) error {
 		}
 	}
 
+	// An empty managementApiAuth keeps the default, insecure mode
+	if auth := dc.Spec.ManagementApiAuth; !auth.IsEmpty() {
+		if err := auth.Validate(); err != nil {
+			return attemptedTo("use %s", err.Error())
+		}
+	}
+
+	switch dc.Spec.PodManagementPolicy {
+	case "", "OrderedReady", "Parallel":
+	default:
+		return attemptedTo("use unsupported podManagementPolicy '%s'", dc.Spec.PodManagementPolicy)
+	}
+
+	if dc.Spec.SeedServiceName != "" {
+		if errs := validation.IsDNS1035Label(dc.Spec.SeedServiceName); len(errs) > 0 {
+			return attemptedTo("use invalid seedServiceName '%s'", dc.Spec.SeedServiceName)
+		}
+	}
+
 	if err := ValidateServiceLabelsAndAnnotations(dc); err != nil {
 		return err
 	}
@@ -126,6 +148,193 @@ func ValidateSingleDatacenter(dc CassandraDatacenter) error {
 	return ValidateFQLConfig(dc)
 }
 
+// reservedVolumes are the volumes of the server container, with their mount paths, that the
+// additional volumes can not replace
+var reservedVolumes = map[string]string{
+	"server-data":             "/var/lib/cassandra",
+	"server-config":           "/config",
+	"server-logs":             "/var/log/cassandra",
+	"encryption-cred-storage": "/etc/encryption/",
+}
+
+// ValidateAdditionalVolumes checks that the additional volumes of the storage config, such as a
+// dedicated commitlog volume, have unique names and mount paths that do not clash with the
+// volumes of the server container
+func ValidateAdditionalVolumes(dc CassandraDatacenter) error {
+	names := map[string]bool{}
+	mountPaths := map[string]bool{}
+	for _, mountPath := range reservedVolumes {
+		mountPaths[path.Clean(mountPath)] = true
+	}
+
+	for _, volume := range dc.Spec.StorageConfig.AdditionalVolumes {
+		if _, found := reservedVolumes[volume.Name]; found {
+			return attemptedTo("use reserved additional volume name '%s'", volume.Name)
+		}
+		if names[volume.Name] {
+			return attemptedTo("use additional volume name '%s' more than once", volume.Name)
+		}
+		names[volume.Name] = true
+
+		mountPath := path.Clean(volume.MountPath)
+		if !path.IsAbs(mountPath) {
+			return attemptedTo("mount additional volume '%s' at relative path '%s'", volume.Name, volume.MountPath)
+		}
+		if mountPaths[mountPath] {
+			return attemptedTo("mount additional volume '%s' at '%s', which is already in use", volume.Name, volume.MountPath)
+		}
+		mountPaths[mountPath] = true
+	}
+	return nil
+}
+
+// zoneLabels are the node labels holding the zone of the node
+var zoneLabels = []string{"topology.kubernetes.io/zone", "failure-domain.beta.kubernetes.io/zone"}
+
+// rackZone returns the zone the rack is pinned to with its node affinity labels, if any
+func rackZone(dc CassandraDatacenter, rack Rack) string {
+	if rack.Zone != "" {
+		return rack.Zone
+	}
+	for _, label := range zoneLabels {
+		if zone, found := rack.NodeAffinityLabels[label]; found {
+			return zone
+		}
+		if zone, found := dc.Spec.NodeAffinityLabels[label]; found {
+			return zone
+		}
+	}
+	return ""
+}
+
+// ValidateRackZones checks that racks pinned to zones are pinned to distinct zones, as racks
+// sharing a zone don't tolerate the loss of that zone. Racks that are not pinned are ignored.
+func ValidateRackZones(dc CassandraDatacenter) error {
+	zonedRacks := 0
+	zones := map[string]bool{}
+	for _, rack := range dc.GetRacks() {
+		if zone := rackZone(dc, rack); zone != "" {
+			zonedRacks++
+			zones[zone] = true
+		}
+	}
+
+	if len(zones) < zonedRacks {
+		return attemptedTo("pin %d racks to only %d distinct zones", zonedRacks, len(zones))
+	}
+	return nil
+}
+
+// ValidateNewOrChangedFields checks the fields of a new datacenter, oldDc being nil, or the fields
+// changed by an update. A datacenter accepted before these checks were added can still be updated
+// as long as those fields are left alone.
+func ValidateNewOrChangedFields(oldDc *CassandraDatacenter, newDc CassandraDatacenter) error {
+	racksChanged := oldDc == nil || !reflect.DeepEqual(oldDc.Spec.Racks, newDc.Spec.Racks) ||
+		!reflect.DeepEqual(oldDc.Spec.NodeAffinityLabels, newDc.Spec.NodeAffinityLabels)
+
+	if racksChanged {
+		if err := ValidateRackZones(newDc); err != nil {
+			return err
+		}
+	}
+
+	if oldDc == nil || !reflect.DeepEqual(oldDc.Spec.StorageConfig.AdditionalVolumes, newDc.Spec.StorageConfig.AdditionalVolumes) {
+		if err := ValidateAdditionalVolumes(newDc); err != nil {
+			return err
+		}
+	}
+
+	if oldDc == nil || podTemplateServerImage(*oldDc) != podTemplateServerImage(newDc) {
+		if err := ValidateServerImageNotOverridden(newDc); err != nil {
+			return err
+		}
+	} else if image := podTemplateServerImage(newDc); image != "" {
+		log.Info("podTemplateSpec sets the image of the server container, which overrides serverImage and serverVersion",
+			"datacenter", newDc.Name, "image", image)
+	}
+
+	if racksChanged || oldDc.Spec.Size != newDc.Spec.Size || oldDc.Spec.StrictRackBalance != newDc.Spec.StrictRackBalance {
+		if err := ValidateRackBalance(newDc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateRackBalance checks that the nodes can be evenly split across the racks. An uneven
+// split is only rejected when StrictRackBalance is set, otherwise a warning is logged.
+func ValidateRackBalance(dc CassandraDatacenter) error {
+	rackCount := len(dc.GetRacks())
+	if int(dc.Spec.Size)%rackCount == 0 {
+		return nil
+	}
+
+	replicasPerRack := ReplicasPerRack(int(dc.Spec.Size), rackCount)
+	if dc.Spec.StrictRackBalance {
+		return attemptedTo("use size %d which is not a multiple of the %d racks, nodes per rack would be %v",
+			dc.Spec.Size, rackCount, replicasPerRack)
+	}
+
+	log.Info("size is not a multiple of the number of racks, the racks will have different node counts",
+		"datacenter", dc.Name, "size", dc.Spec.Size, "racks", rackCount, "nodesPerRack", replicasPerRack)
+	return nil
+}
+
+// MinimumSafeSize returns the smallest size the datacenter can be scaled down to: every rack keeps
+// a node, and no keyspace is left with a replication factor higher than the number of nodes
+func MinimumSafeSize(dc CassandraDatacenter) int32 {
+	minimum := int32(len(dc.GetRacks()))
+	if dc.Status.MaxReplicationFactor > minimum {
+		minimum = dc.Status.MaxReplicationFactor
+	}
+	return minimum
+}
+
+// ValidateSizeDecrease rejects reducing the size below MinimumSafeSize, the nodes would not be
+// decommissioned anyway
+func ValidateSizeDecrease(oldDc, newDc CassandraDatacenter) error {
+	if newDc.Spec.Size >= oldDc.Spec.Size {
+		return nil
+	}
+
+	if minimum := MinimumSafeSize(oldDc); newDc.Spec.Size < minimum {
+		return attemptedTo("decrease size to %d, below the minimum of %d nodes that can be safely kept", newDc.Spec.Size, minimum)
+	}
+	return nil
+}
+
+// validateStorageConfigChange disallows StorageConfig changes, except growing the storage request
+// of the server data volume: the operator expands the existing claims when their storage class
+// allows it.
+func validateStorageConfigChange(oldConfig, newConfig StorageConfig) error {
+	oldSpec, newSpec := oldConfig.CassandraDataVolumeClaimSpec, newConfig.CassandraDataVolumeClaimSpec
+	if oldSpec != nil && newSpec != nil {
+		oldSize := oldSpec.Resources.Requests[corev1.ResourceStorage]
+		newSize := newSpec.Resources.Requests[corev1.ResourceStorage]
+		if newSize.Cmp(oldSize) < 0 {
+			return attemptedTo("shrink storageConfig.cassandraDataVolumeClaimSpec")
+		}
+
+		oldConfig, newConfig = *oldConfig.DeepCopy(), *newConfig.DeepCopy()
+		delete(oldConfig.CassandraDataVolumeClaimSpec.Resources.Requests, corev1.ResourceStorage)
+		delete(newConfig.CassandraDataVolumeClaimSpec.Resources.Requests, corev1.ResourceStorage)
+	}
+
+	if !reflect.DeepEqual(oldConfig, newConfig) {
+		return attemptedTo("change storageConfig")
+	}
+	return nil
+}
+
+func rackNames(racks []Rack) []string {
+	names := make([]string, 0, len(racks))
+	for _, rack := range racks {
+		names = append(names, rack.Name)
+	}
+	return names
+}
+
 // ValidateDatacenterFieldChanges checks that no values are improperly changing while updating
 // a CassandraDatacenter
 func ValidateDatacenterFieldChanges(oldDc CassandraDatacenter, newDc CassandraDatacenter) error {
@@ -146,9 +355,12 @@ func ValidateDatacenterFieldChanges(oldDc CassandraDatacenter, newDc CassandraDa
 		return attemptedTo("change serviceAccount")
 	}
 
-	// StorageConfig changes are disallowed
-	if !reflect.DeepEqual(oldDc.Spec.StorageConfig, newDc.Spec.StorageConfig) {
-		return attemptedTo("change storageConfig")
+	if err := validateStorageConfigChange(oldDc.Spec.StorageConfig, newDc.Spec.StorageConfig); err != nil {
+		return err
+	}
+
+	if err := ValidateSizeDecrease(oldDc, newDc); err != nil {
+		return err
 	}
 
 	// Topology changes - Racks
@@ -160,12 +372,22 @@ func ValidateDatacenterFieldChanges(oldDc CassandraDatacenter, newDc CassandraDa
 	oldRacks := oldDc.GetRacks()
 	newRacks := newDc.GetRacks()
 
+	// Racks detected by AutoDetectRacks replace the implicit default rack before the datacenter is
+	// initialized, the size doesn't have to grow for them and they are not compared to the default rack
+	racksDetected := oldDc.Spec.AutoDetectRacks && len(oldDc.Spec.Racks) == 0 && len(newDc.Spec.Racks) > 0 &&
+		oldDc.GetConditionStatus(DatacenterInitialized) != corev1.ConditionTrue &&
+		oldDc.Annotations[DetectedRacksAnnotation] == "" &&
+		newDc.Annotations[DetectedRacksAnnotation] == strings.Join(rackNames(newDc.Spec.Racks), ",")
+	if racksDetected {
+		oldRacks = nil
+	}
+
 	if len(oldRacks) > len(newRacks) {
 		return attemptedTo("remove rack")
 	}
 
 	newRackCount := len(newRacks) - len(oldRacks)
-	if newRackCount > 0 {
+	if newRackCount > 0 && !racksDetected {
 		newSizeDifference := newDc.Spec.Size - oldDc.Spec.Size
 		oldRackNodeSplit := SplitRacks(int(oldDc.Spec.Size), len(oldRacks))
 		minNodesFromOldRacks := oldRackNodeSplit[len(oldRackNodeSplit)-1]
@@ -212,7 +434,7 @@ func (dc *CassandraDatacenter) ValidateCreate() error {
 		return err
 	}
 
-	return nil
+	return ValidateNewOrChangedFields(nil, *dc)
 }
 
 func (dc *CassandraDatacenter) ValidateUpdate(old runtime.Object) error {
@@ -227,6 +449,10 @@ func (dc *CassandraDatacenter) ValidateUpdate(old runtime.Object) error {
 		return err
 	}
 
+	if err := ValidateNewOrChangedFields(oldDc, *dc); err != nil {
+		return err
+	}
+
 	return ValidateDatacenterFieldChanges(*oldDc, *dc)
 }
 
@@ -275,6 +501,40 @@ func ValidateServiceLabelsAndAnnotations(dc CassandraDatacenter) error {
 		}
 	}
 
+	// the additional annotations are merged into the annotations the operator manages itself
+	if containsReservedPrefixes(dc.Spec.AdditionalAnnotations) {
+		return attemptedTo(fmt.Sprintf("configure additionalAnnotations with reserved annotations (prefixes %s and/or %s)", datastaxPrefix, k8ssandraPrefix))
+	}
+
+	return nil
+}
+
+// serverContainerName is the name of the container running the server, its image comes from the
+// serverImage or serverVersion
+const serverContainerName = "cassandra"
+
+// podTemplateServerImage returns the image of the server container set in the PodTemplateSpec, if any
+func podTemplateServerImage(dc CassandraDatacenter) string {
+	if dc.Spec.PodTemplateSpec == nil {
+		return ""
+	}
+
+	for _, container := range dc.Spec.PodTemplateSpec.Spec.Containers {
+		if container.Name == serverContainerName {
+			return container.Image
+		}
+	}
+
+	return ""
+}
+
+// ValidateServerImageNotOverridden rejects a PodTemplateSpec setting the image of the server
+// container, which would silently override serverImage and serverVersion
+func ValidateServerImageNotOverridden(dc CassandraDatacenter) error {
+	if podTemplateServerImage(dc) != "" {
+		return attemptedTo("set the image of the %s container in podTemplateSpec, use serverImage or serverVersion instead", serverContainerName)
+	}
+
 	return nil
 }
 
@@ -60,6 +60,20 @@ func Test_ValidateSingleDatacenter(t *testing.T) {
 			},
 			errString: "use unsupported DSE version '4.8.0'",
 		},
+		{
+			name: "Invalid seed service name",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:      "cassandra",
+					ServerVersion:   "4.0.1",
+					SeedServiceName: "Seed_Service",
+				},
+			},
+			errString: "use invalid seedServiceName 'Seed_Service'",
+		},
 		{
 			name: "Cassandra valid",
 			dc: &CassandraDatacenter{
@@ -247,6 +261,26 @@ func Test_ValidateSingleDatacenter(t *testing.T) {
 			},
 			errString: "use multiple nodes per worker without cpu and memory requests and limits",
 		},
+		{
+			name: "Management API auth can't be both insecure and manual",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "4.0.1",
+					ManagementApiAuth: ManagementApiAuthConfig{
+						Insecure: &ManagementApiAuthInsecureConfig{},
+						Manual: &ManagementApiAuthManualConfig{
+							ClientSecretName: "mgmt-api-client",
+							ServerSecretName: "mgmt-api-server",
+						},
+					},
+				},
+			},
+			errString: "use both insecure and manual managementApiAuth",
+		},
 		{
 			name: "Prevent user specified reserved Service labels and annotations",
 			dc: &CassandraDatacenter{
@@ -266,6 +300,84 @@ func Test_ValidateSingleDatacenter(t *testing.T) {
 			},
 			errString: "configure DatacenterService with reserved annotations and/or labels (prefixes cassandra.datastax.com and/or k8ssandra.io)",
 		},
+		{
+			name: "Prevent reserved additional annotations",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:            "cassandra",
+					ServerVersion:         "4.0.4",
+					AdditionalAnnotations: map[string]string{"cost-center": "db", "cassandra.datastax.com/resource-hash": "abc"},
+				},
+			},
+			errString: "configure additionalAnnotations with reserved annotations (prefixes cassandra.datastax.com and/or k8ssandra.io)",
+		},
+		{
+			name: "Size a multiple of the racks is allowed",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "4.0.4",
+					Size:          6,
+					Racks:         []Rack{{Name: "rack1"}, {Name: "rack2"}, {Name: "rack3"}},
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "PodTemplateSpec customizing the server container",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "4.0.4",
+					PodTemplateSpec: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{Name: "cassandra", Env: []corev1.EnvVar{{Name: "FOO", Value: "bar"}}},
+								{Name: "sidecar", Image: "busybox"},
+							},
+						},
+					},
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "OrderedReady podManagementPolicy",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:          "cassandra",
+					ServerVersion:       "4.0.4",
+					PodManagementPolicy: "OrderedReady",
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Unsupported podManagementPolicy",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:          "cassandra",
+					ServerVersion:       "4.0.4",
+					PodManagementPolicy: "Random",
+				},
+			},
+			errString: "use unsupported podManagementPolicy 'Random'",
+		},
 	}
 
 	for _, tt := range tests {
@@ -286,6 +398,177 @@ func Test_ValidateSingleDatacenter(t *testing.T) {
 	}
 }
 
+func Test_ValidateRackZones(t *testing.T) {
+	dc := CreateCassDc("cassandra")
+	dc.Spec.Size = 3
+	dc.Spec.Racks = []Rack{
+		{Name: "rack1", Zone: "zone1"},
+		{Name: "rack2", NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "zone2"}},
+		{Name: "rack3", NodeAffinityLabels: map[string]string{"failure-domain.beta.kubernetes.io/zone": "zone3"}},
+	}
+	assert.NoError(t, ValidateRackZones(dc))
+
+	// Only the pinned racks are counted
+	dc.Spec.Racks = []Rack{
+		{Name: "rack1", NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "zone1"}},
+		{Name: "rack2"},
+		{Name: "rack3"},
+	}
+	assert.NoError(t, ValidateRackZones(dc))
+
+	dc.Spec.Racks = []Rack{
+		{Name: "rack1", NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "zone1"}},
+		{Name: "rack2", NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "zone1"}},
+		{Name: "rack3"},
+	}
+	assert.EqualError(t, ValidateRackZones(dc),
+		"CassandraDatacenter write rejected, attempted to pin 2 racks to only 1 distinct zones")
+
+	// Rejected on create and when the racks change, but an existing layout can still be updated
+	assert.Error(t, dc.ValidateCreate())
+
+	oldDc := dc.DeepCopy()
+	dc.Spec.AdditionalLabels = map[string]string{"team": "db"}
+	assert.NoError(t, dc.ValidateUpdate(oldDc))
+
+	dc.Spec.Racks[2].NodeAffinityLabels = map[string]string{"topology.kubernetes.io/zone": "zone2"}
+	assert.Error(t, dc.ValidateUpdate(oldDc))
+}
+
+func Test_ReplicasPerRack(t *testing.T) {
+	assert.Equal(t, []int{2, 2, 1}, ReplicasPerRack(5, 3))
+	assert.Equal(t, []int{2, 2, 2}, ReplicasPerRack(6, 3))
+	assert.Equal(t, []int{0, 0}, ReplicasPerRack(0, 2))
+	assert.Nil(t, ReplicasPerRack(3, 0))
+}
+
+func Test_ValidateAdditionalVolumes(t *testing.T) {
+	dc := CreateCassDc("cassandra")
+	dc.Spec.StorageConfig.AdditionalVolumes = AdditionalVolumesSlice{
+		{Name: "commitlog", MountPath: "/var/lib/cassandra-commitlog"},
+		{Name: "hints", MountPath: "/var/lib/cassandra-hints"},
+	}
+	assert.NoError(t, ValidateAdditionalVolumes(dc))
+	assert.NoError(t, ValidateNewOrChangedFields(nil, dc))
+
+	dc.Spec.StorageConfig.AdditionalVolumes[1].Name = "commitlog"
+	assert.EqualError(t, ValidateAdditionalVolumes(dc),
+		"CassandraDatacenter write rejected, attempted to use additional volume name 'commitlog' more than once")
+	assert.Error(t, ValidateNewOrChangedFields(nil, dc))
+
+	// A datacenter created before the check keeps being updatable while its volumes are left alone
+	oldDc := *dc.DeepCopy()
+	newDc := *dc.DeepCopy()
+	newDc.Spec.ServerVersion = "4.0.5"
+	assert.NoError(t, ValidateNewOrChangedFields(&oldDc, newDc))
+
+	dc.Spec.StorageConfig.AdditionalVolumes[1].Name = "server-data"
+	assert.EqualError(t, ValidateAdditionalVolumes(dc),
+		"CassandraDatacenter write rejected, attempted to use reserved additional volume name 'server-data'")
+
+	dc.Spec.StorageConfig.AdditionalVolumes[1].Name = "hints"
+	dc.Spec.StorageConfig.AdditionalVolumes[1].MountPath = "/var/lib/cassandra-commitlog/"
+	assert.EqualError(t, ValidateAdditionalVolumes(dc),
+		"CassandraDatacenter write rejected, attempted to mount additional volume 'hints' at '/var/lib/cassandra-commitlog/', which is already in use")
+
+	dc.Spec.StorageConfig.AdditionalVolumes[1].MountPath = "/var/log/cassandra"
+	assert.Error(t, ValidateAdditionalVolumes(dc))
+
+	dc.Spec.StorageConfig.AdditionalVolumes[1].MountPath = "hints"
+	assert.EqualError(t, ValidateAdditionalVolumes(dc),
+		"CassandraDatacenter write rejected, attempted to mount additional volume 'hints' at relative path 'hints'")
+}
+
+func Test_ValidateServerImageNotOverridden(t *testing.T) {
+	dc := CreateCassDc("cassandra")
+	dc.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "cassandra", Image: "cassandra:4.0.4"}},
+		},
+	}
+	assert.EqualError(t, ValidateServerImageNotOverridden(dc),
+		"CassandraDatacenter write rejected, attempted to set the image of the cassandra container in podTemplateSpec, use serverImage or serverVersion instead")
+	assert.Error(t, ValidateNewOrChangedFields(nil, dc))
+
+	// A datacenter created before the check keeps being updatable while the image is left alone
+	oldDc := *dc.DeepCopy()
+	newDc := *dc.DeepCopy()
+	newDc.Spec.Size = 6
+	assert.NoError(t, ValidateNewOrChangedFields(&oldDc, newDc))
+
+	newDc.Spec.PodTemplateSpec.Spec.Containers[0].Image = "cassandra:4.0.5"
+	assert.Error(t, ValidateNewOrChangedFields(&oldDc, newDc))
+
+	// Removing the image is allowed
+	newDc.Spec.PodTemplateSpec.Spec.Containers[0].Image = ""
+	assert.NoError(t, ValidateNewOrChangedFields(&oldDc, newDc))
+}
+
+func Test_ValidateDatacenterFieldChanges_CreationOnlyStorageConfig(t *testing.T) {
+	oldDc := CreateCassDc("cassandra")
+
+	newDc := *oldDc.DeepCopy()
+	newDc.Spec.StorageConfig.FixPermissions = true
+	assert.EqualError(t, ValidateDatacenterFieldChanges(oldDc, newDc),
+		"CassandraDatacenter write rejected, attempted to change storageConfig")
+
+	newDc = *oldDc.DeepCopy()
+	newDc.Spec.StorageConfig.UseEphemeralStorage = true
+	assert.EqualError(t, ValidateDatacenterFieldChanges(oldDc, newDc),
+		"CassandraDatacenter write rejected, attempted to change storageConfig")
+}
+
+func Test_ValidateSizeDecrease(t *testing.T) {
+	oldDc := CreateCassDc("cassandra")
+	oldDc.Spec.Size = 9
+	oldDc.Spec.Racks = []Rack{{Name: "rack1"}, {Name: "rack2"}, {Name: "rack3"}}
+	assert.Equal(t, int32(3), MinimumSafeSize(oldDc))
+
+	newDc := *oldDc.DeepCopy()
+	newDc.Spec.Size = 3
+	assert.NoError(t, ValidateSizeDecrease(oldDc, newDc))
+
+	// A keyspace with a replication factor of 5 was seen by the operator
+	oldDc.Status.MaxReplicationFactor = 5
+	assert.Equal(t, int32(5), MinimumSafeSize(oldDc))
+	assert.EqualError(t, ValidateSizeDecrease(oldDc, newDc),
+		"CassandraDatacenter write rejected, attempted to decrease size to 3, below the minimum of 5 nodes that can be safely kept")
+	assert.Error(t, ValidateDatacenterFieldChanges(oldDc, newDc))
+
+	newDc.Spec.Size = 6
+	assert.NoError(t, ValidateSizeDecrease(oldDc, newDc))
+
+	// Growing is always allowed
+	oldDc.Spec.Size = 1
+	assert.NoError(t, ValidateSizeDecrease(oldDc, newDc))
+}
+
+func Test_ValidateRackBalance(t *testing.T) {
+	dc := CreateCassDc("cassandra")
+	dc.Spec.Size = 5
+	dc.Spec.Racks = []Rack{{Name: "rack1"}, {Name: "rack2"}, {Name: "rack3"}}
+
+	// An uneven split is only logged by default
+	assert.NoError(t, ValidateRackBalance(dc))
+	assert.NoError(t, ValidateNewOrChangedFields(nil, dc))
+
+	dc.Spec.StrictRackBalance = true
+	assert.EqualError(t, ValidateNewOrChangedFields(nil, dc),
+		"CassandraDatacenter write rejected, attempted to use size 5 which is not a multiple of the 3 racks, nodes per rack would be [2 2 1]")
+
+	// A datacenter accepted before keeps being updatable while its size and racks are left alone
+	oldDc := *dc.DeepCopy()
+	newDc := *dc.DeepCopy()
+	newDc.Spec.ServerVersion = "4.0.5"
+	assert.NoError(t, ValidateNewOrChangedFields(&oldDc, newDc))
+
+	newDc.Spec.Size = 7
+	assert.Error(t, ValidateNewOrChangedFields(&oldDc, newDc))
+
+	newDc.Spec.Size = 6
+	assert.NoError(t, ValidateNewOrChangedFields(&oldDc, newDc))
+}
+
 func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 	storageSize := resource.MustParse("1Gi")
 	storageName := "server-data"
@@ -476,6 +759,78 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 			},
 			errString: "change storageConfig",
 		},
+		{
+			name: "StorageConfig storage size increase",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					StorageConfig: StorageConfig{
+						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+							StorageClassName: &storageName,
+							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
+							Resources: corev1.ResourceRequirements{
+								Requests: map[corev1.ResourceName]resource.Quantity{"storage": storageSize},
+							},
+						},
+					},
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					StorageConfig: StorageConfig{
+						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+							StorageClassName: &storageName,
+							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
+							Resources: corev1.ResourceRequirements{
+								Requests: map[corev1.ResourceName]resource.Quantity{"storage": resource.MustParse("2Gi")},
+							},
+						},
+					},
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "StorageConfig storage size decrease",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					StorageConfig: StorageConfig{
+						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+							StorageClassName: &storageName,
+							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
+							Resources: corev1.ResourceRequirements{
+								Requests: map[corev1.ResourceName]resource.Quantity{"storage": storageSize},
+							},
+						},
+					},
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					StorageConfig: StorageConfig{
+						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+							StorageClassName: &storageName,
+							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
+							Resources: corev1.ResourceRequirements{
+								Requests: map[corev1.ResourceName]resource.Quantity{"storage": resource.MustParse("512Mi")},
+							},
+						},
+					},
+				},
+			},
+			errString: "shrink storageConfig.cassandraDataVolumeClaimSpec",
+		},
 		{
 			name: "Removing a rack",
 			oldDc: &CassandraDatacenter{
@@ -776,6 +1131,157 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 			},
 			errString: "add racks without increasing size enough to prevent existing nodes from moving to new racks to maintain balance.\nNew racks added: 2, size increased by: 7. Expected size increase to be at least 8",
 		},
+		{
+			name: "Detected racks replace the default rack",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "exampleDC",
+					Annotations: map[string]string{DetectedRacksAnnotation: "zone1,zone2,zone3"},
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+					Racks: []Rack{
+						{Name: "zone1"},
+						{Name: "zone2"},
+						{Name: "zone3"},
+					},
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Racks set by the user don't replace the default rack",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+					Racks: []Rack{
+						{Name: "zone1"},
+						{Name: "zone2"},
+						{Name: "zone3"},
+					},
+				},
+			},
+			errString: "add rack without increasing size",
+		},
+		{
+			name: "Detected racks don't replace the default rack of an initialized datacenter",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+				},
+				Status: CassandraDatacenterStatus{
+					Conditions: []DatacenterCondition{
+						*NewDatacenterCondition(DatacenterInitialized, corev1.ConditionTrue),
+					},
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "exampleDC",
+					Annotations: map[string]string{DetectedRacksAnnotation: "zone1,zone2,zone3"},
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+					Racks: []Rack{
+						{Name: "zone1"},
+						{Name: "zone2"},
+						{Name: "zone3"},
+					},
+				},
+			},
+			errString: "add rack without increasing size",
+		},
+		{
+			name: "Detected racks can't be removed",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+					Racks: []Rack{
+						{Name: "zone1"},
+						{Name: "zone2"},
+						{Name: "zone3"},
+					},
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+					Racks: []Rack{
+						{Name: "zone1"},
+						{Name: "zone2"},
+					},
+				},
+			},
+			errString: "remove rack",
+		},
+		{
+			name: "Detected racks can't be renamed",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+					Racks: []Rack{
+						{Name: "zone1"},
+						{Name: "zone2"},
+						{Name: "zone3"},
+					},
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					Size:            3,
+					AutoDetectRacks: true,
+					Racks: []Rack{
+						{Name: "zone1"},
+						{Name: "zone2"},
+						{Name: "rack3"},
+					},
+				},
+			},
+			errString: "change rack name from 'zone3' to 'rack3'",
+		},
 	}
 
 	for _, tt := range tests {
@@ -337,11 +337,47 @@ func (in *CassandraDatacenterSpec) DeepCopyInto(out *CassandraDatacenterSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.AdditionalAnnotations != nil {
+		in, out := &in.AdditionalAnnotations, &out.AdditionalAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.CDC != nil {
 		in, out := &in.CDC, &out.CDC
 		*out = new(CDCConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PostStartHook != nil {
+		in, out := &in.PostStartHook, &out.PostStartHook
+		*out = new(v1.LifecycleHandler)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AutoCleanupAfterScaleUp != nil {
+		in, out := &in.AutoCleanupAfterScaleUp, &out.AutoCleanupAfterScaleUp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PodRebalancing != nil {
+		in, out := &in.PodRebalancing, &out.PodRebalancing
+		*out = new(PodRebalancingConfig)
+		**out = **in
+	}
+	if in.NetworkPolicyClientNamespaceLabels != nil {
+		in, out := &in.NetworkPolicyClientNamespaceLabels, &out.NetworkPolicyClientNamespaceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraDatacenterSpec.
@@ -368,6 +404,8 @@ func (in *CassandraDatacenterStatus) DeepCopyInto(out *CassandraDatacenterStatus
 	in.UsersUpserted.DeepCopyInto(&out.UsersUpserted)
 	in.LastServerNodeStarted.DeepCopyInto(&out.LastServerNodeStarted)
 	in.LastRollingRestart.DeepCopyInto(&out.LastRollingRestart)
+	in.LastScaleOperation.DeepCopyInto(&out.LastScaleOperation)
+	in.LastReconciledTime.DeepCopyInto(&out.LastReconciledTime)
 	if in.NodeStatuses != nil {
 		in, out := &in.NodeStatuses, &out.NodeStatuses
 		*out = make(CassandraStatusMap, len(*in))
@@ -386,6 +424,44 @@ func (in *CassandraDatacenterStatus) DeepCopyInto(out *CassandraDatacenterStatus
 		*out = make([]v1.ObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	in.KeystoreSecretReloadTime.DeepCopyInto(&out.KeystoreSecretReloadTime)
+	if in.RackStatuses != nil {
+		in, out := &in.RackStatuses, &out.RackStatuses
+		*out = make([]RackStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnhealthyPods != nil {
+		in, out := &in.UnhealthyPods, &out.UnhealthyPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RollingOperation != nil {
+		in, out := &in.RollingOperation, &out.RollingOperation
+		*out = new(RollingOperation)
+		**out = **in
+	}
+	if in.ReconcileErrors != nil {
+		in, out := &in.ReconcileErrors, &out.ReconcileErrors
+		*out = make([]ReconcileError, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalLabelKeys != nil {
+		in, out := &in.AdditionalLabelKeys, &out.AdditionalLabelKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalAnnotationKeys != nil {
+		in, out := &in.AdditionalAnnotationKeys, &out.AdditionalAnnotationKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigBuilderFeatures != nil {
+		in, out := &in.ConfigBuilderFeatures, &out.ConfigBuilderFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraDatacenterStatus.
@@ -480,6 +556,21 @@ func (in *DseWorkloads) DeepCopy() *DseWorkloads {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedObjectCounts) DeepCopyInto(out *ManagedObjectCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedObjectCounts.
+func (in *ManagedObjectCounts) DeepCopy() *ManagedObjectCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedObjectCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementApiAuthConfig) DeepCopyInto(out *ManagementApiAuthConfig) {
 	*out = *in
@@ -570,6 +661,21 @@ func (in *NodePortConfig) DeepCopy() *NodePortConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodRebalancingConfig) DeepCopyInto(out *PodRebalancingConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodRebalancingConfig.
+func (in *PodRebalancingConfig) DeepCopy() *PodRebalancingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodRebalancingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rack) DeepCopyInto(out *Rack) {
 	*out = *in
@@ -592,6 +698,52 @@ func (in *Rack) DeepCopy() *Rack {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RackStatus) DeepCopyInto(out *RackStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RackStatus.
+func (in *RackStatus) DeepCopy() *RackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileError) DeepCopyInto(out *ReconcileError) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileError.
+func (in *ReconcileError) DeepCopy() *ReconcileError {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingOperation) DeepCopyInto(out *RollingOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingOperation.
+func (in *RollingOperation) DeepCopy() *RollingOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceConfig) DeepCopyInto(out *ServiceConfig) {
 	*out = *in
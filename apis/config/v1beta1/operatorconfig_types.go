@@ -38,6 +38,14 @@ type OperatorConfig struct {
 
 	// ImageConfigFile indicates the path where to load the imageConfig from
 	ImageConfigFile string `json:"imageConfigFile,omitempty"`
+
+	// LabelMigrations maps label keys used by a previous version of the operator to the keys that replaced
+	// them. Managed resources carrying an old key get the new key added with the same value.
+	LabelMigrations map[string]string `json:"labelMigrations,omitempty"`
+
+	// MaxRequeueBackoff caps the exponential backoff before reconciling again a datacenter whose
+	// reconcile failed. Defaults to 5m.
+	MaxRequeueBackoff metav1.Duration `json:"maxRequeueBackoff,omitempty"`
 }
 
 func init() {
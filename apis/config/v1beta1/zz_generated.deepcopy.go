@@ -136,6 +136,14 @@ func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ControllerManagerConfigurationSpec.DeepCopyInto(&out.ControllerManagerConfigurationSpec)
+	if in.LabelMigrations != nil {
+		in, out := &in.LabelMigrations, &out.LabelMigrations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.MaxRequeueBackoff = in.MaxRequeueBackoff
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
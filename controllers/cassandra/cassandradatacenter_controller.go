@@ -27,6 +27,7 @@ import (
 	"github.com/k8ssandra/cass-operator/pkg/reconciliation"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -56,8 +57,11 @@ import (
 // +kubebuilder:rbac:groups=apps,namespace=cass-operator,resources=deployments/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,namespace=cass-operator,resources=pods;endpoints;services;configmaps;secrets;persistentvolumeclaims;events,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,namespace=cass-operator,resources=namespaces,verbs=get
-// +kubebuilder:rbac:groups=core,resources=persistentvolumes;nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=policy,namespace=cass-operator,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,namespace=cass-operator,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 
 // CassandraDatacenterReconciler reconciles a cassandraDatacenter object
 type CassandraDatacenterReconciler struct {
@@ -70,6 +74,17 @@ type CassandraDatacenterReconciler struct {
 	// during reconciliation where we update the mappings for the watches.
 	// Putting it here allows us to get it to both places.
 	SecretWatches dynamicwatch.DynamicWatches
+
+	// MaxRequeueBackoff caps the delay before reconciling again a datacenter whose reconcile
+	// failed, DefaultMaxRequeueBackoff if unset
+	MaxRequeueBackoff time.Duration
+
+	// DryRun makes the reconciles compute the desired resources of the datacenters and log the
+	// writes to the API server, the events and the management API requests other than reads
+	// instead of doing them
+	DryRun bool
+
+	backoff requeueBackoff
 }
 
 // Reconcile reads that state of the cluster for a Datacenter object
@@ -80,8 +95,9 @@ type CassandraDatacenterReconciler struct {
 // if the returned error is non-nil or Result.Requeue is true,
 // otherwise upon completion it will remove the work from the queue.
 // See: https://godoc.org/sigs.k8s.io/controller-runtime/pkg/reconcile#Result
-func (r *CassandraDatacenterReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+func (r *CassandraDatacenterReconciler) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
 	startReconcile := time.Now()
+	failed := false
 
 	logger := r.Log.
 		WithValues("cassandradatacenter", request.NamespacedName).
@@ -94,13 +110,19 @@ func (r *CassandraDatacenterReconciler) Reconcile(ctx context.Context, request c
 
 	defer func() {
 		reconcileDuration := time.Since(startReconcile).Seconds()
+		observeReconcile(reconcileDuration, result, err, failed)
 		logger.Info("Reconcile loop completed",
 			"duration", reconcileDuration)
 	}()
 
 	logger.Info("======== handler::Reconcile has been called")
 
-	rc, err := reconciliation.CreateReconciliationContext(ctx, &request, r.Client, r.Scheme, r.Recorder, r.SecretWatches)
+	var cli client.Client = r.Client
+	if r.DryRun {
+		cli = reconciliation.NewDryRunClient(r.Client, logger)
+	}
+
+	rc, err := reconciliation.CreateReconciliationContext(ctx, &request, cli, r.Scheme, r.Recorder, r.SecretWatches)
 
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -108,12 +130,18 @@ func (r *CassandraDatacenterReconciler) Reconcile(ctx context.Context, request c
 			// Owned objects are automatically garbage collected.
 			// Return and don't requeue
 			logger.Info("CassandraDatacenter resource not found. Ignoring since object must be deleted.")
+			reconciliation.DeleteDatacenterMetrics(request.Namespace, request.Name)
+			r.backoff.reset(request.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 
 		// Error reading the object
-		logger.Error(err, "Failed to get CassandraDatacenter.")
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		failed = true
+		return r.requeueWithBackoff(logger, request, err, "Failed to get CassandraDatacenter."), nil
+	}
+
+	if r.DryRun {
+		rc.UseDryRun()
 	}
 
 	if err := rc.IsValid(rc.Datacenter); err != nil {
@@ -142,9 +170,14 @@ func (r *CassandraDatacenterReconciler) Reconcile(ctx context.Context, request c
 
 	res, err := rc.CalculateReconciliationActions()
 	if err != nil {
-		logger.Error(err, "calculateReconciliationActions returned an error")
 		rc.Recorder.Eventf(rc.Datacenter, "Warning", "ReconcileFailed", err.Error())
+		if err := rc.RecordReconcileError(err); err != nil {
+			logger.Error(err, "failed to record the reconcile error in the datacenter status")
+		}
+		failed = true
+		return r.requeueWithBackoff(logger, request, err, "calculateReconciliationActions returned an error"), nil
 	}
+	r.backoff.reset(request.NamespacedName)
 
 	// Prevent immediate requeue
 	if res.Requeue {
@@ -152,7 +185,20 @@ func (r *CassandraDatacenterReconciler) Reconcile(ctx context.Context, request c
 			res.RequeueAfter = time.Duration(500 * time.Millisecond)
 		}
 	}
-	return res, err
+	return res, nil
+}
+
+// requeueWithBackoff logs the error of the failed reconcile and returns a result requeueing it after
+// an exponential backoff. Returning the error would requeue it with the rate limiter of the
+// controller, which is shared by all the datacenters.
+func (r *CassandraDatacenterReconciler) requeueWithBackoff(logger logr.Logger, request ctrl.Request, err error, msg string) ctrl.Result {
+	maxBackoff := r.MaxRequeueBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxRequeueBackoff
+	}
+	backoff := r.backoff.next(request.NamespacedName, maxBackoff)
+	logger.Error(err, msg, "requeueAfter", backoff)
+	return ctrl.Result{RequeueAfter: backoff}
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -176,13 +222,30 @@ func (r *CassandraDatacenterReconciler) SetupWithManager(mgr ctrl.Manager) error
 		},
 	}
 
+	// Annotations don't change the generation, the rolling-restart one needs to trigger a reconcile
+	rollingRestartAnnotationPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetAnnotations()[api.RollingRestartAnnotation] != e.ObjectNew.GetAnnotations()[api.RollingRestartAnnotation]
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+
 	// Create a new managed controller builder
 	c := ctrl.NewControllerManagedBy(mgr).
 		Named("cassandradatacenter-controller").
 		WithLogger(log).
-		For(&api.CassandraDatacenter{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		For(&api.CassandraDatacenter{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, rollingRestartAnnotationPredicate))).
 		Owns(&appsv1.StatefulSet{}, builder.WithPredicates(managedByCassandraOperatorPredicate)).
 		Owns(&policyv1.PodDisruptionBudget{}, builder.WithPredicates(managedByCassandraOperatorPredicate)).
+		Owns(&networkingv1.NetworkPolicy{}, builder.WithPredicates(managedByCassandraOperatorPredicate)).
 		Owns(&corev1.Service{}, builder.WithPredicates(managedByCassandraOperatorPredicate))
 
 	configSecretMapFn := func(mapObj client.Object) []reconcile.Request {
@@ -0,0 +1,60 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	reconcileSuccess = "success"
+	reconcileRequeue = "requeue"
+	reconcileError   = "error"
+)
+
+var (
+	reconcileDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "cass_operator_reconcile_duration_seconds",
+		Help: "Duration of the reconciles of the CassandraDatacenters, by result: success, requeue or error",
+	}, []string{"result"})
+
+	reconcileErrorsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "cass_operator_reconcile_errors_total",
+		Help: "Number of reconciles of the CassandraDatacenters that failed",
+	})
+)
+
+func init() {
+	// The controller-runtime registry is served on the metrics endpoint of the manager
+	metrics.Registry.MustRegister(reconcileDurationSeconds, reconcileErrorsTotal)
+}
+
+// observeReconcile records the duration of a reconcile under its result. A failed reconcile is
+// counted as an error even when it was requeued with a backoff instead of returning the error.
+func observeReconcile(seconds float64, res ctrl.Result, err error, failed bool) {
+	result := reconcileSuccess
+	switch {
+	case err != nil || failed:
+		result = reconcileError
+		reconcileErrorsTotal.Inc()
+	case res.Requeue || res.RequeueAfter > 0:
+		result = reconcileRequeue
+	}
+	reconcileDurationSeconds.WithLabelValues(result).Observe(seconds)
+}
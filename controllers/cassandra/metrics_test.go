@@ -0,0 +1,79 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/k8ssandra/cass-operator/pkg/mocks"
+)
+
+func histogramCount(t *testing.T, result string) uint64 {
+	metric := &dto.Metric{}
+	assert.NoError(t, reconcileDurationSeconds.WithLabelValues(result).(prometheus.Histogram).Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestReconcile_ErrorMetrics(t *testing.T) {
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "dc1"}}
+
+	mockClient := &mocks.Client{}
+	mockClient.On("Get", mock.Anything, request.NamespacedName, mock.Anything).
+		Return(fmt.Errorf("etcdserver: request timed out")).Once()
+	mockClient.On("Get", mock.Anything, request.NamespacedName, mock.Anything).
+		Return(errors.NewNotFound(schema.GroupResource{Resource: "cassandradatacenters"}, "dc1")).Once()
+
+	r := &CassandraDatacenterReconciler{
+		Client:   mockClient,
+		Log:      logr.Discard(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	errorsBefore := testutil.ToFloat64(reconcileErrorsTotal)
+	errorCount := histogramCount(t, reconcileError)
+	successCount := histogramCount(t, reconcileSuccess)
+
+	// The failed reconcile is requeued with a backoff, it still counts as an error
+	res, err := r.Reconcile(context.Background(), request)
+	assert.NoError(t, err)
+	assert.True(t, res.RequeueAfter > 0)
+	assert.Equal(t, errorsBefore+1, testutil.ToFloat64(reconcileErrorsTotal))
+	assert.Equal(t, errorCount+1, histogramCount(t, reconcileError))
+
+	// A deleted datacenter is not an error
+	res, err = r.Reconcile(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.Equal(t, errorsBefore+1, testutil.ToFloat64(reconcileErrorsTotal))
+	assert.Equal(t, successCount+1, histogramCount(t, reconcileSuccess))
+
+	mockClient.AssertExpectations(t)
+}
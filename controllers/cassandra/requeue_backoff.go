@@ -0,0 +1,69 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	minRequeueBackoff = time.Second
+
+	// DefaultMaxRequeueBackoff caps the requeue backoff when OperatorConfig.MaxRequeueBackoff is unset
+	DefaultMaxRequeueBackoff = 5 * time.Minute
+)
+
+// requeueBackoff counts the consecutive failed reconciles of each datacenter, so that they are
+// requeued with an exponential backoff instead of hammering the API server during transient errors.
+type requeueBackoff struct {
+	mu       sync.Mutex
+	failures map[types.NamespacedName]int
+}
+
+// next records a failed reconcile of the datacenter and returns the delay before the next one,
+// doubling from one second up to maxBackoff
+func (b *requeueBackoff) next(key types.NamespacedName, maxBackoff time.Duration) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures == nil {
+		b.failures = map[types.NamespacedName]int{}
+	}
+
+	backoff := minRequeueBackoff
+	for i := 0; i < b.failures[key] && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	} else {
+		b.failures[key]++
+	}
+
+	return backoff
+}
+
+// reset forgets the failed reconciles of the datacenter once one completes without error
+func (b *requeueBackoff) reset(key types.NamespacedName) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.failures, key)
+}
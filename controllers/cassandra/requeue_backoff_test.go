@@ -0,0 +1,44 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRequeueBackoff(t *testing.T) {
+	backoff := requeueBackoff{}
+	dc1 := types.NamespacedName{Namespace: "ns", Name: "dc1"}
+	dc2 := types.NamespacedName{Namespace: "ns", Name: "dc2"}
+
+	delays := []time.Duration{}
+	for i := 0; i < 5; i++ {
+		delays = append(delays, backoff.next(dc1, 10*time.Second))
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second}, delays)
+
+	// Each datacenter has its own backoff
+	assert.Equal(t, time.Second, backoff.next(dc2, 10*time.Second))
+
+	// A successful reconcile resets the backoff
+	backoff.reset(dc1)
+	assert.Equal(t, time.Second, backoff.next(dc1, 10*time.Second))
+}
@@ -17,9 +17,9 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
-	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -32,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	configv1beta1 "github.com/k8ssandra/cass-operator/apis/config/v1beta1"
@@ -39,6 +40,7 @@ import (
 	controllers "github.com/k8ssandra/cass-operator/controllers/cassandra"
 	controlcontrollers "github.com/k8ssandra/cass-operator/controllers/control"
 	"github.com/k8ssandra/cass-operator/pkg/images"
+	"github.com/k8ssandra/cass-operator/pkg/oplabels"
 	"github.com/k8ssandra/cass-operator/pkg/utils"
 	//+kubebuilder:scaffold:imports
 )
@@ -63,6 +65,12 @@ func main() {
 		"The controller will load its initial configuration from this file. "+
 			"Omit this flag to use the default configuration values. ")
 
+	var dryRun bool
+	flag.BoolVar(&dryRun, "dry-run", utils.IsDryRunEnabled(),
+		"Compute the desired resources of the datacenters and log them without writing to the API server, "+
+			"recording events or sending requests other than reads to the management API. "+
+			"Defaults to the value of the "+utils.DryRunEnvVar+" environment variable.")
+
 	opts := zap.Options{
 		Development: true,
 	}
@@ -71,7 +79,11 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	ns, err := utils.GetWatchNamespace()
+	if dryRun {
+		setupLog.Info("dry run mode enabled, the reconciles will log their writes instead of doing them")
+	}
+
+	namespaces, err := utils.GetWatchNamespaces()
 	if err != nil {
 		setupLog.Error(err, "unable to get WatchNamespace, "+
 			"the manager will watch and manage resources in all namespaces")
@@ -95,13 +107,19 @@ func main() {
 		}
 	}
 
+	oplabels.SetLabelMigrations(operConfig.LabelMigrations)
+
 	// Add support for MultiNamespace set in WATCH_NAMESPACE (e.g ns1,ns2)
-	if strings.Contains(ns, ",") {
-		setupLog.Info("manager set up with multiple namespaces", "namespaces", ns)
+	if len(namespaces) > 1 {
+		setupLog.Info("manager set up with multiple namespaces", "namespaces", namespaces)
 		// configure cluster-scoped with MultiNamespacedCacheBuilder
 		options.Namespace = ""
-		options.NewCache = cache.MultiNamespacedCacheBuilder(strings.Split(ns, ","))
+		options.NewCache = cache.MultiNamespacedCacheBuilder(namespaces)
 	} else {
+		ns := ""
+		if len(namespaces) == 1 {
+			ns = namespaces[0]
+		}
 		setupLog.Info("watch namespace configured", "namespace", ns)
 		options.Namespace = ns
 	}
@@ -112,11 +130,27 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The manager only starts this runnable once elected, and stops it when the lease is lost or
+	// the operator shuts down
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		setupLog.Info("elected leader")
+		utils.SetLeader(true)
+		<-ctx.Done()
+		utils.SetLeader(false)
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to track the leader election")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.CassandraDatacenterReconciler{
 		Client:   mgr.GetClient(),
 		Log:      ctrl.Log.WithName("controllers").WithName("CassandraDatacenter"),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("cass-operator"),
+
+		MaxRequeueBackoff: operConfig.MaxRequeueBackoff.Duration,
+		DryRun:            dryRun,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CassandraDatacenter")
 		os.Exit(1)
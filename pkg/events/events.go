@@ -15,6 +15,7 @@ const (
 	DeletingStuckPod                  string = "DeletingStuckPod"
 	RestartingCassandra               string = "RestartingCassandra"
 	CreatedResource                   string = "CreatedResource"
+	DeletedResource                   string = "DeletedResource"
 	StartedCassandra                  string = "StartedCassandra"
 	LabeledPodAsSeed                  string = "LabeledPodAsSeed"
 	LabeledPodAsDecommissioning       string = "LabeledPodAsDecommissioning"
@@ -31,6 +32,36 @@ const (
 	StartingCassandra                 string = "StartingCassandra"
 	DecommissionDatacenter            string = "DecommissionDatacenter"
 	UnhealthyDatacenter               string = "UnhealthyDatacenter"
+	RecoveringSeeds                   string = "RecoveringSeeds"
+	RefusedDecommission               string = "RefusedDecommission"
+	ConfigBuilderFailed               string = "ConfigBuilderFailed"
+	CancelledDecommission             string = "CancelledDecommission"
+	ImagePullFailed                   string = "ImagePullFailed"
+	RecreatedPersistentVolumeClaim    string = "RecreatedPersistentVolumeClaim"
+	UnsupportedAccessMode             string = "UnsupportedAccessMode"
+	RemovedReplaceAddress             string = "RemovedReplaceAddress"
+	ReloadedTLS                       string = "ReloadedTLS"
+	RebalancingPod                    string = "RebalancingPod"
+	CassandraOOMKilled                string = "CassandraOOMKilled"
+	ClockSkewDetected                 string = "ClockSkewDetected"
+	SchedulingDeadlock                string = "SchedulingDeadlock"
+	ExpandingVolume                   string = "ExpandingVolume"
+	VolumeExpansionNotAllowed         string = "VolumeExpansionNotAllowed"
+	PodEvicted                        string = "PodEvicted"
+	UnschedulableRack                 string = "UnschedulableRack"
+	DecommissionFailed                string = "DecommissionFailed"
+	ManagementApiUnreachable          string = "ManagementApiUnreachable"
+	DetectedRacks                     string = "DetectedRacks"
+	GossipInconsistency               string = "GossipInconsistency"
+	ScaledUpDatacenter                string = "ScaledUpDatacenter"
+	ScaledDownDatacenter              string = "ScaledDownDatacenter"
+	DecommissionedNode                string = "DecommissionedNode"
+	ExpandedVolumes                   string = "ExpandedVolumes"
+	CleaningUpNodes                   string = "CleaningUpNodes"
+	ReclaimedRackPVCs                 string = "ReclaimedRackPVCs"
+	RestoredOwnerReference            string = "RestoredOwnerReference"
+	ClusterFormationTimeout           string = "ClusterFormationTimeout"
+	SeedEndpointsNotSynced            string = "SeedEndpointsNotSynced"
 )
 
 type LoggingEventRecorder struct {
@@ -136,6 +136,7 @@ const (
 	AsyncScrubTask          Feature = "async_scrub_task"
 	FullQuerySupport        Feature = "full_query_logging"
 	Rebuild                 Feature = "rebuild"
+	ReloadSsl               Feature = "reload_ssl"
 )
 
 func (f *FeatureSet) UnmarshalJSON(b []byte) error {
@@ -203,7 +204,7 @@ func GetPodHost(podName, clusterName, dcName, namespace string) string {
 
 func parseMetadataEndpointsResponseBody(body []byte) (*CassMetadataEndpoints, error) {
 	endpoints := &CassMetadataEndpoints{}
-	if err := json.Unmarshal(body, &endpoints); err != nil {
+	if err := unmarshalResponse(body, &endpoints); err != nil {
 		return nil, err
 	}
 	return endpoints, nil
@@ -264,7 +265,7 @@ func (client *NodeMgmtClient) CallSchemaVersionsEndpoint(pod *corev1.Pod) (map[s
 	}
 
 	result := make(map[string][]string)
-	if err = json.Unmarshal(bytes, &result); err != nil {
+	if err = unmarshalResponse(bytes, &result); err != nil {
 		return nil, err
 	}
 
@@ -325,6 +326,65 @@ func (client *NodeMgmtClient) CallProbeClusterEndpoint(pod *corev1.Pod, consiste
 	return err
 }
 
+// CallLivenessProbeEndpoint checks that the management API of the pod is reachable
+func (client *NodeMgmtClient) CallLivenessProbeEndpoint(pod *corev1.Pod) error {
+	client.Log.Info(
+		"calling Management API liveness probe - GET /api/v0/probes/liveness",
+		"pod", pod.Name,
+	)
+
+	podHost, err := BuildPodHostFromPod(pod)
+	if err != nil {
+		return err
+	}
+
+	request := nodeMgmtRequest{
+		endpoint: "/api/v0/probes/liveness",
+		host:     podHost,
+		method:   http.MethodGet,
+		timeout:  5 * time.Second,
+	}
+
+	_, err = callNodeMgmtEndpoint(client, request, "")
+	return err
+}
+
+// GetNodeClockOffset returns how far ahead of the local clock the clock of the pod's node is. The node
+// time is read from the Date header of the management API response, so it has a precision of one second.
+func (client *NodeMgmtClient) GetNodeClockOffset(pod *corev1.Pod) (time.Duration, error) {
+	podHost, err := BuildPodHostFromPod(pod)
+	if err != nil {
+		return 0, err
+	}
+
+	request := nodeMgmtRequest{
+		endpoint: "/api/v0/probes/liveness",
+		host:     podHost,
+		method:   http.MethodGet,
+		timeout:  5 * time.Second,
+	}
+
+	before := time.Now()
+	headers, _, err := callNodeMgmtEndpointWithHeaders(client, request, "")
+	if err != nil {
+		return 0, err
+	}
+	after := time.Now()
+
+	date := headers.Get("Date")
+	if date == "" {
+		return 0, fmt.Errorf("management API response of pod %s has no Date header", pod.Name)
+	}
+	nodeTime, err := http.ParseTime(date)
+	if err != nil {
+		return 0, err
+	}
+
+	// Compare with the middle of the request to account for its latency
+	localTime := before.Add(after.Sub(before) / 2)
+	return nodeTime.Sub(localTime), nil
+}
+
 func (client *NodeMgmtClient) CallDrainEndpoint(pod *corev1.Pod) error {
 	client.Log.Info(
 		"calling Management API drain node - POST /api/v0/ops/node/drain",
@@ -677,7 +737,7 @@ func (client *NodeMgmtClient) modifyKeyspace(endpoint string, pod *corev1.Pod, k
 
 func parseListKeyspacesEndpointsResponseBody(body []byte) ([]string, error) {
 	var keyspaces []string
-	if err := json.Unmarshal(body, &keyspaces); err != nil {
+	if err := unmarshalResponse(body, &keyspaces); err != nil {
 		return nil, err
 	}
 	return keyspaces, nil
@@ -737,7 +797,7 @@ func (client *NodeMgmtClient) GetKeyspaceReplication(pod *corev1.Pod, keyspaceNa
 		return nil, err
 	}
 	var replication map[string]string
-	if err := json.Unmarshal(body, &replication); err != nil {
+	if err := unmarshalResponse(body, &replication); err != nil {
 		return nil, err
 	}
 	return replication, nil
@@ -764,7 +824,7 @@ func (client *NodeMgmtClient) ListTables(pod *corev1.Pod, keyspaceName string) (
 		return nil, err
 	}
 	var tables []string
-	if err := json.Unmarshal(body, &tables); err != nil {
+	if err := unmarshalResponse(body, &tables); err != nil {
 		return nil, err
 	}
 	return tables, nil
@@ -931,6 +991,30 @@ func (client *NodeMgmtClient) CallReloadSeedsEndpoint(pod *corev1.Pod) error {
 	return err
 }
 
+// CallReloadSslEndpoint reloads the SSL contexts of the node from its keystores and truststores,
+// like nodetool reloadssl. Check that the ReloadSsl feature is supported first.
+func (client *NodeMgmtClient) CallReloadSslEndpoint(pod *corev1.Pod) error {
+	client.Log.Info(
+		"calling Management API reload ssl - POST /api/v0/ops/node/ssl/reload",
+		"pod", pod.Name,
+	)
+
+	podHost, err := BuildPodHostFromPod(pod)
+	if err != nil {
+		return err
+	}
+
+	request := nodeMgmtRequest{
+		endpoint: "/api/v0/ops/node/ssl/reload",
+		host:     podHost,
+		method:   http.MethodPost,
+		timeout:  60 * time.Second,
+	}
+
+	_, err = callNodeMgmtEndpoint(client, request, "")
+	return err
+}
+
 // CallDecommissionNodeEndpoint is for the old /api/v0 decommission. Use the CallDecommissionNode for async behavior if available
 func (client *NodeMgmtClient) CallDecommissionNodeEndpoint(pod *corev1.Pod) error {
 	client.Log.Info(
@@ -954,6 +1038,36 @@ func (client *NodeMgmtClient) CallDecommissionNodeEndpoint(pod *corev1.Pod) erro
 	return err
 }
 
+// CallTakeSnapshotEndpoint takes a snapshot of all keyspaces on the node with the given snapshot name
+func (client *NodeMgmtClient) CallTakeSnapshotEndpoint(pod *corev1.Pod, snapshotName string) error {
+	client.Log.Info(
+		"calling Management API take snapshot - POST /api/v0/ops/node/snapshots",
+		"pod", pod.Name,
+		"snapshotName", snapshotName,
+	)
+
+	body, err := json.Marshal(map[string]string{"snapshot_name": snapshotName})
+	if err != nil {
+		return err
+	}
+
+	podHost, err := BuildPodHostFromPod(pod)
+	if err != nil {
+		return err
+	}
+
+	request := nodeMgmtRequest{
+		endpoint: "/api/v0/ops/node/snapshots",
+		host:     podHost,
+		method:   http.MethodPost,
+		timeout:  60 * time.Second,
+		body:     body,
+	}
+
+	_, err = callNodeMgmtEndpoint(client, request, "application/json")
+	return err
+}
+
 // CallDecommissionNode returns the job id of the decommission job.
 func (client *NodeMgmtClient) CallDecommissionNode(pod *corev1.Pod, force bool) (string, error) {
 	client.Log.Info(
@@ -1014,7 +1128,7 @@ func (client *NodeMgmtClient) FeatureSet(pod *corev1.Pod) (*FeatureSet, error) {
 	}
 
 	features := &FeatureSet{}
-	if err := json.Unmarshal(data, &features); err != nil {
+	if err := unmarshalResponse(data, &features); err != nil {
 		return nil, err
 	}
 
@@ -1049,7 +1163,7 @@ func (client *NodeMgmtClient) JobDetails(pod *corev1.Pod, jobId string) (*JobDet
 		return nil, err
 	}
 
-	if err := json.Unmarshal(data, job); err != nil {
+	if err := unmarshalResponse(data, job); err != nil {
 		return nil, err
 	}
 
@@ -1057,6 +1171,12 @@ func (client *NodeMgmtClient) JobDetails(pod *corev1.Pod, jobId string) (*JobDet
 }
 
 func callNodeMgmtEndpoint(client *NodeMgmtClient, request nodeMgmtRequest, contentType string) ([]byte, error) {
+	_, body, err := callNodeMgmtEndpointWithHeaders(client, request, contentType)
+	return body, err
+}
+
+// callNodeMgmtEndpointWithHeaders is callNodeMgmtEndpoint also returning the headers of the response
+func callNodeMgmtEndpointWithHeaders(client *NodeMgmtClient, request nodeMgmtRequest, contentType string) (http.Header, []byte, error) {
 	client.Log.Info("client::callNodeMgmtEndpoint")
 
 	url := fmt.Sprintf("%s://%s:8080%s", client.Protocol, request.host, request.endpoint)
@@ -1068,7 +1188,7 @@ func callNodeMgmtEndpoint(client *NodeMgmtClient, request nodeMgmtRequest, conte
 
 	req, err := http.NewRequest(request.method, url, reqBody)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	req.Close = true
 
@@ -1084,7 +1204,7 @@ func callNodeMgmtEndpoint(client *NodeMgmtClient, request nodeMgmtRequest, conte
 
 	res, err := client.Client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer func() {
@@ -1097,7 +1217,7 @@ func callNodeMgmtEndpoint(client *NodeMgmtClient, request nodeMgmtRequest, conte
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		client.Log.Error(err, "Unable to read response from Node Management Endpoint")
-		return nil, err
+		return nil, nil, err
 	}
 
 	goodStatus := res.StatusCode >= 200 && res.StatusCode < 300
@@ -1106,16 +1226,55 @@ func callNodeMgmtEndpoint(client *NodeMgmtClient, request nodeMgmtRequest, conte
 			StatusCode: res.StatusCode,
 			Err:        fmt.Errorf("incorrect status code of %d when calling endpoint", res.StatusCode),
 		}
+		// A proxy in front of the management API can answer with an HTML error page
+		if contentType := res.Header.Get("Content-Type"); strings.HasPrefix(contentType, "text/html") {
+			reqErr.Err = fmt.Errorf("incorrect status code of %d when calling endpoint: %w",
+				res.StatusCode, &NonJSONResponseError{ContentType: contentType, Body: truncateBody(body)})
+		}
 		if res.StatusCode != http.StatusNotFound {
 			client.Log.Error(reqErr, "incorrect status code when calling Node Management Endpoint",
 				"statusCode", res.StatusCode,
 				"pod", request.host)
 		}
 
-		return nil, reqErr
+		return nil, nil, reqErr
 	}
 
-	return body, nil
+	return res.Header, body, nil
+}
+
+// maxErrorBodyLength is the number of bytes of an unexpected response body kept in errors
+const maxErrorBodyLength = 256
+
+// NonJSONResponseError is returned when a response expected to be JSON is something else,
+// such as an HTML error page from a proxy. Body has the beginning of the response.
+type NonJSONResponseError struct {
+	ContentType string
+	Body        string
+}
+
+func (e *NonJSONResponseError) Error() string {
+	return fmt.Sprintf("expected a JSON response from the management API but got %s: %s", e.ContentType, e.Body)
+}
+
+// unmarshalResponse parses the JSON response of the management API, returning a
+// NonJSONResponseError rather than the JSON syntax error when the body is not JSON.
+func unmarshalResponse(body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		if !json.Valid(body) {
+			return &NonJSONResponseError{ContentType: http.DetectContentType(body), Body: truncateBody(body)}
+		}
+		return err
+	}
+	return nil
+}
+
+func truncateBody(body []byte) string {
+	trimmed := strings.TrimSpace(string(body))
+	if len(trimmed) > maxErrorBodyLength {
+		return trimmed[:maxErrorBodyLength] + "..."
+	}
+	return trimmed
 }
 
 type RequestError struct {
@@ -1127,6 +1286,10 @@ func (r *RequestError) Error() string {
 	return r.Err.Error()
 }
 
+func (r *RequestError) Unwrap() error {
+	return r.Err
+}
+
 func (r *RequestError) NotFound() bool {
 	return r.StatusCode == http.StatusNotFound
 }
@@ -1149,7 +1312,7 @@ func (client *NodeMgmtClient) CallIsFullQueryLogEnabledEndpoint(pod *corev1.Pod)
 		return false, err
 	}
 	var parsedResponse map[string]interface{}
-	err = json.Unmarshal([]byte(apiResponse), &parsedResponse)
+	err = unmarshalResponse(apiResponse, &parsedResponse)
 	if err != nil {
 		client.Log.Error(err, "failed to unmarshall JSON response from /api/v0/ops/node/fullquerylogging", "response", string(apiResponse))
 		return false, err
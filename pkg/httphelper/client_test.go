@@ -9,6 +9,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -177,6 +178,50 @@ func Test_parseListKeyspacesEndpointsResponseBody(t *testing.T) {
 	assert.Equal(t, "keyspace2", keyspaces[1])
 }
 
+func TestNodeMgmtClient_NonJSONResponse(t *testing.T) {
+	assert := assert.New(t)
+
+	htmlBody := "<html><head><title>502 Bad Gateway</title></head><body><h1>502 Bad Gateway</h1>" +
+		strings.Repeat("<p>nginx</p>", 50) + "</body></html>"
+	newHtmlResponse := func(statusCode int, contentType string) *http.Response {
+		return &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       io.NopCloser(strings.NewReader(htmlBody)),
+		}
+	}
+
+	// An error page is detected from the Content-Type of the response
+	mgmtClient := newMockMgmtClient(newMockHttpClient(newHtmlResponse(http.StatusBadGateway, "text/html; charset=utf-8"), nil))
+	_, err := mgmtClient.CallSchemaVersionsEndpoint(goodPod)
+	var reqErr *RequestError
+	assert.True(errors.As(err, &reqErr))
+	assert.Equal(http.StatusBadGateway, reqErr.StatusCode)
+	var nonJSONErr *NonJSONResponseError
+	assert.True(errors.As(err, &nonJSONErr))
+	assert.Equal("text/html; charset=utf-8", nonJSONErr.ContentType)
+	assert.True(strings.HasPrefix(err.Error(),
+		"incorrect status code of 502 when calling endpoint: expected a JSON response from the management API but got text/html; charset=utf-8: <html><head><title>502 Bad Gateway</title>"))
+	assert.True(strings.HasSuffix(err.Error(), "..."))
+	assert.Equal(maxErrorBodyLength+len("..."), len(nonJSONErr.Body))
+
+	// A successful response is only rejected if its body is not JSON
+	mgmtClient = newMockMgmtClient(newMockHttpClient(newHtmlResponse(http.StatusOK, "application/json"), nil))
+	_, err = mgmtClient.CallSchemaVersionsEndpoint(goodPod)
+	assert.True(errors.As(err, &nonJSONErr))
+	assert.Equal("text/html; charset=utf-8", nonJSONErr.ContentType)
+	assert.Contains(err.Error(), "502 Bad Gateway")
+
+	mgmtClient = newMockMgmtClient(newMockHttpClient(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/html"}},
+		Body:       io.NopCloser(strings.NewReader(`{"schema-1": ["10.0.0.1"]}`)),
+	}, nil))
+	versions, err := mgmtClient.CallSchemaVersionsEndpoint(goodPod)
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"schema-1": {"10.0.0.1"}}, versions)
+}
+
 func Test_featureSet(t *testing.T) {
 	assert := assert.New(t)
 
@@ -109,7 +109,7 @@ type InsecureManagementApiSecurityProvider struct {
 
 func buildInsecureManagementApiSecurityProvider(dc *api.CassandraDatacenter) (ManagementApiSecurityProvider, error) {
 	// If both are nil, then default to insecure
-	if dc.Spec.ManagementApiAuth.Insecure != nil || (dc.Spec.ManagementApiAuth.Manual == nil && dc.Spec.ManagementApiAuth.Insecure == nil) {
+	if dc.Spec.ManagementApiAuth.Insecure != nil || dc.Spec.ManagementApiAuth.IsEmpty() {
 		return &InsecureManagementApiSecurityProvider{}, nil
 	}
 	return nil, nil
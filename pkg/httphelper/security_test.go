@@ -4,6 +4,7 @@
 package httphelper
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"os"
@@ -11,6 +12,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 )
 
 func helperLoadBytes(t *testing.T, name string) []byte {
@@ -98,3 +104,50 @@ func Test_validatePrivateKey(t *testing.T) {
 		t, 1, len(errs),
 		"Should consider an empty key as an invalid key")
 }
+
+func Test_ManualManagementApiSecurityProvider_ValidateConfig(t *testing.T) {
+	makeSecret := func(name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}
+	}
+
+	provider := &ManualManagementApiSecurityProvider{
+		Namespace: "default",
+		Config: &api.ManagementApiAuthManualConfig{
+			ClientSecretName: "mgmt-api-client",
+			ServerSecretName: "mgmt-api-server",
+		},
+	}
+
+	t.Run("secret not found", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().Build()
+
+		errs := provider.ValidateConfig(fakeClient, context.TODO())
+		if assert.Len(t, errs, 1) {
+			assert.Contains(t, errs[0].Error(), ".managementApiAuth.manual.clientSecretName")
+			assert.Contains(t, errs[0].Error(), "mgmt-api-client")
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		clientSecret := makeSecret("mgmt-api-client", map[string][]byte{
+			"ca.crt":  helperLoadBytes(t, "ca.crt"),
+			"tls.crt": helperLoadBytes(t, "client.crt"),
+		})
+		serverSecret := makeSecret("mgmt-api-server", map[string][]byte{
+			"ca.crt":  helperLoadBytes(t, "ca.crt"),
+			"tls.crt": helperLoadBytes(t, "server.crt"),
+			"tls.key": helperLoadBytes(t, "server.key"),
+		})
+		fakeClient := fake.NewClientBuilder().WithObjects(clientSecret, serverSecret).Build()
+
+		errs := provider.ValidateConfig(fakeClient, context.TODO())
+		if assert.NotEmpty(t, errs) {
+			assert.Contains(t, errs[0].Error(), "mgmt-api-client")
+			assert.Contains(t, errs[0].Error(), "'tls.key'")
+		}
+	})
+}
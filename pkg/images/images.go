@@ -5,8 +5,10 @@ package images
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -161,10 +163,100 @@ func GetCassandraImage(serverType, version string) (string, error) {
 	return ApplyRegistry(fmt.Sprintf("%s:%s%s", prefix, version, suffix)), nil
 }
 
+// ImageForVersion returns the default server image for a known Cassandra or DSE version, the
+// server type being deduced from the version. Versions mapped in the image config are known too.
+func ImageForVersion(version string) (string, error) {
+	switch {
+	case IsDseVersionSupported(version):
+		return GetCassandraImage("dse", version)
+	case IsOssVersionSupported(version):
+		return GetCassandraImage("cassandra", version)
+	}
+
+	for _, serverType := range []string{"cassandra", "dse"} {
+		if found, image := getCassandraContainerImageOverride(serverType, version); found {
+			return ApplyRegistry(image), nil
+		}
+	}
+
+	return "", fmt.Errorf("no default image for server version '%s', set serverImage to the image to use", version)
+}
+
 func GetConfigBuilderImage() string {
 	return ApplyRegistry(GetImageConfig().Images.ConfigBuilder)
 }
 
+// ParseImageTag splits an image reference in its repository and tag. The tag is empty when the
+// image has none, or is only referenced by its digest.
+func ParseImageTag(image string) (string, string) {
+	if idx := strings.Index(image, "@"); idx >= 0 {
+		image = image[:idx]
+		// a tag before the digest is ignored by the container runtime anyway
+		if tagIdx := strings.LastIndex(image, ":"); tagIdx > strings.LastIndex(image, "/") {
+			return image[:tagIdx], ""
+		}
+		return image, ""
+	}
+
+	// a colon before the last slash separates the registry from its port
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		return image[:idx], image[idx+1:]
+	}
+	return image, ""
+}
+
+// Config builder features that depend on the version of the config builder image
+const (
+	ConfigBuilderEncryption           = "encryption"
+	ConfigBuilderCustomConfigSections = "custom-config-sections"
+)
+
+// configBuilderFeatures are the config builder features, in order, with the first version of the
+// config builder the operator relies on for them. The default config builder of the image config
+// supports all of them.
+var configBuilderFeatures = []struct {
+	name    string
+	version [3]int
+}{
+	{ConfigBuilderEncryption, [3]int{1, 0, 3}},
+	{ConfigBuilderCustomConfigSections, [3]int{1, 0, 4}},
+}
+
+var imageVersionRegexp = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)`)
+
+// ConfigBuilderFeatures returns the features supported by a config builder image, according to the
+// version in its tag, or nil if it supports none. An image without a version tag, such as latest
+// or a digest, is assumed to support all of them.
+func ConfigBuilderFeatures(image string) []string {
+	var version [3]int
+	_, tag := ParseImageTag(image)
+	match := imageVersionRegexp.FindStringSubmatch(tag)
+	for i := range version {
+		if match == nil {
+			version[i] = math.MaxInt32
+		} else {
+			version[i], _ = strconv.Atoi(match[i+1])
+		}
+	}
+
+	var features []string
+	for _, feature := range configBuilderFeatures {
+		if !versionLess(version, feature.version) {
+			features = append(features, feature.name)
+		}
+	}
+	return features
+}
+
+func versionLess(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
 func GetSystemLoggerImage() string {
 	return ApplyRegistry(GetImageConfig().Images.SystemLogger)
 }
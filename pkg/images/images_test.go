@@ -121,3 +121,62 @@ func TestOssValidVersions(t *testing.T) {
 	assert.False(IsOssVersionSupported("4.1"))
 	assert.False(IsOssVersionSupported("6.8.0"))
 }
+
+func TestImageForVersion(t *testing.T) {
+	assert := assert.New(t)
+	imageConfig = &configv1beta1.ImageConfig{}
+	imageConfig.Images = &configv1beta1.Images{}
+
+	path, err := ImageForVersion("4.0.1")
+	assert.NoError(err)
+	assert.Equal("k8ssandra/cass-management-api:4.0.1", path)
+
+	path, err = ImageForVersion("6.8.17")
+	assert.NoError(err)
+	assert.Equal("datastax/dse-server:6.8.17", path)
+
+	_, err = ImageForVersion("2.2.19")
+	assert.EqualError(err, "no default image for server version '2.2.19', set serverImage to the image to use")
+
+	// Versions mapped in the image config are known
+	imageConfig.Images.CassandraVersions = map[string]string{
+		"2.2.19": "my-custom-image:2.2.19",
+	}
+	path, err = ImageForVersion("2.2.19")
+	assert.NoError(err)
+	assert.Equal("my-custom-image:2.2.19", path)
+}
+
+func TestParseImageTag(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		image      string
+		repository string
+		tag        string
+	}{
+		{"datastax/cass-config-builder:1.0.4-ubi7", "datastax/cass-config-builder", "1.0.4-ubi7"},
+		{"localhost:5000/datastax/cass-config-builder:1.0.3", "localhost:5000/datastax/cass-config-builder", "1.0.3"},
+		{"localhost:5000/datastax/cass-config-builder", "localhost:5000/datastax/cass-config-builder", ""},
+		{"datastax/cass-config-builder@sha256:abcd", "datastax/cass-config-builder", ""},
+		{"datastax/cass-config-builder:1.0.4@sha256:abcd", "datastax/cass-config-builder", ""},
+	}
+	for _, tt := range tests {
+		repository, tag := ParseImageTag(tt.image)
+		assert.Equal(tt.repository, repository, tt.image)
+		assert.Equal(tt.tag, tag, tt.image)
+	}
+}
+
+func TestConfigBuilderFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(ConfigBuilderFeatures("datastax/cass-config-builder:1.0.2"))
+	assert.Equal([]string{ConfigBuilderEncryption}, ConfigBuilderFeatures("datastax/cass-config-builder:1.0.3-ubi7"))
+	assert.Equal([]string{ConfigBuilderEncryption, ConfigBuilderCustomConfigSections}, ConfigBuilderFeatures("datastax/cass-config-builder:1.0.4-ubi7"))
+	assert.Equal([]string{ConfigBuilderEncryption, ConfigBuilderCustomConfigSections}, ConfigBuilderFeatures("datastax/cass-config-builder:v1.1.0"))
+
+	// without a version, the image is assumed to be recent
+	assert.Equal([]string{ConfigBuilderEncryption, ConfigBuilderCustomConfigSections}, ConfigBuilderFeatures("datastax/cass-config-builder:latest"))
+	assert.Equal([]string{ConfigBuilderEncryption, ConfigBuilderCustomConfigSections}, ConfigBuilderFeatures("datastax/cass-config-builder@sha256:abcd"))
+}
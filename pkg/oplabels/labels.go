@@ -5,6 +5,8 @@ package oplabels
 
 import (
 	"fmt"
+	"sort"
+
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 )
 
@@ -20,6 +22,40 @@ const (
 	CreatedByLabelValue        = "cassandradatacenter_controller"
 )
 
+// labelMigrations maps the label keys of previous operator versions to the keys that replaced them
+var labelMigrations = map[string]string{}
+
+// SetLabelMigrations sets the label keys to migrate, from the old key to the new one
+func SetLabelMigrations(migrations map[string]string) {
+	labelMigrations = map[string]string{}
+	for oldKey, newKey := range migrations {
+		labelMigrations[oldKey] = newKey
+	}
+}
+
+// HasLabelMigrations returns true if label keys to migrate are configured
+func HasLabelMigrations() bool {
+	return len(labelMigrations) > 0
+}
+
+// MigrateLabels adds the new key for each old key present in m. The old keys are kept so that
+// existing selectors still match. Returns true if m was modified.
+func MigrateLabels(m map[string]string) bool {
+	updated := false
+	for oldKey, newKey := range labelMigrations {
+		if value, found := m[oldKey]; found {
+			if current, exists := m[newKey]; !exists || current != value {
+				m[newKey] = value
+				updated = true
+			}
+		}
+	}
+	return updated
+}
+
+// AddOperatorLabels sets the labels managed by the operator in m, followed by the additional labels
+// of the dc. An additional label never replaces a label already in m, so the operator labels (and
+// any label the caller set beforehand) win on conflict.
 func AddOperatorLabels(m map[string]string, dc *api.CassandraDatacenter) {
 	m[ManagedByLabel] = ManagedByLabelValue
 	m[NameLabel] = NameLabelValue
@@ -27,13 +63,58 @@ func AddOperatorLabels(m map[string]string, dc *api.CassandraDatacenter) {
 	m[InstanceLabel] = fmt.Sprintf("cassandra-%s", api.CleanLabelValue(dc.Spec.ClusterName))
 	m[CreatedByLabel] = CreatedByLabelValue
 
-	if len(dc.Spec.AdditionalLabels) != 0 {
-		for key, value := range dc.Spec.AdditionalLabels {
+	addIfAbsent(m, dc.Spec.AdditionalLabels)
+}
+
+// AddOperatorAnnotations sets the additional annotations of the dc in m, without replacing the
+// annotations already in m.
+func AddOperatorAnnotations(m map[string]string, dc *api.CassandraDatacenter) {
+	addIfAbsent(m, dc.Spec.AdditionalAnnotations)
+}
+
+func addIfAbsent(m map[string]string, additional map[string]string) {
+	for key, value := range additional {
+		if _, found := m[key]; !found {
 			m[key] = value
 		}
 	}
 }
 
+// RemovedAdditionalLabels returns the additional label keys applied by a previous reconcile that are
+// no longer in the spec, and have to be removed from the managed resources
+func RemovedAdditionalLabels(dc *api.CassandraDatacenter) []string {
+	return removedKeys(dc.Status.AdditionalLabelKeys, dc.Spec.AdditionalLabels)
+}
+
+// RemovedAdditionalAnnotations returns the additional annotation keys applied by a previous reconcile
+// that are no longer in the spec, and have to be removed from the managed resources
+func RemovedAdditionalAnnotations(dc *api.CassandraDatacenter) []string {
+	return removedKeys(dc.Status.AdditionalAnnotationKeys, dc.Spec.AdditionalAnnotations)
+}
+
+func removedKeys(applied []string, current map[string]string) []string {
+	var removed []string
+	for _, key := range applied {
+		if _, found := current[key]; !found {
+			removed = append(removed, key)
+		}
+	}
+	return removed
+}
+
+// SortedKeys returns the keys of m in order, as recorded in the status
+func SortedKeys(m map[string]string) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func AddDefunctManagedByLabel(m map[string]string) {
 	m[ManagedByLabel] = ManagedByLabelDefunctValue
 }
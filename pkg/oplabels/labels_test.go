@@ -62,3 +62,55 @@ func TestWhitelistRegex(t *testing.T) {
 	regexpResult = whitelistRegex.FindAllString(strings.Replace(unclean, " ", "", -1), -1)
 	require.EqualValues(t, "corre_c-t-.LABEL.name-1", strings.Join(regexpResult, ""))
 }
+
+func TestAddOperatorLabels_OperatorLabelsWin(t *testing.T) {
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			ClusterName:   "cluster1",
+			ServerVersion: "4.0.1",
+			AdditionalLabels: map[string]string{
+				ManagedByLabel: "someone-else",
+				VersionLabel:   "0.0.1",
+				ClusterLabel:   "other-cluster",
+				"cost-center":  "db",
+			},
+		},
+	}
+
+	labels := dc.GetClusterLabels()
+	AddOperatorLabels(labels, dc)
+
+	require.Equal(t, ManagedByLabelValue, labels[ManagedByLabel])
+	require.Equal(t, "4.0.1", labels[VersionLabel])
+	require.Equal(t, "cluster1", labels[ClusterLabel])
+	require.Equal(t, "db", labels["cost-center"])
+}
+
+func TestAddOperatorAnnotations(t *testing.T) {
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			AdditionalAnnotations: map[string]string{"cost-center": "db", "owner": "team-a"},
+		},
+	}
+
+	annotations := map[string]string{"owner": "team-b"}
+	AddOperatorAnnotations(annotations, dc)
+	require.Equal(t, map[string]string{"cost-center": "db", "owner": "team-b"}, annotations)
+}
+
+func TestRemovedAdditionalLabels(t *testing.T) {
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			AdditionalLabels:      map[string]string{"kept": "true"},
+			AdditionalAnnotations: map[string]string{"kept": "true", "added": "true"},
+		},
+		Status: CassandraDatacenterStatus{
+			AdditionalLabelKeys:      []string{"dropped", "kept"},
+			AdditionalAnnotationKeys: []string{"kept"},
+		},
+	}
+
+	require.Equal(t, []string{"dropped"}, RemovedAdditionalLabels(dc))
+	require.Empty(t, RemovedAdditionalAnnotations(dc))
+	require.Equal(t, []string{"added", "kept"}, SortedKeys(dc.Spec.AdditionalAnnotations))
+}
@@ -5,12 +5,22 @@ package reconciliation
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/k8ssandra/cass-operator/pkg/events"
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/k8ssandra/cass-operator/pkg/internal/result"
 	"github.com/k8ssandra/cass-operator/pkg/utils"
 
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
@@ -73,6 +83,844 @@ func (rc *ReconciliationContext) removePVC(pvc *corev1.PersistentVolumeClaim) er
 	return nil
 }
 
+// CheckVolumeExpansion grows the server data PVCs of the pods to the storage size requested in the
+// StorageConfig when their storage class allows volume expansion, claims are never shrunk. The
+// ResizingVolumes condition is set until the capacity of every PVC reflects the new size, the
+// VolumeExpansionNotAllowed condition names the PVCs whose storage class does not allow it.
+func (rc *ReconciliationContext) CheckVolumeExpansion() result.ReconcileResult {
+	claimSpec := rc.Datacenter.Spec.StorageConfig.CassandraDataVolumeClaimSpec
+	if claimSpec == nil {
+		return result.Continue()
+	}
+	desiredSize, found := claimSpec.Resources.Requests[corev1.ResourceStorage]
+	if !found {
+		return result.Continue()
+	}
+
+	resizing := []string{}
+	notExpandable := []string{}
+	for _, pod := range rc.dcPods {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvcName := types.NamespacedName{Namespace: pod.Namespace, Name: fmt.Sprintf("%s-%s", PvcName, pod.Name)}
+		if err := rc.Client.Get(rc.Ctx, pvcName, pvc); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return result.Error(err)
+		}
+		if pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+
+		requestedSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if desiredSize.Cmp(requestedSize) > 0 {
+			allowed, err := rc.storageClassAllowsExpansion(pvc)
+			if err != nil {
+				return result.Error(err)
+			}
+			if !allowed {
+				notExpandable = append(notExpandable, pvc.Name)
+				continue
+			}
+
+			rc.ReqLogger.Info("Expanding PVC", "pvc", pvc.Name, "from", requestedSize.String(), "to", desiredSize.String())
+			pvcPatch := client.MergeFrom(pvc.DeepCopy())
+			if pvc.Spec.Resources.Requests == nil {
+				pvc.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = desiredSize
+			if err := rc.Client.Patch(rc.Ctx, pvc, pvcPatch); err != nil {
+				rc.ReqLogger.Error(err, "error expanding PVC", "pvc", pvc.Name)
+				return result.Error(err)
+			}
+			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.ExpandingVolume,
+				"Expanding PVC %s from %s to %s", pvc.Name, requestedSize.String(), desiredSize.String())
+			requestedSize = desiredSize
+		}
+
+		capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		if requestedSize.Cmp(capacity) > 0 {
+			resizing = append(resizing, pvc.Name)
+		}
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	notAllowedChanged := false
+	if len(notExpandable) > 0 || rc.Datacenter.GetConditionStatus(api.DatacenterVolumeExpansionNotAllowed) == corev1.ConditionTrue {
+		condition := api.NewDatacenterCondition(api.DatacenterVolumeExpansionNotAllowed, corev1.ConditionFalse)
+		if len(notExpandable) > 0 {
+			condition = api.NewDatacenterConditionWithReason(api.DatacenterVolumeExpansionNotAllowed, corev1.ConditionTrue,
+				"StorageClassNotExpandable", fmt.Sprintf("storage class of PVCs %s does not allow volume expansion", strings.Join(notExpandable, ", ")))
+		}
+		notAllowedChanged = rc.setCondition(condition)
+	}
+
+	resizingChanged := false
+	if len(resizing) > 0 || rc.Datacenter.GetConditionStatus(api.DatacenterResizingVolumes) == corev1.ConditionTrue {
+		condition := api.NewDatacenterCondition(api.DatacenterResizingVolumes, corev1.ConditionFalse)
+		if len(resizing) > 0 {
+			condition = api.NewDatacenterConditionWithReason(api.DatacenterResizingVolumes, corev1.ConditionTrue,
+				"ExpandingVolumes", fmt.Sprintf("expanding PVCs %s to %s", strings.Join(resizing, ", "), desiredSize.String()))
+		}
+		resizingChanged = rc.setCondition(condition)
+	}
+
+	if notAllowedChanged || resizingChanged {
+		if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+			rc.ReqLogger.Error(err, "error patching datacenter status for volume expansion")
+			return result.Error(err)
+		}
+	}
+	// The PVCs that can't be expanded are only reported once, not on every reconcile
+	if notAllowedChanged && len(notExpandable) > 0 {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.VolumeExpansionNotAllowed,
+			"Storage class of PVCs %s does not allow volume expansion, they keep their size", strings.Join(notExpandable, ", "))
+	}
+	if resizingChanged && len(resizing) == 0 {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.ExpandedVolumes,
+			"Finished expanding the PVCs to %s", desiredSize.String())
+	}
+
+	if len(resizing) > 0 {
+		return result.RequeueSoon(10)
+	}
+	return result.Continue()
+}
+
+func (rc *ReconciliationContext) storageClassAllowsExpansion(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc.Spec.StorageClassName == nil {
+		return false, nil
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	if err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, storageClass); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+// CheckStalePVCSelectedNodes clears the selected-node annotation of PVCs that are not bound yet
+// when the node it references no longer exists. With WaitForFirstConsumer volumes, the pod would
+// otherwise stay unschedulable as the volume can't be provisioned on the vanished node.
+func (rc *ReconciliationContext) CheckStalePVCSelectedNodes() result.ReconcileResult {
+	for _, pod := range rc.dcPods {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvcName := types.NamespacedName{Namespace: pod.Namespace, Name: fmt.Sprintf("%s-%s", PvcName, pod.Name)}
+		if err := rc.Client.Get(rc.Ctx, pvcName, pvc); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return result.Error(err)
+		}
+
+		nodeName := utils.GetPVCSelectedNodeName(pvc)
+		if pvc.Status.Phase != corev1.ClaimPending || nodeName == "" {
+			continue
+		}
+
+		if _, err := rc.getNode(nodeName); err == nil {
+			continue
+		} else if !errors.IsNotFound(err) {
+			return result.Error(err)
+		}
+
+		rc.ReqLogger.Info("Clearing stale selected node from PVC", "pvc", pvc.Name, "node", nodeName)
+		patch := client.MergeFrom(pvc.DeepCopy())
+		delete(pvc.Annotations, utils.PVCSelectedNodeAnnotation)
+		if err := rc.Client.Patch(rc.Ctx, pvc, patch); err != nil {
+			return result.Error(err)
+		}
+	}
+
+	return result.Continue()
+}
+
+// recordVolumeOwner records the UID of the datacenter on the bound server data PVC and on its
+// PersistentVolume, so that the volume can be told apart from the volumes of a previous datacenter
+// with the same name once it is released
+func (rc *ReconciliationContext) recordVolumeOwner(pvc *corev1.PersistentVolumeClaim, pv *corev1.PersistentVolume) error {
+	owner := string(rc.Datacenter.UID)
+
+	if pv.Annotations[api.VolumeOwnerAnnotation] != owner {
+		pvPatch := client.MergeFrom(pv.DeepCopy())
+		metav1.SetMetaDataAnnotation(&pv.ObjectMeta, api.VolumeOwnerAnnotation, owner)
+		if err := rc.Client.Patch(rc.Ctx, pv, pvPatch); err != nil {
+			return err
+		}
+	}
+
+	pvcPatch := client.MergeFrom(pvc.DeepCopy())
+	metav1.SetMetaDataAnnotation(&pvc.ObjectMeta, api.VolumeOwnerAnnotation, owner)
+	return rc.Client.Patch(rc.Ctx, pvc, pvcPatch)
+}
+
+// CheckReleasedVolumes recreates the missing server data PVCs of the datacenter when the
+// volume they were bound to was retained, so that the node comes back with its data. Only the
+// volumes recorded as bound to this datacenter are rebound, and only for the pods that exist.
+func (rc *ReconciliationContext) CheckReleasedVolumes() result.ReconcileResult {
+	if !rc.Datacenter.Spec.RebindReleasedVolumes {
+		return result.Continue()
+	}
+
+	pvcList, err := rc.listPVCs()
+	if err != nil {
+		return result.Error(err)
+	}
+
+	owner := string(rc.Datacenter.UID)
+	claims := make(map[string]bool, len(pvcList.Items))
+	var unrecorded []*corev1.PersistentVolumeClaim
+	for idx := range pvcList.Items {
+		pvc := &pvcList.Items[idx]
+		claims[pvc.Name] = true
+		if strings.HasPrefix(pvc.Name, PvcName+"-") && pvc.Status.Phase == corev1.ClaimBound &&
+			pvc.Spec.VolumeName != "" && pvc.Annotations[api.VolumeOwnerAnnotation] != owner {
+			unrecorded = append(unrecorded, pvc)
+		}
+	}
+
+	type missingClaim struct {
+		name     string
+		template *corev1.PersistentVolumeClaim
+	}
+	var missing []missingClaim
+	for _, statefulSet := range rc.statefulSets {
+		if statefulSet == nil {
+			continue
+		}
+
+		var template *corev1.PersistentVolumeClaim
+		for i := range statefulSet.Spec.VolumeClaimTemplates {
+			if statefulSet.Spec.VolumeClaimTemplates[i].Name == PvcName {
+				template = &statefulSet.Spec.VolumeClaimTemplates[i]
+			}
+		}
+		if template == nil {
+			continue
+		}
+
+		// The claims of the pods that don't exist yet, during a scale up for instance, are left to
+		// the StatefulSet controller
+		for _, pod := range rc.dcPods {
+			pvcName := fmt.Sprintf("%s-%s", PvcName, pod.Name)
+			if strings.HasPrefix(pod.Name, statefulSet.Name+"-") && !claims[pvcName] {
+				missing = append(missing, missingClaim{name: pvcName, template: template})
+			}
+		}
+	}
+
+	if len(unrecorded) == 0 && len(missing) == 0 {
+		return result.Continue()
+	}
+
+	pvList := &corev1.PersistentVolumeList{}
+	if err := rc.Client.List(rc.Ctx, pvList); err != nil {
+		return result.Error(err)
+	}
+
+	volumes := make(map[string]*corev1.PersistentVolume, len(pvList.Items))
+	released := make(map[string]*corev1.PersistentVolume)
+	for idx := range pvList.Items {
+		pv := &pvList.Items[idx]
+		volumes[pv.Name] = pv
+		claimRef := pv.Spec.ClaimRef
+		if pv.Status.Phase == corev1.VolumeReleased && claimRef != nil &&
+			claimRef.Namespace == rc.Datacenter.Namespace && pv.Annotations[api.VolumeOwnerAnnotation] == owner {
+			released[claimRef.Name] = pv
+		}
+	}
+
+	for _, pvc := range unrecorded {
+		pv, found := volumes[pvc.Spec.VolumeName]
+		if !found || pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.UID != pvc.UID {
+			continue
+		}
+		if err := rc.recordVolumeOwner(pvc, pv); err != nil {
+			return result.Error(err)
+		}
+	}
+
+	for _, claim := range missing {
+		pv, found := released[claim.name]
+		if !found {
+			continue
+		}
+
+		// A Released volume keeps the reference to its deleted claim, forget it so that the
+		// volume becomes available again to the new claim
+		pvPatch := client.MergeFrom(pv.DeepCopy())
+		pv.Spec.ClaimRef.UID = ""
+		pv.Spec.ClaimRef.ResourceVersion = ""
+		if err := rc.Client.Patch(rc.Ctx, pv, pvPatch); err != nil {
+			return result.Error(err)
+		}
+
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        claim.name,
+				Namespace:   rc.Datacenter.Namespace,
+				Labels:      utils.MergeMap(map[string]string{}, claim.template.Labels),
+				Annotations: utils.MergeMap(map[string]string{}, claim.template.Annotations),
+			},
+			Spec: *claim.template.Spec.DeepCopy(),
+		}
+		pvc.Spec.VolumeName = pv.Name
+
+		rc.ReqLogger.Info("Recreating PVC bound to its released volume", "pvc", claim.name, "volume", pv.Name)
+		if err := rc.Client.Create(rc.Ctx, pvc); err != nil {
+			return result.Error(err)
+		}
+
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.RecreatedPersistentVolumeClaim,
+			"Recreated PVC %s bound to released volume %s", claim.name, pv.Name)
+	}
+
+	return result.Continue()
+}
+
+// CheckRemovedRackPVCs deletes the PVCs of the racks that are no longer part of the datacenter,
+// once all the pods of those racks are gone.
+func (rc *ReconciliationContext) CheckRemovedRackPVCs() result.ReconcileResult {
+	if !rc.Datacenter.Spec.ReclaimRemovedRackPVCs {
+		return result.Continue()
+	}
+
+	desiredRacks := utils.StringSet{}
+	for _, rack := range rc.Datacenter.GetRacks() {
+		desiredRacks[rack.Name] = true
+	}
+
+	pvcList, err := rc.listPVCs()
+	if err != nil {
+		return result.Error(err)
+	}
+	pvcs := make([]*corev1.PersistentVolumeClaim, 0, len(pvcList.Items))
+	for i := range pvcList.Items {
+		pvcs = append(pvcs, &pvcList.Items[i])
+	}
+	removedRackPVCs := utils.FilterPVCsWithFn(pvcs, func(pvc *corev1.PersistentVolumeClaim) bool {
+		rackName, found := pvc.Labels[api.RackLabel]
+		return found && !desiredRacks[rackName]
+	})
+	if len(removedRackPVCs) == 0 {
+		return result.Continue()
+	}
+
+	podList, err := rc.listPods(rc.Datacenter.GetDatacenterLabels())
+	if err != nil {
+		return result.Error(err)
+	}
+	racksWithPods := utils.StringSet{}
+	for _, pod := range podList.Items {
+		racksWithPods[pod.Labels[api.RackLabel]] = true
+	}
+
+	reclaimed := map[string][]string{}
+	for _, pvc := range removedRackPVCs {
+		rackName := pvc.Labels[api.RackLabel]
+		if racksWithPods[rackName] {
+			rc.ReqLogger.Info("Waiting for the pods of the removed rack to be gone before reclaiming its PVCs", "rack", rackName)
+			continue
+		}
+		if err := rc.Client.Delete(rc.Ctx, pvc); err != nil && !errors.IsNotFound(err) {
+			rc.ReqLogger.Error(err, "error deleting PVC of removed rack", "pvc", pvc.Name)
+			return result.Error(err)
+		}
+		reclaimed[rackName] = append(reclaimed[rackName], pvc.Name)
+	}
+
+	reclaimedRacks := make([]string, 0, len(reclaimed))
+	for rackName := range reclaimed {
+		reclaimedRacks = append(reclaimedRacks, rackName)
+	}
+	sort.Strings(reclaimedRacks)
+	for _, rackName := range reclaimedRacks {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.ReclaimedRackPVCs,
+			"Deleted PVCs %s of removed rack %s", strings.Join(reclaimed[rackName], ", "), rackName)
+	}
+
+	return result.Continue()
+}
+
+// discoverRackTolerations returns tolerations for the taints that all the nodes targeted by the
+// node affinity labels of the rack have in common, and that are not already tolerated. It returns
+// false when the rack targets nodes but none of them is found.
+func (rc *ReconciliationContext) discoverRackTolerations(rackName string, nodes []*corev1.Node) ([]corev1.Toleration, bool, error) {
+	nodeAffinityLabels, err := rackNodeAffinitylabels(rc.Datacenter, rackName)
+	if err != nil || len(nodeAffinityLabels) == 0 {
+		return nil, err == nil, err
+	}
+
+	selector := labels.SelectorFromSet(nodeAffinityLabels)
+	rackNodes := utils.FilterNodesWithFn(nodes, func(node *corev1.Node) bool {
+		return selector.Matches(labels.Set(node.Labels))
+	})
+	if len(rackNodes) == 0 {
+		return nil, false, nil
+	}
+
+	tolerations := []corev1.Toleration{}
+	for _, taint := range rackNodes[0].Spec.Taints {
+		taint := taint
+		// Taints managed by the node lifecycle controller are not meant to be tolerated
+		if strings.HasPrefix(taint.Key, "node.kubernetes.io/") || strings.HasPrefix(taint.Key, "node.cloudprovider.kubernetes.io/") {
+			continue
+		}
+		if len(utils.FilterNodesWithTaintKeyValueEffect(rackNodes, taint.Key, taint.Value, taint.Effect)) != len(rackNodes) {
+			continue
+		}
+		if isTaintTolerated(rc.Datacenter.Spec.Tolerations, &taint) {
+			continue
+		}
+		tolerations = append(tolerations, corev1.Toleration{
+			Key:      taint.Key,
+			Operator: corev1.TolerationOpEqual,
+			Value:    taint.Value,
+			Effect:   taint.Effect,
+		})
+	}
+
+	return tolerations, true, nil
+}
+
+// nodeZone returns the zone of a worker node, from its topology.kubernetes.io/zone label or the
+// deprecated failure-domain.beta.kubernetes.io/zone one
+func nodeZone(node *corev1.Node) string {
+	if zone, found := node.Labels[corev1.LabelTopologyZone]; found {
+		return zone
+	}
+	return node.Labels[zoneLabel]
+}
+
+// FindRebalanceCandidates returns the pods to reschedule to spread the pods of each rack across the
+// zones of the worker nodes matching the node affinity labels of the rack. A pod is a candidate when
+// its zone hosts at least two more pods of its rack than another zone with a node free for the rack,
+// or when it shares a worker node while a node of its own zone is free for the rack. On each shared
+// node, the first pod by name stays where it is. The pods sharing a node come first.
+func FindRebalanceCandidates(pods []*corev1.Pod, nodes []*corev1.Node, rackAffinityLabels map[string]map[string]string) []*corev1.Pod {
+	nodesByName := map[string]*corev1.Node{}
+	for _, node := range nodes {
+		nodesByName[node.Name] = node
+	}
+
+	podsByNode := map[string][]*corev1.Pod{}
+	rackZonePods := map[string]map[string]int{}
+	for _, pod := range pods {
+		node, found := nodesByName[pod.Spec.NodeName]
+		if !found {
+			continue
+		}
+		podsByNode[node.Name] = append(podsByNode[node.Name], pod)
+
+		rack := pod.Labels[api.RackLabel]
+		if rackZonePods[rack] == nil {
+			rackZonePods[rack] = map[string]int{}
+		}
+		rackZonePods[rack][nodeZone(node)]++
+	}
+
+	sharingNode := map[*corev1.Pod]bool{}
+	for _, nodePods := range podsByNode {
+		sort.Slice(nodePods, func(i, j int) bool { return nodePods[i].Name < nodePods[j].Name })
+		for _, pod := range nodePods[1:] {
+			sharingNode[pod] = true
+		}
+	}
+
+	freeNodes := utils.FilterNodesWithFn(nodes, func(node *corev1.Node) bool {
+		_, used := podsByNode[node.Name]
+		return !used && !node.Spec.Unschedulable && utils.IsNodeReady(node)
+	})
+
+	candidates := []*corev1.Pod{}
+	for _, pod := range pods {
+		node, found := nodesByName[pod.Spec.NodeName]
+		if !found {
+			continue
+		}
+		rack := pod.Labels[api.RackLabel]
+		zone := nodeZone(node)
+		selector := labels.SelectorFromSet(rackAffinityLabels[rack])
+		for _, freeNode := range freeNodes {
+			if !selector.Matches(labels.Set(freeNode.Labels)) {
+				continue
+			}
+			freeZone := nodeZone(freeNode)
+			if (freeZone == zone && sharingNode[pod]) || rackZonePods[rack][freeZone] < rackZonePods[rack][zone]-1 {
+				candidates = append(candidates, pod)
+				break
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if sharingNode[candidates[i]] != sharingNode[candidates[j]] {
+			return sharingNode[candidates[i]]
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	return candidates
+}
+
+// CheckPodRebalancing deletes a pod returned by FindRebalanceCandidates, if pod rebalancing is
+// enabled and the maintenance window is open. The next pod is only rebalanced once all the pods of
+// the datacenter exist and are ready again, and none is being deleted.
+func (rc *ReconciliationContext) CheckPodRebalancing() result.ReconcileResult {
+	config := rc.Datacenter.Spec.PodRebalancing
+	if config == nil || !config.Enabled || !config.InMaintenanceWindow(time.Now()) {
+		return result.Continue()
+	}
+
+	if len(rc.dcPods) != int(rc.Datacenter.Spec.Size) || len(findAllPodsNotReady(rc.dcPods)) > 0 {
+		return result.Continue()
+	}
+	for _, pod := range rc.dcPods {
+		if pod.DeletionTimestamp != nil {
+			return result.Continue()
+		}
+	}
+
+	nodes, err := rc.GetAllNodes()
+	if err != nil {
+		return result.Error(err)
+	}
+
+	rackAffinityLabels, err := rc.rackAffinityLabels()
+	if err != nil {
+		return result.Error(err)
+	}
+
+	candidates := FindRebalanceCandidates(rc.dcPods, nodes, rackAffinityLabels)
+	if len(candidates) == 0 {
+		return result.Continue()
+	}
+
+	pod := candidates[0]
+	rc.ReqLogger.Info("Rebalancing pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+	rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.RebalancingPod,
+		"Deleting pod %s on node %s to reschedule it and spread the pods of its rack", pod.Name, pod.Spec.NodeName)
+	if err := rc.RemovePod(pod); err != nil {
+		return result.Error(err)
+	}
+
+	return result.RequeueSoon(10)
+}
+
+// rackAffinityLabels returns the node affinity labels of each rack, by rack name.
+func (rc *ReconciliationContext) rackAffinityLabels() (map[string]map[string]string, error) {
+	rackAffinityLabels := map[string]map[string]string{}
+	for _, rackInfo := range rc.desiredRackInformation {
+		affinityLabels, err := rackNodeAffinitylabels(rc.Datacenter, rackInfo.RackName)
+		if err != nil {
+			return nil, err
+		}
+		rackAffinityLabels[rackInfo.RackName] = affinityLabels
+	}
+	return rackAffinityLabels, nil
+}
+
+// IsPodUnschedulable returns true if the scheduler found no node to run the pending pod on
+func IsPodUnschedulable(pod *corev1.Pod) bool {
+	_, unschedulable := podUnschedulableCondition(pod)
+	return unschedulable
+}
+
+func podUnschedulableCondition(pod *corev1.Pod) (corev1.PodCondition, bool) {
+	if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+		return corev1.PodCondition{}, false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			return condition, condition.Reason == corev1.PodReasonUnschedulable
+		}
+	}
+	return corev1.PodCondition{}, false
+}
+
+// CheckUnschedulableRacks sets the UnschedulableRacks condition, naming the racks with a pod the
+// scheduler can't place, which usually means no node matches the node affinity labels of the rack.
+// A warning event is emitted for each rack when the list of racks changes.
+func (rc *ReconciliationContext) CheckUnschedulableRacks() result.ReconcileResult {
+	unschedulableRacks := []string{}
+	podConditions := map[string]string{}
+	for _, rackInfo := range rc.desiredRackInformation {
+		rackPods := FilterPodListByLabels(rc.dcPods, rc.Datacenter.GetRackLabels(rackInfo.RackName))
+		for _, pod := range rackPods {
+			condition, unschedulable := podUnschedulableCondition(pod)
+			if !unschedulable {
+				continue
+			}
+			unschedulableRacks = append(unschedulableRacks, rackInfo.RackName)
+			podConditions[rackInfo.RackName] = fmt.Sprintf("pod %s: %s", pod.Name, condition.Message)
+			break
+		}
+	}
+
+	if len(unschedulableRacks) == 0 && rc.Datacenter.GetConditionStatus(api.DatacenterUnschedulableRacks) != corev1.ConditionTrue {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	condition := api.NewDatacenterCondition(api.DatacenterUnschedulableRacks, corev1.ConditionFalse)
+	if len(unschedulableRacks) > 0 {
+		condition = api.NewDatacenterConditionWithReason(api.DatacenterUnschedulableRacks, corev1.ConditionTrue,
+			"PodUnschedulable", fmt.Sprintf("Racks unschedulable: %s", strings.Join(unschedulableRacks, ", ")))
+	}
+	current, _ := rc.Datacenter.GetCondition(api.DatacenterUnschedulableRacks)
+	if !rc.setCondition(condition) {
+		if current.Message == condition.Message {
+			return result.Continue()
+		}
+		// Other racks are unschedulable, the transition time is kept
+		rc.Datacenter.SetCondition(*condition)
+	}
+
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for unschedulable racks")
+		return result.Error(err)
+	}
+
+	for _, rackName := range unschedulableRacks {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.UnschedulableRack,
+			"Rack %s can't be scheduled, %s", rackName, podConditions[rackName])
+	}
+
+	return result.Continue()
+}
+
+// FindSchedulingDeadlocks returns the unschedulable pods whose volume waits for its first consumer
+// while no node of their rack can take them: the nodes matching the node affinity labels of the rack
+// are not ready, cordoned or, unless multiple nodes per worker are allowed, already host a pod of the
+// datacenter. waitingPVCs holds the unbound WaitForFirstConsumer PVCs, by pod name.
+func FindSchedulingDeadlocks(pods []*corev1.Pod, waitingPVCs map[string]*corev1.PersistentVolumeClaim, nodes []*corev1.Node, rackAffinityLabels map[string]map[string]string, allowMultipleNodesPerWorker bool) []*corev1.Pod {
+	usedNodes := getPodsNodeNameSet(pods)
+	availableNodes := utils.FilterNodesWithFn(nodes, func(node *corev1.Node) bool {
+		if !allowMultipleNodesPerWorker && usedNodes[node.Name] {
+			return false
+		}
+		return !node.Spec.Unschedulable && utils.IsNodeReady(node)
+	})
+
+	deadlocked := []*corev1.Pod{}
+	for _, pod := range pods {
+		if !IsPodUnschedulable(pod) {
+			continue
+		}
+		if _, waiting := waitingPVCs[pod.Name]; !waiting {
+			continue
+		}
+
+		selector := labels.SelectorFromSet(rackAffinityLabels[pod.Labels[api.RackLabel]])
+		schedulable := false
+		for _, node := range availableNodes {
+			if selector.Matches(labels.Set(node.Labels)) {
+				schedulable = true
+				break
+			}
+		}
+		if !schedulable {
+			deadlocked = append(deadlocked, pod)
+		}
+	}
+	sort.Slice(deadlocked, func(i, j int) bool { return deadlocked[i].Name < deadlocked[j].Name })
+
+	return deadlocked
+}
+
+// getWaitingPVCs returns the unbound PVCs of the unschedulable pods whose storage class delays the
+// binding until a pod uses them, by pod name.
+func (rc *ReconciliationContext) getWaitingPVCs() (map[string]*corev1.PersistentVolumeClaim, error) {
+	waitingPVCs := map[string]*corev1.PersistentVolumeClaim{}
+	for _, pod := range rc.dcPods {
+		if !IsPodUnschedulable(pod) {
+			continue
+		}
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvcName := types.NamespacedName{Namespace: pod.Namespace, Name: fmt.Sprintf("%s-%s", PvcName, pod.Name)}
+		if err := rc.Client.Get(rc.Ctx, pvcName, pvc); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if pvc.Status.Phase != corev1.ClaimPending || pvc.Spec.StorageClassName == nil {
+			continue
+		}
+
+		storageClass := &storagev1.StorageClass{}
+		if err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, storageClass); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if storageClass.VolumeBindingMode != nil && *storageClass.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+			waitingPVCs[pod.Name] = pvc
+		}
+	}
+	return waitingPVCs, nil
+}
+
+// CheckSchedulingDeadlocks sets the SchedulingDeadlock condition when a pod can't be scheduled
+// because no node of its rack is available while its WaitForFirstConsumer volume waits for the pod.
+func (rc *ReconciliationContext) CheckSchedulingDeadlocks() result.ReconcileResult {
+	waitingPVCs, err := rc.getWaitingPVCs()
+	if err != nil {
+		return result.Error(err)
+	}
+	if len(waitingPVCs) == 0 && rc.Datacenter.GetConditionStatus(api.DatacenterSchedulingDeadlock) != corev1.ConditionTrue {
+		return result.Continue()
+	}
+
+	deadlocked := []*corev1.Pod{}
+	if len(waitingPVCs) > 0 {
+		nodes, err := rc.GetAllNodes()
+		if err != nil {
+			return result.Error(err)
+		}
+		rackAffinityLabels, err := rc.rackAffinityLabels()
+		if err != nil {
+			return result.Error(err)
+		}
+		deadlocked = FindSchedulingDeadlocks(rc.dcPods, waitingPVCs, nodes, rackAffinityLabels, rc.Datacenter.Spec.AllowMultipleNodesPerWorker)
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	condition := api.NewDatacenterCondition(api.DatacenterSchedulingDeadlock, corev1.ConditionFalse)
+	if len(deadlocked) > 0 {
+		pod := deadlocked[0]
+		condition = api.NewDatacenterConditionWithReason(api.DatacenterSchedulingDeadlock, corev1.ConditionTrue,
+			"NoSchedulableNode", fmt.Sprintf("pod %s and its volume %s wait for each other, add or uncordon a node matching the node affinity of rack %s, or allow multiple nodes per worker",
+				pod.Name, waitingPVCs[pod.Name].Name, pod.Labels[api.RackLabel]))
+	}
+	if !rc.setCondition(condition) {
+		return result.Continue()
+	}
+
+	if len(deadlocked) > 0 {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.SchedulingDeadlock,
+			"Pod %s can't be scheduled on any node of rack %s while its volume waits for it", deadlocked[0].Name, deadlocked[0].Labels[api.RackLabel])
+	}
+
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for scheduling deadlocks")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
+// detectRacks creates one rack per zone of the ready worker nodes when AutoDetectRacks is set and
+// no racks are defined. Once the datacenter is initialized the racks are never detected again, so
+// that the existing pods don't move to other racks.
+func (rc *ReconciliationContext) detectRacks() error {
+	dc := rc.Datacenter
+	if !dc.Spec.AutoDetectRacks || len(dc.Spec.Racks) > 0 || rc.IsInitialized() {
+		return nil
+	}
+
+	nodes, err := rc.GetAllNodes()
+	if err != nil {
+		return err
+	}
+	zoneCounts := utils.CountNodesByLabel(utils.FilterNodesWithFn(nodes, utils.IsNodeReady), corev1.LabelTopologyZone)
+	if len(zoneCounts) == 0 {
+		rc.ReqLogger.Info("No zone found on the worker nodes, keeping the default rack")
+		return nil
+	}
+
+	zones := make([]string, 0, len(zoneCounts))
+	for zone := range zoneCounts {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	patch := client.MergeFrom(dc.DeepCopy())
+	for _, zone := range zones {
+		dc.Spec.Racks = append(dc.Spec.Racks, api.Rack{
+			Name:               zone,
+			NodeAffinityLabels: map[string]string{corev1.LabelTopologyZone: zone},
+		})
+	}
+	metav1.SetMetaDataAnnotation(&dc.ObjectMeta, api.DetectedRacksAnnotation, strings.Join(zones, ","))
+	if err := rc.Client.Patch(rc.Ctx, dc, patch); err != nil {
+		return err
+	}
+
+	rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.DetectedRacks,
+		"Detected racks from the zones of the worker nodes: %s", strings.Join(zones, ", "))
+	return nil
+}
+
+func containsToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(&toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTaintTolerated(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// datacenterForRack returns the datacenter to build the StatefulSet of the rack from, statefulSet
+// being the existing StatefulSet of the rack if any. When AutoDiscoverTolerations is enabled, it is
+// a copy with the discovered tolerations added. The tolerations are discovered once per reconcile.
+func (rc *ReconciliationContext) datacenterForRack(rackName string, statefulSet *appsv1.StatefulSet) (*api.CassandraDatacenter, error) {
+	if !rc.Datacenter.Spec.AutoDiscoverTolerations {
+		return rc.Datacenter, nil
+	}
+
+	if rc.rackTolerations == nil {
+		nodes, err := rc.GetAllNodes()
+		if err != nil {
+			return nil, err
+		}
+
+		rackTolerations := make(map[string][]corev1.Toleration)
+		for _, rack := range rc.Datacenter.GetRacks() {
+			tolerations, found, err := rc.discoverRackTolerations(rack.Name, nodes)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				rackTolerations[rack.Name] = tolerations
+			}
+		}
+		rc.rackTolerations = rackTolerations
+	}
+
+	tolerations, found := rc.rackTolerations[rackName]
+	if !found && statefulSet != nil {
+		// The nodes of the rack may only be gone for a moment, the pods keep the tolerations they
+		// have rather than being restarted without them
+		for _, toleration := range statefulSet.Spec.Template.Spec.Tolerations {
+			if !containsToleration(rc.Datacenter.Spec.Tolerations, toleration) {
+				tolerations = append(tolerations, toleration)
+			}
+		}
+	}
+	if len(tolerations) == 0 {
+		return rc.Datacenter, nil
+	}
+
+	dc := rc.Datacenter.DeepCopy()
+	dc.Spec.Tolerations = append(dc.Spec.Tolerations, tolerations...)
+	return dc, nil
+}
+
 func getPVCsNodeNameSet(pvcs []*corev1.PersistentVolumeClaim) utils.StringSet {
 	nodeNameSet := utils.StringSet{}
 	for _, pvc := range pvcs {
@@ -171,6 +1019,11 @@ func (rc *ReconciliationContext) StartNodeReplace(podName string) error {
 		return fmt.Errorf("Pod with name '%s' not part of datacenter", podName)
 	}
 
+	// Only replace one node at a time
+	if inProgress, replacedPod := rc.ReplacementInProgress(); inProgress && replacedPod != podName {
+		return fmt.Errorf("Replacement of pod '%s' is already in progress", replacedPod)
+	}
+
 	pvc, err := rc.GetPodPVC(pod.Namespace, pod.Name)
 	if err != nil {
 		return err
@@ -206,6 +1059,150 @@ func (rc *ReconciliationContext) GetInProgressNodeReplacements() []string {
 	return rc.Datacenter.Status.NodeReplacements
 }
 
+// ReplacementInProgress returns whether a node replacement is in progress and the name of the pod
+// being replaced. Besides the replacements tracked in the status, a pod is considered to be replacing
+// a node if the replace address is set in the environment of its cassandra container.
+func (rc *ReconciliationContext) ReplacementInProgress() (bool, string) {
+	if len(rc.Datacenter.Status.NodeReplacements) > 0 {
+		return true, rc.Datacenter.Status.NodeReplacements[0]
+	}
+
+	for _, pod := range rc.dcPods {
+		if hasReplaceAddressEnv(pod) {
+			return true, pod.Name
+		}
+	}
+
+	return false, ""
+}
+
+func hasReplaceAddressEnv(pod *corev1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != CassandraContainerName {
+			continue
+		}
+		for _, env := range container.Env {
+			if isReplaceAddressEnv(env) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isReplaceAddressEnv(env corev1.EnvVar) bool {
+	// REPLACE_ADDRESS and REPLACE_ADDRESS_FIRST_BOOT are read by the Cassandra docker images,
+	// otherwise the replace_address system property can be passed through the JVM options
+	if (env.Name == "REPLACE_ADDRESS" || env.Name == "REPLACE_ADDRESS_FIRST_BOOT") && env.Value != "" {
+		return true
+	}
+	return strings.Contains(env.Value, "-Dcassandra.replace_address")
+}
+
+// removeReplaceAddressEnv removes the replace address from the environment of the cassandra
+// container of the template. The removed values are returned, or an empty string if the template
+// had no replace address.
+func removeReplaceAddressEnv(template *corev1.PodTemplateSpec) string {
+	var removed []string
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		if container.Name != CassandraContainerName {
+			continue
+		}
+
+		env := make([]corev1.EnvVar, 0, len(container.Env))
+		for _, envVar := range container.Env {
+			if !isReplaceAddressEnv(envVar) {
+				env = append(env, envVar)
+				continue
+			}
+			removed = append(removed, fmt.Sprintf("%s=%s", envVar.Name, envVar.Value))
+			if envVar.Name == "REPLACE_ADDRESS" || envVar.Name == "REPLACE_ADDRESS_FIRST_BOOT" {
+				continue
+			}
+
+			// keep the other JVM options
+			var options []string
+			for _, option := range strings.Fields(envVar.Value) {
+				if !strings.HasPrefix(option, "-Dcassandra.replace_address") {
+					options = append(options, option)
+				}
+			}
+			if len(options) > 0 {
+				envVar.Value = strings.Join(options, " ")
+				env = append(env, envVar)
+			}
+		}
+		container.Env = env
+	}
+
+	return strings.Join(removed, ",")
+}
+
+// CheckReplaceAddressRemoval annotates the StatefulSets still having a replace address in their pod
+// template once all the nodes of their rack are up and normal. CheckRackPodTemplate then removes the
+// replace address from the pod template, so that restarting the nodes doesn't replace them again.
+func (rc *ReconciliationContext) CheckReplaceAddressRemoval(epData httphelper.CassMetadataEndpoints) result.ReconcileResult {
+	dc := rc.Datacenter
+	if !dc.Spec.RemoveReplaceAddressAfterReplace || len(dc.Status.NodeReplacements) > 0 {
+		return result.Continue()
+	}
+
+	for idx := range rc.desiredRackInformation {
+		rackName := rc.desiredRackInformation[idx].RackName
+		statefulSet := rc.statefulSets[idx]
+
+		removed := removeReplaceAddressEnv(statefulSet.Spec.Template.DeepCopy())
+		if removed == "" || statefulSet.Annotations[api.ReplaceAddressRemovedAnnotation] == removed {
+			continue
+		}
+
+		rackPods := FilterPodListByLabels(rc.dcPods, dc.GetRackLabels(rackName))
+		if len(rackPods) < int(*statefulSet.Spec.Replicas) || !allNodesNormal(rackPods, epData) {
+			continue
+		}
+
+		rc.ReqLogger.Info("Nodes of the rack rejoined the ring, removing the replace address",
+			"rackName", rackName)
+
+		patch := client.MergeFrom(statefulSet.DeepCopy())
+		metav1.SetMetaDataAnnotation(&statefulSet.ObjectMeta, api.ReplaceAddressRemovedAnnotation, removed)
+		// the pod template has to be rebuilt even if nothing else changed
+		delete(statefulSet.Annotations, api.ReconciledGenerationAnnotation)
+		if err := rc.Client.Patch(rc.Ctx, statefulSet, patch); err != nil {
+			rc.ReqLogger.Error(err, "error annotating statefulset for the removal of the replace address")
+			return result.Error(err)
+		}
+
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.RemovedReplaceAddress,
+			"Removing replace address from rack %s", rackName)
+	}
+
+	return result.Continue()
+}
+
+// allNodesNormal returns true if all the pods are ready and their nodes are up and normal
+func allNodesNormal(pods []*corev1.Pod, epData httphelper.CassMetadataEndpoints) bool {
+	for _, pod := range pods {
+		if !isServerReady(pod) {
+			return false
+		}
+
+		normal := false
+		for idx := range epData.Entity {
+			ep := &epData.Entity[idx]
+			if ep.GetRpcAddress() == pod.Status.PodIP || ep.EndpointIP == pod.Status.PodIP {
+				normal = ep.IsAlive == "true" && ep.HasStatus(httphelper.StatusNormal)
+				break
+			}
+		}
+		if !normal {
+			return false
+		}
+	}
+	return true
+}
+
 func (rc *ReconciliationContext) RemovePod(pod *corev1.Pod) error {
 	if isMgmtApiRunning(pod) {
 		err := rc.NodeMgmtClient.CallDrainEndpoint(pod)
@@ -237,3 +1234,96 @@ func (rc *ReconciliationContext) IsStopped() bool {
 func (rc *ReconciliationContext) IsInitialized() bool {
 	return rc.Datacenter.GetConditionStatus(api.DatacenterInitialized) == corev1.ConditionTrue
 }
+
+// ClockSkew returns the largest difference between the clock offsets of the nodes, along with the
+// pods whose clocks are the most ahead and the most behind
+func ClockSkew(clockOffsets map[string]time.Duration) (time.Duration, string, string) {
+	podNames := make([]string, 0, len(clockOffsets))
+	for podName := range clockOffsets {
+		podNames = append(podNames, podName)
+	}
+	sort.Strings(podNames)
+
+	var ahead, behind string
+	for _, podName := range podNames {
+		if ahead == "" || clockOffsets[podName] > clockOffsets[ahead] {
+			ahead = podName
+		}
+		if behind == "" || clockOffsets[podName] < clockOffsets[behind] {
+			behind = podName
+		}
+	}
+	if ahead == "" {
+		return 0, "", ""
+	}
+	return clockOffsets[ahead] - clockOffsets[behind], ahead, behind
+}
+
+const (
+	// clockSkewProbeInterval is the minimum time between two reads of the clocks of the nodes of a
+	// datacenter
+	clockSkewProbeInterval = 5 * time.Minute
+
+	// clockReadTolerance is the error on the difference between two clocks read from the Date header
+	// of the management API responses, which has a resolution of one second
+	clockReadTolerance = 2 * time.Second
+)
+
+var clockSkewProbes = newProbeThrottle(clockSkewProbeInterval)
+
+// CheckClockSkew sets the ClockSkewDetected condition when the clocks of the ready nodes differ by
+// more than ClockSkewThresholdSeconds plus clockReadTolerance, and clears it once they differ by
+// at most ClockSkewThresholdSeconds. The clocks are read every clockSkewProbeInterval.
+func (rc *ReconciliationContext) CheckClockSkew() result.ReconcileResult {
+	threshold := time.Duration(rc.Datacenter.Spec.ClockSkewThresholdSeconds) * time.Second
+	if threshold <= 0 {
+		return result.Continue()
+	}
+
+	dcName := types.NamespacedName{Name: rc.Datacenter.Name, Namespace: rc.Datacenter.Namespace}
+	if !clockSkewProbes.allow(dcName, time.Now()) {
+		return result.Continue()
+	}
+
+	clockOffsets := map[string]time.Duration{}
+	for _, pod := range rc.dcPods {
+		if !isServerReady(pod) {
+			continue
+		}
+		offset, err := rc.NodeMgmtClient.GetNodeClockOffset(pod)
+		if err != nil {
+			rc.ReqLogger.Error(err, "unable to read the clock of the node", "pod", pod.Name)
+			continue
+		}
+		clockOffsets[pod.Name] = offset
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	condition := api.NewDatacenterCondition(api.DatacenterClockSkewDetected, corev1.ConditionFalse)
+	var message string
+	skew, ahead, behind := ClockSkew(clockOffsets)
+	switch {
+	case skew > threshold+clockReadTolerance:
+		message = fmt.Sprintf("clock of pod %s is %s ahead of pod %s", ahead, skew.Round(time.Second), behind)
+		condition = api.NewDatacenterConditionWithReason(api.DatacenterClockSkewDetected,
+			corev1.ConditionTrue, "ClockSkew", message)
+	case skew > threshold:
+		// Within the read tolerance, the condition is kept as is so that it doesn't flap
+		return result.Continue()
+	}
+	if !rc.setCondition(condition) {
+		return result.Continue()
+	}
+
+	if message != "" {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.ClockSkewDetected,
+			"Clock skew detected, the %s", message)
+	}
+
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for clock skew")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
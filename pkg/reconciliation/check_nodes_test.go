@@ -0,0 +1,871 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/events"
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/k8ssandra/cass-operator/pkg/internal/result"
+	"github.com/k8ssandra/cass-operator/pkg/mocks"
+	"github.com/k8ssandra/cass-operator/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReplacementInProgress(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	makePod := func(name string, env ...corev1.EnvVar) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: rc.Datacenter.Namespace,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: CassandraContainerName,
+					Env:  env,
+				}},
+			},
+		}
+	}
+
+	rc.dcPods = []*corev1.Pod{
+		makePod("pod-0"),
+		makePod("pod-1", corev1.EnvVar{Name: "DS_LICENSE", Value: "accept"}),
+	}
+
+	inProgress, podName := rc.ReplacementInProgress()
+	assert.False(inProgress)
+	assert.Empty(podName)
+
+	rc.dcPods = append(rc.dcPods, makePod("pod-2",
+		corev1.EnvVar{Name: "JVM_EXTRA_OPTS", Value: "-Dcassandra.replace_address_first_boot=10.0.0.3"}))
+
+	inProgress, podName = rc.ReplacementInProgress()
+	assert.True(inProgress)
+	assert.Equal("pod-2", podName)
+
+	// Replacements tracked in the status are reported as well
+	rc.dcPods = rc.dcPods[:2]
+	rc.Datacenter.Status.NodeReplacements = []string{"pod-0"}
+
+	inProgress, podName = rc.ReplacementInProgress()
+	assert.True(inProgress)
+	assert.Equal("pod-0", podName)
+}
+
+func TestCheckStalePVCSelectedNodes(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	assert.NoError(rc.Client.Create(rc.Ctx, node))
+
+	makePodAndPVC := func(podName, nodeName string) *corev1.PersistentVolumeClaim {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: rc.Datacenter.Namespace,
+			},
+		}
+		rc.dcPods = append(rc.dcPods, pod)
+
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      PvcName + "-" + podName,
+				Namespace: rc.Datacenter.Namespace,
+				Annotations: map[string]string{
+					utils.PVCSelectedNodeAnnotation: nodeName,
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase: corev1.ClaimPending,
+			},
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, pvc))
+		return pvc
+	}
+
+	stalePVC := makePodAndPVC("pod-0", "vanished-node")
+	validPVC := makePodAndPVC("pod-1", node.Name)
+
+	r := rc.CheckStalePVCSelectedNodes()
+	assert.Equal(result.Continue(), r)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(stalePVC), pvc))
+	assert.Empty(utils.GetPVCSelectedNodeName(pvc), "stale selected node should have been cleared")
+
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(validPVC), pvc))
+	assert.Equal(node.Name, utils.GetPVCSelectedNodeName(pvc))
+}
+
+func TestCheckVolumeExpansion(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	allowExpansion := true
+	expandable := &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "expandable"},
+		Provisioner:          "csi.example.com",
+		AllowVolumeExpansion: &allowExpansion,
+	}
+	fixed := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "fixed"},
+		Provisioner: "csi.example.com",
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, expandable))
+	assert.NoError(rc.Client.Create(rc.Ctx, fixed))
+
+	makePodAndPVC := func(podName, storageClassName string) *corev1.PersistentVolumeClaim {
+		rc.dcPods = append(rc.dcPods, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: rc.Datacenter.Namespace,
+			},
+		})
+
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      PvcName + "-" + podName,
+				Namespace: rc.Datacenter.Namespace,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClassName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase:    corev1.ClaimBound,
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, pvc))
+		return pvc
+	}
+
+	expandablePVC := makePodAndPVC("pod-0", expandable.Name)
+	fixedPVC := makePodAndPVC("pod-1", fixed.Name)
+
+	newSize := resource.MustParse("2Gi")
+	rc.Datacenter.Spec.StorageConfig.CassandraDataVolumeClaimSpec.Resources.Requests[corev1.ResourceStorage] = newSize
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	r := rc.CheckVolumeExpansion()
+	assert.Equal(result.RequeueSoon(10), r)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(expandablePVC), pvc))
+	size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Equal(0, size.Cmp(newSize), "PVC should have been expanded")
+
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(fixedPVC), pvc))
+	size = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Equal("1Gi", size.String(), "PVC of a storage class without expansion should be left alone")
+
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterResizingVolumes))
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterVolumeExpansionNotAllowed))
+
+	recorder := rc.Recorder.(*record.FakeRecorder)
+	assert.Len(recorder.Events, 2)
+	assert.Contains(<-recorder.Events, events.ExpandingVolume)
+	assert.Contains(<-recorder.Events, events.VolumeExpansionNotAllowed)
+
+	// Once the volume is expanded, the condition is cleared
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(expandablePVC), pvc))
+	pvc.Status.Capacity[corev1.ResourceStorage] = newSize
+	assert.NoError(rc.Client.Status().Update(rc.Ctx, pvc))
+
+	r = rc.CheckVolumeExpansion()
+	assert.Equal(result.Continue(), r)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterResizingVolumes))
+	// The PVC that can't be expanded was already reported
+	assert.Len(recorder.Events, 1)
+	assert.Contains(<-recorder.Events, events.ExpandedVolumes)
+
+	// Once the storage class allows it, the other PVC is expanded as well
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(fixed), fixed))
+	fixed.AllowVolumeExpansion = &allowExpansion
+	assert.NoError(rc.Client.Update(rc.Ctx, fixed))
+
+	r = rc.CheckVolumeExpansion()
+	assert.Equal(result.RequeueSoon(10), r)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterVolumeExpansionNotAllowed))
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterResizingVolumes))
+}
+
+func TestCheckRemovedRackPVCs(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	rc.Datacenter.Spec.Racks = []api.Rack{{Name: "rack1"}}
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	for _, rackName := range []string{"rack1", "rack2"} {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s-sts-0", PvcName, rackName),
+				Namespace: rc.Datacenter.Namespace,
+				Labels:    rc.Datacenter.GetRackLabels(rackName),
+			},
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, pvc))
+	}
+	removedRackPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rack2-sts-0",
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    rc.Datacenter.GetRackLabels("rack2"),
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, removedRackPod))
+
+	countPVCs := func() int {
+		pvcs := &corev1.PersistentVolumeClaimList{}
+		assert.NoError(rc.Client.List(rc.Ctx, pvcs, client.InNamespace(rc.Datacenter.Namespace)))
+		return len(pvcs.Items)
+	}
+
+	// Disabled by default
+	assert.Equal(result.Continue(), rc.CheckRemovedRackPVCs())
+	assert.Equal(2, countPVCs())
+
+	rc.Datacenter.Spec.ReclaimRemovedRackPVCs = true
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	// A pod of the removed rack is still there
+	assert.Equal(result.Continue(), rc.CheckRemovedRackPVCs())
+	assert.Equal(2, countPVCs())
+	assert.Len(fakeRecorder.Events, 0)
+
+	assert.NoError(rc.Client.Delete(rc.Ctx, removedRackPod))
+	assert.Equal(result.Continue(), rc.CheckRemovedRackPVCs())
+	assert.Equal(1, countPVCs())
+	assert.Len(fakeRecorder.Events, 1)
+	assert.Contains(<-fakeRecorder.Events, events.ReclaimedRackPVCs)
+
+	// The PVC of the rack still in the spec is kept
+	pvc := &corev1.PersistentVolumeClaim{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Namespace: rc.Datacenter.Namespace, Name: PvcName + "-rack1-sts-0"}, pvc))
+}
+
+func TestCheckSchedulingDeadlocks(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{{Name: "rack1", Zone: "zone-a"}, {Name: "rack2", Zone: "zone-b"}}
+	rc.desiredRackInformation = []*RackInformation{{RackName: "rack1", NodeCount: 2}, {RackName: "rack2", NodeCount: 1}}
+
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta:        metav1.ObjectMeta{Name: "topology-aware"},
+		Provisioner:       "csi.example.com",
+		VolumeBindingMode: &waitForFirstConsumer,
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, storageClass))
+
+	makeNode := func(name, zone string) {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{zoneLabel: zone}},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, node))
+	}
+	makeNode("node-a", "zone-a")
+	makeNode("node-b", "zone-b")
+
+	makePodAndPVC := func(podName, rackName, nodeName string) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: rc.Datacenter.Namespace,
+				Labels:    map[string]string{api.RackLabel: rackName},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      PvcName + "-" + podName,
+				Namespace: rc.Datacenter.Namespace,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass.Name},
+		}
+		if nodeName == "" {
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:   corev1.PodScheduled,
+					Status: corev1.ConditionFalse,
+					Reason: corev1.PodReasonUnschedulable,
+				}},
+			}
+			pvc.Status.Phase = corev1.ClaimPending
+		} else {
+			pod.Status.Phase = corev1.PodRunning
+			pvc.Status.Phase = corev1.ClaimBound
+		}
+		rc.dcPods = append(rc.dcPods, pod)
+		assert.NoError(rc.Client.Create(rc.Ctx, pvc))
+	}
+
+	// The only node of zone-a already hosts a pod of rack1, the anti-affinity keeps the second
+	// pod pending while its volume waits for it
+	makePodAndPVC("pod-0", "rack1", "node-a")
+	makePodAndPVC("pod-1", "rack1", "")
+	makePodAndPVC("pod-2", "rack2", "node-b")
+
+	r := rc.CheckSchedulingDeadlocks()
+	assert.Equal(result.Continue(), r)
+
+	dc := &api.CassandraDatacenter{}
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(rc.Datacenter), dc))
+	condition, found := dc.GetCondition(api.DatacenterSchedulingDeadlock)
+	assert.True(found)
+	assert.Equal(corev1.ConditionTrue, condition.Status)
+	assert.Contains(condition.Message, "pod-1")
+	assert.Contains(condition.Message, "rack1")
+
+	// Another node in zone-a lets the pod be scheduled
+	makeNode("node-a2", "zone-a")
+
+	r = rc.CheckSchedulingDeadlocks()
+	assert.Equal(result.Continue(), r)
+
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(rc.Datacenter), dc))
+	condition, found = dc.GetCondition(api.DatacenterSchedulingDeadlock)
+	assert.True(found)
+	assert.Equal(corev1.ConditionFalse, condition.Status)
+}
+
+func TestCheckUnschedulableRacks(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	rc.Datacenter.Spec.Racks = []api.Rack{
+		{Name: "rack1", NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "zone-a"}},
+		{Name: "rack2", NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "zone-b"}},
+	}
+	rc.desiredRackInformation = []*RackInformation{{RackName: "rack1", NodeCount: 1}, {RackName: "rack2", NodeCount: 1}}
+
+	scheduled := makeMockReadyStartedPod()
+	scheduled.Name = "pod-0"
+	scheduled.Labels = utils.MergeMap(scheduled.Labels, rc.Datacenter.GetRackLabels("rack1"))
+	scheduled.Spec.NodeName = "node-a"
+	scheduled.Status.Phase = corev1.PodRunning
+
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "pod-1",
+			Labels: rc.Datacenter.GetRackLabels("rack2"),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  corev1.PodReasonUnschedulable,
+				Message: "0/3 nodes are available: 3 node(s) didn't match Pod's node affinity/selector.",
+			}},
+		},
+	}
+	assert.False(IsPodUnschedulable(scheduled))
+	assert.True(IsPodUnschedulable(pending))
+
+	rc.dcPods = []*corev1.Pod{scheduled, pending}
+	assert.Equal(result.Continue(), rc.CheckUnschedulableRacks())
+	assert.Equal(1, len(fakeRecorder.Events))
+	event := <-fakeRecorder.Events
+	assert.Contains(event, "Warning UnschedulableRack")
+	assert.Contains(event, "Rack rack2 can't be scheduled, pod pod-1")
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterUnschedulableRacks))
+
+	// The event is not emitted again while the same racks can't be scheduled
+	assert.Equal(result.Continue(), rc.CheckUnschedulableRacks())
+	assert.Equal(0, len(fakeRecorder.Events))
+
+	// Once scheduled, no event is emitted and the condition is cleared
+	pending.Spec.NodeName = "node-b"
+	assert.Equal(result.Continue(), rc.CheckUnschedulableRacks())
+	assert.Equal(0, len(fakeRecorder.Events))
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterUnschedulableRacks))
+}
+
+func TestDiscoverRackTolerations(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	dedicated := corev1.Taint{Key: "dedicated", Value: "cassandra", Effect: corev1.TaintEffectNoSchedule}
+	makeNode := func(name string, labels map[string]string, taints ...corev1.Taint) {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       corev1.NodeSpec{Taints: taints},
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, node))
+	}
+	poolLabels := map[string]string{"pool": "cassandra"}
+	makeNode("node-1", poolLabels, dedicated,
+		corev1.Taint{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoExecute})
+	makeNode("node-2", poolLabels, dedicated,
+		corev1.Taint{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule})
+	makeNode("node-3", map[string]string{"pool": "other"},
+		corev1.Taint{Key: "dedicated", Value: "other", Effect: corev1.TaintEffectNoSchedule})
+
+	rc.Datacenter.Spec.Racks = []api.Rack{{
+		Name:               "rack1",
+		NodeAffinityLabels: poolLabels,
+	}}
+
+	nodes, err := rc.GetAllNodes()
+	assert.NoError(err)
+	tolerations, found, err := rc.discoverRackTolerations("rack1", nodes)
+	assert.NoError(err)
+	assert.True(found)
+	assert.Equal([]corev1.Toleration{{
+		Key:      "dedicated",
+		Operator: corev1.TolerationOpEqual,
+		Value:    "cassandra",
+		Effect:   corev1.TaintEffectNoSchedule,
+	}}, tolerations)
+
+	// Disabled by default
+	dc, err := rc.datacenterForRack("rack1", nil)
+	assert.NoError(err)
+	assert.Empty(dc.Spec.Tolerations)
+
+	rc.Datacenter.Spec.AutoDiscoverTolerations = true
+	dc, err = rc.datacenterForRack("rack1", nil)
+	assert.NoError(err)
+	assert.Equal(tolerations, dc.Spec.Tolerations)
+	assert.Empty(rc.Datacenter.Spec.Tolerations, "the datacenter itself should not be modified")
+
+	sts, err := newStatefulSetForCassandraDatacenter(nil, "rack1", dc, 1, false)
+	assert.NoError(err)
+	assert.Equal(tolerations, sts.Spec.Template.Spec.Tolerations)
+
+	// The tolerations are discovered once per reconcile
+	for _, name := range []string{"node-1", "node-2"} {
+		assert.NoError(rc.Client.Delete(rc.Ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}))
+	}
+	dc, err = rc.datacenterForRack("rack1", sts)
+	assert.NoError(err)
+	assert.Equal(tolerations, dc.Spec.Tolerations)
+
+	// When the nodes of the rack are not found, the StatefulSet keeps its tolerations
+	rc.rackTolerations = nil
+	nodes, err = rc.GetAllNodes()
+	assert.NoError(err)
+	_, found, err = rc.discoverRackTolerations("rack1", nodes)
+	assert.NoError(err)
+	assert.False(found)
+	dc, err = rc.datacenterForRack("rack1", sts)
+	assert.NoError(err)
+	assert.Equal(tolerations, dc.Spec.Tolerations)
+	dc, err = rc.datacenterForRack("rack1", nil)
+	assert.NoError(err)
+	assert.Empty(dc.Spec.Tolerations)
+
+	// Taints that are already tolerated are skipped
+	makeNode("node-4", poolLabels, dedicated)
+	nodes, err = rc.GetAllNodes()
+	assert.NoError(err)
+	rc.Datacenter.Spec.Tolerations = []corev1.Toleration{{
+		Key:      "dedicated",
+		Operator: corev1.TolerationOpExists,
+	}}
+	tolerations, _, err = rc.discoverRackTolerations("rack1", nodes)
+	assert.NoError(err)
+	assert.Empty(tolerations)
+}
+
+func TestCheckReleasedVolumes(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.UID = "dc-uid"
+	rc.Datacenter.Spec.RebindReleasedVolumes = true
+	storageClass := "retained"
+	rc.statefulSets = []*appsv1.StatefulSet{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1-dc1-rack1-sts",
+			Namespace: rc.Datacenter.Namespace,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: PvcName},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &storageClass,
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				},
+			}},
+		},
+	}}
+	rc.dcPods = []*corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1-dc1-rack1-sts-0", Namespace: rc.Datacenter.Namespace}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1-dc1-rack1-sts-1", Namespace: rc.Datacenter.Namespace}},
+	}
+
+	releasedVolume := func(name, pvcName, owner string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{api.VolumeOwnerAnnotation: owner},
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+				ClaimRef: &corev1.ObjectReference{
+					Namespace: rc.Datacenter.Namespace,
+					Name:      pvcName,
+					UID:       "deleted-claim",
+				},
+			},
+			Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeReleased},
+		}
+	}
+
+	// The volume of the first pod was bound to this datacenter, the one of the second pod to a
+	// previous datacenter with the same name
+	pvcName := PvcName + "-cluster1-dc1-rack1-sts-0"
+	pv := releasedVolume("pv-1", pvcName, "dc-uid")
+	assert.NoError(rc.Client.Create(rc.Ctx, pv))
+	previousPVCName := PvcName + "-cluster1-dc1-rack1-sts-1"
+	previousPV := releasedVolume("pv-2", previousPVCName, "previous-dc-uid")
+	assert.NoError(rc.Client.Create(rc.Ctx, previousPV))
+
+	// The third pod doesn't exist yet
+	scaleUpPV := releasedVolume("pv-3", PvcName+"-cluster1-dc1-rack1-sts-2", "dc-uid")
+	assert.NoError(rc.Client.Create(rc.Ctx, scaleUpPV))
+
+	r := rc.CheckReleasedVolumes()
+	assert.Equal(result.Continue(), r)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Namespace: rc.Datacenter.Namespace, Name: pvcName}, pvc))
+	assert.Equal(pv.Name, pvc.Spec.VolumeName)
+	assert.Equal(&storageClass, pvc.Spec.StorageClassName)
+
+	updatedPV := &corev1.PersistentVolume{}
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(pv), updatedPV))
+	assert.Empty(updatedPV.Spec.ClaimRef.UID)
+	assert.Equal(pvcName, updatedPV.Spec.ClaimRef.Name)
+
+	err := rc.Client.Get(rc.Ctx, types.NamespacedName{Namespace: rc.Datacenter.Namespace, Name: previousPVCName}, &corev1.PersistentVolumeClaim{})
+	assert.True(errors.IsNotFound(err), "the volume of a previous datacenter should not be rebound")
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(previousPV), updatedPV))
+	assert.Equal(types.UID("deleted-claim"), updatedPV.Spec.ClaimRef.UID)
+
+	err = rc.Client.Get(rc.Ctx, types.NamespacedName{Namespace: rc.Datacenter.Namespace, Name: PvcName + "-cluster1-dc1-rack1-sts-2"}, &corev1.PersistentVolumeClaim{})
+	assert.True(errors.IsNotFound(err), "the claims of the missing pods are left to the StatefulSet")
+}
+
+func TestCheckReleasedVolumesRecordsOwner(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.UID = "dc-uid"
+	rc.Datacenter.Spec.RebindReleasedVolumes = true
+
+	pvcName := PvcName + "-cluster1-dc1-rack1-sts-0"
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvcName,
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    map[string]string{api.DatacenterLabel: rc.Datacenter.Name},
+			UID:       "claim-uid",
+		},
+		Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pvc))
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			ClaimRef: &corev1.ObjectReference{
+				Namespace: rc.Datacenter.Namespace,
+				Name:      pvcName,
+				UID:       "claim-uid",
+			},
+		},
+		Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pv))
+
+	r := rc.CheckReleasedVolumes()
+	assert.Equal(result.Continue(), r)
+
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(pv), pv))
+	assert.Equal("dc-uid", pv.Annotations[api.VolumeOwnerAnnotation])
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(pvc), pvc))
+	assert.Equal("dc-uid", pvc.Annotations[api.VolumeOwnerAnnotation])
+}
+
+func TestFindRebalanceCandidates(t *testing.T) {
+	assert := assert.New(t)
+
+	makeNode := func(name, zone string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{zoneLabel: zone},
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	makePod := func(name, rack, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{api.RackLabel: rack},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+
+	rackAffinityLabels := map[string]map[string]string{
+		"rack1": {zoneLabel: "zone-1"},
+		"rack2": {zoneLabel: "zone-2"},
+	}
+	// Both racks had to put two pods on the same node
+	pods := []*corev1.Pod{
+		makePod("rack1-sts-0", "rack1", "node-1a"),
+		makePod("rack1-sts-1", "rack1", "node-1a"),
+		makePod("rack2-sts-0", "rack2", "node-2a"),
+		makePod("rack2-sts-1", "rack2", "node-2a"),
+	}
+	nodes := []*corev1.Node{makeNode("node-1a", "zone-1"), makeNode("node-2a", "zone-2")}
+
+	assert.Empty(FindRebalanceCandidates(pods, nodes, rackAffinityLabels))
+
+	// A node is added to the zone of rack1, its second pod can move there
+	nodes = append(nodes, makeNode("node-1b", "zone-1"))
+	assert.Equal([]*corev1.Pod{pods[1]}, FindRebalanceCandidates(pods, nodes, rackAffinityLabels))
+
+	// Not if the new node is unschedulable
+	nodes[2].Spec.Unschedulable = true
+	assert.Empty(FindRebalanceCandidates(pods, nodes, rackAffinityLabels))
+
+	// A rack without zone affinity had to put its pods in one zone
+	pods = []*corev1.Pod{
+		makePod("rack3-sts-0", "rack3", "node-1a"),
+		makePod("rack3-sts-1", "rack3", "node-1b"),
+		makePod("rack3-sts-2", "rack3", "node-1c"),
+	}
+	nodes = []*corev1.Node{makeNode("node-1a", "zone-1"), makeNode("node-1b", "zone-1"), makeNode("node-1c", "zone-1")}
+	assert.Empty(FindRebalanceCandidates(pods, nodes, rackAffinityLabels))
+
+	// Once a node is added to another zone, the pods of the crowded zone can move there
+	nodes = append(nodes, makeNode("node-2b", "zone-2"))
+	assert.Equal(pods, FindRebalanceCandidates(pods, nodes, rackAffinityLabels))
+
+	// Moving a pod of a zone hosting only one more pod of the rack would not spread them better
+	pods[2].Spec.NodeName = "node-2b"
+	nodes = append(nodes, makeNode("node-2c", "zone-2"))
+	assert.Empty(FindRebalanceCandidates(pods, nodes, rackAffinityLabels))
+}
+
+func TestPodRebalancingConfig_InMaintenanceWindow(t *testing.T) {
+	assert := assert.New(t)
+	at := func(hour int) time.Time {
+		return time.Date(2022, 3, 1, hour, 30, 0, 0, time.UTC)
+	}
+
+	config := &api.PodRebalancingConfig{Enabled: true}
+	assert.True(config.InMaintenanceWindow(at(12)))
+
+	config.WindowStartHour, config.WindowEndHour = 2, 5
+	assert.True(config.InMaintenanceWindow(at(2)))
+	assert.False(config.InMaintenanceWindow(at(5)))
+
+	config.WindowStartHour, config.WindowEndHour = 22, 3
+	assert.True(config.InMaintenanceWindow(at(23)))
+	assert.True(config.InMaintenanceWindow(at(1)))
+	assert.False(config.InMaintenanceWindow(at(12)))
+}
+
+func TestClockSkew(t *testing.T) {
+	assert := assert.New(t)
+
+	skew, ahead, behind := ClockSkew(map[string]time.Duration{})
+	assert.Equal(time.Duration(0), skew)
+	assert.Empty(ahead)
+	assert.Empty(behind)
+
+	skew, ahead, behind = ClockSkew(map[string]time.Duration{
+		"pod-1": 200 * time.Millisecond,
+		"pod-2": -3 * time.Second,
+		"pod-3": 7 * time.Second,
+	})
+	assert.Equal(10*time.Second, skew)
+	assert.Equal("pod-3", ahead)
+	assert.Equal("pod-2", behind)
+}
+
+func TestCheckClockSkew(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.ClockSkewThresholdSeconds = 5
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	// The nodes report their time in the Date header of the management API responses
+	nodeTimes := map[string]time.Duration{
+		"10.0.0.1": 0,
+		"10.0.0.2": 1 * time.Second,
+		"10.0.0.3": 30 * time.Second,
+	}
+	probes := 0
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do", mock.Anything).
+		Return(func(req *http.Request) *http.Response {
+			probes++
+			nodeTime := time.Now().Add(nodeTimes[req.URL.Hostname()])
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Date": []string{nodeTime.UTC().Format(http.TimeFormat)}},
+				Body:       io.NopCloser(strings.NewReader("OK")),
+			}
+		}, nil)
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	rc.dcPods = []*corev1.Pod{}
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		pod := makeMockReadyStartedPod()
+		pod.Name = []string{"pod-1", "pod-2", "pod-3"}[i]
+		pod.Status.PodIP = ip
+		rc.dcPods = append(rc.dcPods, pod)
+	}
+
+	dcName := types.NamespacedName{Name: rc.Datacenter.Name, Namespace: rc.Datacenter.Namespace}
+	clockSkewProbes.reset(dcName)
+	defer clockSkewProbes.reset(dcName)
+
+	assert.Equal(result.Continue(), rc.CheckClockSkew())
+	condition, found := rc.Datacenter.GetCondition(api.DatacenterClockSkewDetected)
+	assert.True(found)
+	assert.Equal(corev1.ConditionTrue, condition.Status)
+	assert.True(strings.HasPrefix(condition.Message, "clock of pod pod-3 is "), condition.Message)
+	assert.True(strings.HasSuffix(condition.Message, " ahead of pod pod-1"), condition.Message)
+	assert.Equal(3, probes)
+
+	// The clocks are not read again right away
+	assert.Equal(result.Continue(), rc.CheckClockSkew())
+	assert.Equal(3, probes)
+
+	// A skew within the read tolerance above the threshold doesn't clear the condition
+	nodeTimes["10.0.0.3"] = 6 * time.Second
+	clockSkewProbes.reset(dcName)
+	assert.Equal(result.Continue(), rc.CheckClockSkew())
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterClockSkewDetected))
+
+	// Once the clocks are synchronized, the condition is cleared
+	nodeTimes["10.0.0.3"] = 0
+	clockSkewProbes.reset(dcName)
+	assert.Equal(result.Continue(), rc.CheckClockSkew())
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterClockSkewDetected))
+
+	// Nor does it raise the condition
+	nodeTimes["10.0.0.3"] = 6 * time.Second
+	clockSkewProbes.reset(dcName)
+	assert.Equal(result.Continue(), rc.CheckClockSkew())
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterClockSkewDetected))
+}
+
+func TestCalculateRackInformation_AutoDetectRacks(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	rc.Datacenter.Spec.Size = 3
+	rc.Datacenter.Spec.AutoDetectRacks = true
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	makeZoneNode := func(name, zone string, ready corev1.ConditionStatus) *corev1.Node {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			},
+		}
+		if zone != "" {
+			node.Labels = map[string]string{corev1.LabelTopologyZone: zone}
+		}
+		return node
+	}
+	for _, node := range []*corev1.Node{
+		makeZoneNode("node-0", "zone-b", corev1.ConditionTrue),
+		makeZoneNode("node-1", "zone-a", corev1.ConditionTrue),
+		makeZoneNode("node-2", "zone-c", corev1.ConditionTrue),
+		makeZoneNode("node-3", "zone-a", corev1.ConditionTrue),
+		makeZoneNode("node-4", "", corev1.ConditionTrue),
+		makeZoneNode("node-5", "zone-d", corev1.ConditionFalse),
+	} {
+		assert.NoError(rc.Client.Create(rc.Ctx, node))
+	}
+
+	assert.NoError(rc.CalculateRackInformation())
+
+	assert.Equal([]api.Rack{
+		{Name: "zone-a", NodeAffinityLabels: map[string]string{corev1.LabelTopologyZone: "zone-a"}},
+		{Name: "zone-b", NodeAffinityLabels: map[string]string{corev1.LabelTopologyZone: "zone-b"}},
+		{Name: "zone-c", NodeAffinityLabels: map[string]string{corev1.LabelTopologyZone: "zone-c"}},
+	}, rc.Datacenter.Spec.Racks)
+	assert.Len(rc.desiredRackInformation, 3)
+	for _, rackInfo := range rc.desiredRackInformation {
+		assert.Equal(1, rackInfo.NodeCount)
+	}
+
+	// The detected racks are kept in the spec
+	dc := &api.CassandraDatacenter{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: rc.Datacenter.Name, Namespace: rc.Datacenter.Namespace}, dc))
+	assert.Len(dc.Spec.Racks, 3)
+	assert.Equal("zone-a,zone-b,zone-c", dc.Annotations[api.DetectedRacksAnnotation])
+
+	assert.Equal(1, len(fakeRecorder.Events))
+	assert.Contains(<-fakeRecorder.Events, "zone-a, zone-b, zone-c")
+}
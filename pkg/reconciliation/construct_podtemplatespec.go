@@ -31,6 +31,11 @@ const (
 	CassandraContainerName               = "cassandra"
 	PvcName                              = "server-data"
 	SystemLoggerContainerName            = "server-system-logger"
+	FixPermissionsContainerName          = "server-data-permissions"
+	SafeToEvictAnnotation                = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+
+	// cassandraUserID is the uid and gid the server runs as in the server images
+	cassandraUserID int64 = 999
 )
 
 // calculateNodeAffinity provides a way to decide where to schedule pods within a statefulset based on labels
@@ -168,6 +173,14 @@ outerLoop:
 	return out
 }
 
+// ComputeServerPorts returns the ports of the server container based on the features enabled in the
+// datacenter. The services exposing the server ports are built from these ports too.
+func ComputeServerPorts(dc *api.CassandraDatacenter) []corev1.ContainerPort {
+	// GetContainerPorts never returns an error, it only keeps its signature for existing callers
+	ports, _ := dc.GetContainerPorts()
+	return ports
+}
+
 func combinePortSlices(defaults []corev1.ContainerPort, overrides []corev1.ContainerPort) []corev1.ContainerPort {
 	out := append([]corev1.ContainerPort{}, overrides...)
 outerLoop:
@@ -198,6 +211,40 @@ outerLoop:
 	return out
 }
 
+// EnvVarsEqual reports whether two slices have the same environment variables, matched by name,
+// regardless of their order. The variables named in ignoredNames, for instance the ones reserved by
+// the operator, are not compared.
+func EnvVarsEqual(a, b []corev1.EnvVar, ignoredNames ...string) bool {
+	ignored := utils.StringSet{}
+	for _, name := range ignoredNames {
+		ignored[name] = true
+	}
+
+	envA := map[string]corev1.EnvVar{}
+	for _, env := range a {
+		if !ignored[env.Name] {
+			envA[env.Name] = env
+		}
+	}
+	envB := map[string]corev1.EnvVar{}
+	for _, env := range b {
+		if !ignored[env.Name] {
+			envB[env.Name] = env
+		}
+	}
+
+	if len(envA) != len(envB) {
+		return false
+	}
+	for name, env := range envA {
+		other, found := envB[name]
+		if !found || !reflect.DeepEqual(env, other) {
+			return false
+		}
+	}
+	return true
+}
+
 func generateStorageConfigVolumesMount(cc *api.CassandraDatacenter) []corev1.VolumeMount {
 	var vms []corev1.VolumeMount
 	for _, storage := range cc.Spec.StorageConfig.AdditionalVolumes {
@@ -334,6 +381,54 @@ func buildInitContainers(dc *api.CassandraDatacenter, rackName string, baseTempl
 		baseTemplate.Spec.InitContainers = append(baseTemplate.Spec.InitContainers, *serverCfg)
 	}
 
+	if dc.Spec.StorageConfig.FixPermissions {
+		if err := addFixPermissionsInitContainer(dc, baseTemplate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addFixPermissionsInitContainer prepends an init container that chowns the data directory
+// to the Cassandra user, for storage classes that do not honor the fsGroup of the pod.
+func addFixPermissionsInitContainer(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTemplateSpec) error {
+	for _, c := range baseTemplate.Spec.InitContainers {
+		if c.Name == FixPermissionsContainerName {
+			return nil
+		}
+	}
+
+	image, err := makeImage(dc)
+	if err != nil {
+		return err
+	}
+
+	var rootUserID int64 = 0
+	fixPermissions := corev1.Container{
+		Name:  FixPermissionsContainerName,
+		Image: image,
+		Command: []string{
+			"/bin/sh",
+			"-c",
+			fmt.Sprintf("chown -R %d:%d /var/lib/cassandra", cassandraUserID, cassandraUserID),
+		},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser: &rootUserID,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      PvcName,
+				MountPath: "/var/lib/cassandra",
+			},
+		},
+		Resources: *getResourcesOrDefault(&dc.Spec.ConfigBuilderResources, &DefaultsConfigInitContainer),
+	}
+	if images.GetImageConfig() != nil && images.GetImageConfig().ImagePullPolicy != "" {
+		fixPermissions.ImagePullPolicy = images.GetImageConfig().ImagePullPolicy
+	}
+
+	baseTemplate.Spec.InitContainers = append([]corev1.Container{fixPermissions}, baseTemplate.Spec.InitContainers...)
 	return nil
 }
 
@@ -364,17 +459,57 @@ func getConfigDataEnVars(dc *api.CassandraDatacenter) ([]corev1.EnvVar, error) {
 		return nil, fmt.Errorf("datacenter %s is missing %s annotation", dc.Name, api.ConfigHashAnnotation)
 	}
 
-	configData, err := dc.GetConfigAsJSON(dc.Spec.Config)
+	cdcAdded, err := renderConfig(dc)
 	if err != nil {
 		return envVars, err
 	}
+	envVars = append(envVars, corev1.EnvVar{Name: "CONFIG_FILE_DATA", Value: string(cdcAdded)})
+
+	return envVars, nil
+}
+
+// renderConfig returns the server configuration generated from the datacenter's Config
+func renderConfig(dc *api.CassandraDatacenter) ([]byte, error) {
+	configData, err := dc.GetConfigAsJSON(dc.Spec.Config)
+	if err != nil {
+		return nil, err
+	}
 	cdcAdded, err := cdc.UpdateConfig(json.RawMessage(configData), *dc)
 	if err != nil {
-		return envVars, err
+		return nil, err
 	}
-	envVars = append(envVars, corev1.EnvVar{Name: "CONFIG_FILE_DATA", Value: string(cdcAdded)})
+	return []byte(cdcAdded), nil
+}
 
-	return envVars, nil
+// getConfigHash returns the hash of the configuration the pods are started with. When the
+// ConfigSecret property is set, this is the hash kept in the datacenter's config hash annotation,
+// otherwise it is computed from the rendered configuration.
+func getConfigHash(dc *api.CassandraDatacenter) (string, error) {
+	if len(dc.Spec.ConfigSecret) > 0 {
+		return dc.Annotations[api.ConfigHashAnnotation], nil
+	}
+
+	config, err := renderConfig(dc)
+	if err != nil {
+		return "", err
+	}
+	return utils.HashAnnotationValue(string(config)), nil
+}
+
+// configHashFromEnvVars returns the hash of the configuration passed to the config init container
+// through the env vars from getConfigDataEnVars. It matches getConfigHash.
+func configHashFromEnvVars(envVars []corev1.EnvVar) string {
+	for _, envVar := range envVars {
+		if envVar.Name == "CONFIG_HASH" {
+			return envVar.Value
+		}
+	}
+	for _, envVar := range envVars {
+		if envVar.Name == "CONFIG_FILE_DATA" && envVar.ValueFrom == nil {
+			return utils.HashAnnotationValue(envVar.Value)
+		}
+	}
+	return ""
 }
 
 // makeImage takes the server type/version and image from the spec,
@@ -430,6 +565,13 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 		cassContainer.Resources = dc.Spec.Resources
 	}
 
+	if dc.Spec.EnforceGuaranteedQoS {
+		if len(cassContainer.Resources.Limits) == 0 {
+			return fmt.Errorf("resource limits must be set for the %s container when enforceGuaranteedQoS is enabled", CassandraContainerName)
+		}
+		cassContainer.Resources.Requests = cassContainer.Resources.Limits.DeepCopy()
+	}
+
 	if cassContainer.LivenessProbe == nil {
 		cassContainer.LivenessProbe = probe(8080, httphelper.LivenessEndpoint, 15, 15, 10)
 	}
@@ -452,6 +594,10 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 		}
 	}
 
+	if cassContainer.Lifecycle.PostStart == nil && dc.Spec.PostStartHook != nil {
+		cassContainer.Lifecycle.PostStart = dc.Spec.PostStartHook.DeepCopy()
+	}
+
 	// Combine env vars
 
 	envDefaults := []corev1.EnvVar{
@@ -473,12 +619,7 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 
 	// Combine ports
 
-	portDefaults, err := dc.GetContainerPorts()
-	if err != nil {
-		return err
-	}
-
-	cassContainer.Ports = combinePortSlices(portDefaults, cassContainer.Ports)
+	cassContainer.Ports = combinePortSlices(ComputeServerPorts(dc), cassContainer.Ports)
 
 	// Combine volumeMounts
 
@@ -581,7 +722,7 @@ func buildPodTemplateSpec(dc *api.CassandraDatacenter, nodeAffinityLabels map[st
 	if baseTemplate.Spec.SecurityContext == nil {
 		// workaround for https://cloud.google.com/kubernetes-engine/docs/security-bulletins#may-31-2019
 		if shouldDefineSecurityContext(dc) {
-			var userID int64 = 999
+			userID := cassandraUserID
 			baseTemplate.Spec.SecurityContext = &corev1.PodSecurityContext{
 				RunAsUser:  &userID,
 				RunAsGroup: &userID,
@@ -607,8 +748,22 @@ func buildPodTemplateSpec(dc *api.CassandraDatacenter, nodeAffinityLabels map[st
 
 	// Annotations
 
+	// The discovery annotations hold the unmodified names, unlike the labels which are
+	// cleaned up to be valid label values. External tools such as backup operators rely on them.
+	discoveryAnnotations := map[string]string{
+		api.ClusterAnnotation:    dc.Spec.ClusterName,
+		api.DatacenterAnnotation: dc.Name,
+		api.RackAnnotation:       rackName,
+	}
 	podAnnotations := map[string]string{}
 
+	// Evicting a Cassandra pod to scale down its node disrupts the datacenter, keep
+	// the cluster autoscaler from doing it unless allowed
+	if !dc.Spec.AllowAutoscalerEviction {
+		podAnnotations[SafeToEvictAnnotation] = "false"
+	}
+	podAnnotations = utils.MergeMap(podAnnotations, dc.Spec.PodAnnotations, discoveryAnnotations)
+
 	if baseTemplate.Annotations == nil {
 		baseTemplate.Annotations = make(map[string]string)
 	}
@@ -635,6 +790,17 @@ func buildPodTemplateSpec(dc *api.CassandraDatacenter, nodeAffinityLabels map[st
 		return nil, err
 	}
 
+	// The config hash lets users compare the pods' configuration with the one in the datacenter status.
+	// It is taken from the config init container, the configuration is only rendered once.
+	for _, container := range baseTemplate.Spec.InitContainers {
+		if container.Name != ServerConfigContainerName {
+			continue
+		}
+		if configHash := configHashFromEnvVars(container.Env); configHash != "" {
+			baseTemplate.Annotations[api.ConfigHashAnnotation] = configHash
+		}
+	}
+
 	// Containers
 
 	err = buildContainers(dc, baseTemplate)
@@ -1140,3 +1140,252 @@ func TestTolerations(t *testing.T) {
 	// using ElementsMatch instead of Equal because we do not really care about ordering.
 	assert.ElementsMatch(t, tolerations, spec.Spec.Tolerations, "tolerations do not match")
 }
+
+func TestCassandraDatacenter_buildContainers_PostStartHook(t *testing.T) {
+	postStart := &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{
+			Command: []string{"/bin/sh", "-c", "echo started"},
+		},
+	}
+
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			PostStartHook: postStart,
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{}
+
+	err := buildContainers(dc, podTemplateSpec)
+	assert.NoError(t, err, "should not have gotten error from calling buildContainers()")
+
+	lifecycle := podTemplateSpec.Spec.Containers[0].Lifecycle
+	assert.NotNil(t, lifecycle)
+	assert.Equal(t, postStart, lifecycle.PostStart)
+	assert.NotNil(t, lifecycle.PreStop, "the operator's preStop hook should still be set")
+	assert.NotNil(t, lifecycle.PreStop.Exec)
+}
+
+func TestCassandraDatacenter_buildPodTemplateSpec_FixPermissions(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			StorageConfig: api.StorageConfig{
+				FixPermissions: true,
+			},
+		},
+	}
+
+	spec, err := buildPodTemplateSpec(dc, nil, "testrack")
+	assert.NoError(t, err, "should not have gotten error when building podTemplateSpec")
+
+	initContainers := spec.Spec.InitContainers
+	assert.Len(t, initContainers, 2)
+	assert.Equal(t, FixPermissionsContainerName, initContainers[0].Name)
+	assert.Equal(t, ServerConfigContainerName, initContainers[1].Name)
+
+	fixPermissions := initContainers[0]
+	assert.Equal(t, []string{"/bin/sh", "-c", "chown -R 999:999 /var/lib/cassandra"}, fixPermissions.Command)
+	assert.Equal(t, int64(0), *fixPermissions.SecurityContext.RunAsUser)
+	assert.Equal(t, []corev1.VolumeMount{{Name: PvcName, MountPath: "/var/lib/cassandra"}}, fixPermissions.VolumeMounts)
+
+	dc.Spec.StorageConfig.FixPermissions = false
+	spec, err = buildPodTemplateSpec(dc, nil, "testrack")
+	assert.NoError(t, err, "should not have gotten error when building podTemplateSpec")
+	assert.Len(t, spec.Spec.InitContainers, 1)
+	assert.Equal(t, ServerConfigContainerName, spec.Spec.InitContainers[0].Name)
+}
+
+func TestCassandraDatacenter_buildPodTemplateSpec_PodAnnotations(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "test",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			PodAnnotations: map[string]string{
+				"sidecar.istio.io/inject": "false",
+			},
+		},
+	}
+
+	spec, err := buildPodTemplateSpec(dc, nil, "testrack")
+	assert.NoError(t, err, "should not have gotten error when building podTemplateSpec")
+	assert.Equal(t, "false", spec.Annotations["sidecar.istio.io/inject"])
+
+	services := []*corev1.Service{
+		newServiceForCassandraDatacenter(dc),
+		newSeedServiceForCassandraDatacenter(dc),
+		newAllPodsServiceForCassandraDatacenter(dc),
+	}
+	for _, service := range services {
+		assert.NotContains(t, service.Annotations, "sidecar.istio.io/inject", "service %s", service.Name)
+	}
+}
+
+func TestCassandraDatacenter_buildContainers_EnforceGuaranteedQoS(t *testing.T) {
+	limits := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+	}
+
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:          "bob",
+			ServerType:           "cassandra",
+			ServerVersion:        "3.11.7",
+			EnforceGuaranteedQoS: true,
+			Resources: corev1.ResourceRequirements{
+				Limits: limits,
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{}
+	err := buildContainers(dc, podTemplateSpec)
+	assert.NoError(t, err, "should not have gotten error from calling buildContainers()")
+
+	resources := podTemplateSpec.Spec.Containers[0].Resources
+	assert.Equal(t, limits, resources.Limits)
+	assert.Equal(t, limits, resources.Requests)
+
+	dc.Spec.Resources = corev1.ResourceRequirements{}
+	err = buildContainers(dc, &corev1.PodTemplateSpec{})
+	assert.Error(t, err, "should not allow enforceGuaranteedQoS without limits")
+}
+
+func TestCassandraDatacenter_buildPodTemplateSpec_DiscoveryAnnotations(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "test",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "Test Cluster",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+		},
+	}
+
+	configHash, err := getConfigHash(dc)
+	assert.NoError(t, err)
+
+	expected := map[string]string{
+		api.ClusterAnnotation:    "Test Cluster",
+		api.DatacenterAnnotation: "dc1",
+		api.RackAnnotation:       "rack1",
+		SafeToEvictAnnotation:    "false",
+		api.ConfigHashAnnotation: configHash,
+	}
+
+	spec, err := buildPodTemplateSpec(dc, nil, "rack1")
+	assert.NoError(t, err, "should not have gotten error when building podTemplateSpec")
+	assert.Equal(t, expected, spec.Annotations)
+
+	// User annotations are added alongside, but can't replace the discovery annotations
+	dc.Spec.PodAnnotations = map[string]string{
+		"backup.example.com/enabled": "true",
+		api.RackAnnotation:           "other",
+	}
+	spec, err = buildPodTemplateSpec(dc, nil, "rack1")
+	assert.NoError(t, err, "should not have gotten error when building podTemplateSpec")
+	for k, v := range expected {
+		assert.Equal(t, v, spec.Annotations[k])
+	}
+	assert.Equal(t, "true", spec.Annotations["backup.example.com/enabled"])
+}
+
+func TestCassandraDatacenter_buildPodTemplateSpec_SafeToEvict(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "test",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+		},
+	}
+
+	spec, err := buildPodTemplateSpec(dc, nil, "testrack")
+	assert.NoError(t, err, "should not have gotten error when building podTemplateSpec")
+	assert.Equal(t, "false", spec.Annotations[SafeToEvictAnnotation])
+
+	// The annotation can be overridden
+	dc.Spec.PodAnnotations = map[string]string{SafeToEvictAnnotation: "true"}
+	spec, err = buildPodTemplateSpec(dc, nil, "testrack")
+	assert.NoError(t, err, "should not have gotten error when building podTemplateSpec")
+	assert.Equal(t, "true", spec.Annotations[SafeToEvictAnnotation])
+
+	dc.Spec.PodAnnotations = nil
+	dc.Spec.AllowAutoscalerEviction = true
+	spec, err = buildPodTemplateSpec(dc, nil, "testrack")
+	assert.NoError(t, err, "should not have gotten error when building podTemplateSpec")
+	assert.NotContains(t, spec.Annotations, SafeToEvictAnnotation)
+}
+
+func TestEnvVarsEqual(t *testing.T) {
+	env := []corev1.EnvVar{
+		{Name: "MAX_HEAP_SIZE", Value: "1G"},
+		{Name: "HEAP_NEWSIZE", Value: "200M"},
+		{Name: "POD_IP", ValueFrom: selectorFromFieldPath("status.podIP")},
+	}
+
+	tests := []struct {
+		name    string
+		a       []corev1.EnvVar
+		b       []corev1.EnvVar
+		ignored []string
+		want    bool
+	}{
+		{
+			name: "identical",
+			a:    env,
+			b:    env,
+			want: true,
+		},
+		{
+			name: "reordered",
+			a:    env,
+			b:    []corev1.EnvVar{env[2], env[0], env[1]},
+			want: true,
+		},
+		{
+			name: "value changed",
+			a:    env,
+			b:    []corev1.EnvVar{env[2], {Name: "MAX_HEAP_SIZE", Value: "2G"}, env[1]},
+			want: false,
+		},
+		{
+			name: "variable removed",
+			a:    env,
+			b:    env[:2],
+			want: false,
+		},
+		{
+			name:    "ignored variable removed",
+			a:       env,
+			b:       []corev1.EnvVar{env[1], env[0]},
+			ignored: []string{"POD_IP"},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EnvVarsEqual(tt.a, tt.b, tt.ignored...))
+		})
+	}
+}
@@ -15,6 +15,24 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// datacenterServicePortNames maps the server ports exposed by the datacenter service to the names
+// of the service ports
+var datacenterServicePortNames = map[string]string{
+	"native":        "native",
+	"tls-native":    "tls-native",
+	"mgmt-api-http": "mgmt-api",
+	"prometheus":    "prometheus",
+	"thrift":        "thrift",
+	"dsefs-public":  "dsefs-public",
+	"spark-worker":  "spark-worker",
+	"jobserver":     "jobserver",
+	"always-on-sql": "always-on-sql",
+	"sql-thrift":    "sql-thrift",
+	"spark-history": "spark-history",
+	"gremlin":       "gremlin",
+	"solr":          "solr",
+}
+
 // Creates a headless service object for the Datacenter, for clients wanting to
 // reach out to a ready Server node for either CQL or mgmt API
 func newServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *corev1.Service {
@@ -22,50 +40,23 @@ func newServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *corev1.Servi
 	service := makeGenericHeadlessService(dc)
 	service.ObjectMeta.Name = svcName
 
-	nativePort := api.DefaultNativePort
-	if dc.IsNodePortEnabled() {
-		nativePort = dc.GetNodePortNativePort()
-	}
-
-	ports := []corev1.ServicePort{
-		namedServicePort("native", nativePort, nativePort),
-		namedServicePort("tls-native", 9142, 9142),
-		namedServicePort("mgmt-api", 8080, 8080),
-		namedServicePort("prometheus", 9103, 9103),
-		namedServicePort("thrift", 9160, 9160),
-	}
-
-	if dc.Spec.DseWorkloads != nil {
-		if dc.Spec.DseWorkloads.AnalyticsEnabled {
-			ports = append(
-				ports,
-				namedServicePort("dsefs-public", 5598, 5598),
-				namedServicePort("spark-worker", 7081, 7081),
-				namedServicePort("jobserver", 8090, 8090),
-				namedServicePort("always-on-sql", 9077, 9077),
-				namedServicePort("sql-thrift", 10000, 10000),
-				namedServicePort("spark-history", 18080, 18080),
-			)
-		}
-
-		if dc.Spec.DseWorkloads.GraphEnabled {
-			ports = append(
-				ports,
-				namedServicePort("gremlin", 8182, 8182),
-			)
+	var ports []corev1.ServicePort
+	for _, containerPort := range ComputeServerPorts(dc) {
+		name, exposed := datacenterServicePortNames[containerPort.Name]
+		if !exposed {
+			continue
 		}
-
-		if dc.Spec.DseWorkloads.SearchEnabled {
-			ports = append(
-				ports,
-				namedServicePort("solr", 8983, 8983),
-			)
+		port := int(containerPort.ContainerPort)
+		if containerPort.Name == "native" && dc.IsNodePortEnabled() {
+			port = dc.GetNodePortNativePort()
 		}
+		ports = append(ports, namedServicePort(name, port, port))
 	}
 
 	service.Spec.Ports = ports
 
 	addAdditionalOptions(service, &dc.Spec.AdditionalServiceConfig.DatacenterService)
+	addAdditionalAnnotations(service, dc)
 
 	utils.AddHashAnnotation(service)
 
@@ -92,6 +83,18 @@ func addAdditionalOptions(service *corev1.Service, serviceConfig *api.ServiceCon
 	}
 }
 
+// addAdditionalAnnotations adds the additionalAnnotations of the dc, the annotations of the
+// service config take precedence
+func addAdditionalAnnotations(service *corev1.Service, dc *api.CassandraDatacenter) {
+	if len(dc.Spec.AdditionalAnnotations) == 0 {
+		return
+	}
+	if service.Annotations == nil {
+		service.Annotations = make(map[string]string, len(dc.Spec.AdditionalAnnotations))
+	}
+	oplabels.AddOperatorAnnotations(service.Annotations, dc)
+}
+
 func namedServicePort(name string, port int, targetPort int) corev1.ServicePort {
 	return corev1.ServicePort{Name: name, Port: int32(port), TargetPort: intstr.FromInt(targetPort)}
 }
@@ -119,6 +122,7 @@ func newSeedServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *corev1.S
 	service.Spec.PublishNotReadyAddresses = true
 
 	addAdditionalOptions(service, &dc.Spec.AdditionalServiceConfig.SeedService)
+	addAdditionalAnnotations(service, dc)
 
 	utils.AddHashAnnotation(service)
 
@@ -140,6 +144,7 @@ func newAdditionalSeedServiceForCassandraDatacenter(dc *api.CassandraDatacenter)
 	service.Spec.PublishNotReadyAddresses = true
 
 	addAdditionalOptions(&service, &dc.Spec.AdditionalServiceConfig.AdditionalSeedService)
+	addAdditionalAnnotations(&service, dc)
 
 	utils.AddHashAnnotation(&service)
 
@@ -232,11 +237,13 @@ func newNodePortServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *core
 	}
 
 	addAdditionalOptions(service, &dc.Spec.AdditionalServiceConfig.NodePortService)
+	addAdditionalAnnotations(service, dc)
 	return service
 }
 
 // newAllPodsServiceForCassandraDatacenter creates a headless service owned by the CassandraDatacenter,
-// which covers all server pods in the datacenter, whether they are ready or not
+// which covers all server pods in the datacenter, whether they are ready or not. It is the governing
+// service of the StatefulSets, which gives each pod the stable <pod>.<service>.<namespace>.svc DNS name.
 func newAllPodsServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *corev1.Service {
 	service := makeGenericHeadlessService(dc)
 	service.ObjectMeta.Name = dc.GetAllPodsServiceName()
@@ -261,6 +268,7 @@ func newAllPodsServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *corev
 	}
 
 	addAdditionalOptions(service, &dc.Spec.AdditionalServiceConfig.AllPodsService)
+	addAdditionalAnnotations(service, dc)
 
 	utils.AddHashAnnotation(service)
 
@@ -4,12 +4,14 @@
 package reconciliation
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/k8ssandra/cass-operator/pkg/oplabels"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
@@ -76,6 +78,34 @@ func TestServiceNameGeneration(t *testing.T) {
 	assert.Equal(t, "notcool-bob-seed-service", service.Name)
 }
 
+func TestSeedServiceNameOverride(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:     "bob",
+			SeedServiceName: "custom-seeds",
+			AdditionalServiceConfig: api.ServiceConfig{
+				SeedService: api.ServiceConfigAdditions{
+					Labels: map[string]string{
+						"discovery.example.com/seeds": "true",
+					},
+				},
+			},
+		},
+	}
+
+	service := newSeedServiceForCassandraDatacenter(dc)
+	assert.Equal(t, "custom-seeds", service.Name)
+	assert.Equal(t, "true", service.Labels["discovery.example.com/seeds"])
+
+	// The seeds in the server config resolve through the renamed service
+	configJson, err := dc.GetConfigAsJSON(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, configJson, `"seeds":"custom-seeds,`)
+}
+
 func TestLabelsWithNewSeedServiceForCassandraDatacenter(t *testing.T) {
 	dc := &api.CassandraDatacenter{
 		ObjectMeta: metav1.ObjectMeta{
@@ -407,3 +437,59 @@ func TestAddingAdditionalLabels(t *testing.T) {
 		t.Errorf("service labels = %v, want %v", service.Labels, expected)
 	}
 }
+
+func TestComputeServerPorts(t *testing.T) {
+	assert := assert.New(t)
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "piclem",
+			ServerType:    "dse",
+			ServerVersion: "6.8.4",
+		},
+	}
+
+	ports := ComputeServerPorts(dc)
+	assert.Contains(ports, corev1.ContainerPort{Name: "jmx", ContainerPort: 7199})
+	assert.Contains(ports, corev1.ContainerPort{Name: "prometheus", ContainerPort: 9103})
+	assert.Contains(ports, corev1.ContainerPort{Name: "mgmt-api-http", ContainerPort: 8080})
+	assert.Contains(ports, corev1.ContainerPort{Name: "internode-msg", ContainerPort: 8609})
+	assert.NotContains(ports, corev1.ContainerPort{Name: "solr", ContainerPort: 8983})
+
+	// The metrics are exposed by the datacenter service, JMX is not
+	servicePorts := newServiceForCassandraDatacenter(dc).Spec.Ports
+	assert.Contains(servicePorts, namedServicePort("prometheus", 9103, 9103))
+	assert.Contains(servicePorts, namedServicePort("mgmt-api", 8080, 8080))
+	for _, port := range servicePorts {
+		assert.NotEqual("jmx", port.Name)
+	}
+
+	// Enabling a DSE workload adds its ports to both the container and the service
+	dc.Spec.DseWorkloads = &api.DseWorkloads{SearchEnabled: true}
+	assert.Contains(ComputeServerPorts(dc), corev1.ContainerPort{Name: "solr", ContainerPort: 8983})
+	assert.Contains(newServiceForCassandraDatacenter(dc).Spec.Ports, namedServicePort("solr", 8983, 8983))
+}
+
+func TestDatacenterServiceTLSNativePort(t *testing.T) {
+	assert := assert.New(t)
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "piclem",
+			ServerType:    "cassandra",
+			ServerVersion: "4.0.1",
+		},
+	}
+
+	// The port is exposed whether client encryption is enabled in the config or some other way
+	tlsNative := namedServicePort("tls-native", 9142, 9142)
+	assert.Contains(newServiceForCassandraDatacenter(dc).Spec.Ports, tlsNative)
+
+	dc.Spec.Config = json.RawMessage(`{"cassandra-yaml": {"client_encryption_options": {"enabled": true}}}`)
+	assert.Contains(newServiceForCassandraDatacenter(dc).Spec.Ports, tlsNative)
+	assert.Contains(ComputeServerPorts(dc), corev1.ContainerPort{Name: "tls-native", ContainerPort: 9142})
+}
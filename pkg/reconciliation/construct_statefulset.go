@@ -17,6 +17,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -113,6 +114,12 @@ func newStatefulSetForCassandraDatacenter(
 
 	statefulSetSelectorLabels := dc.GetRackLabels(rackName)
 
+	pvcAnnotations := map[string]string{}
+	oplabels.AddOperatorAnnotations(pvcAnnotations, dc)
+	if len(pvcAnnotations) == 0 {
+		pvcAnnotations = nil
+	}
+
 	var volumeClaimTemplates []corev1.PersistentVolumeClaim
 
 	nodeAffinityLabels, nodeAffinityLabelsConfigurationError := rackNodeAffinitylabels(dc, rackName)
@@ -128,8 +135,9 @@ func newStatefulSetForCassandraDatacenter(
 
 	volumeClaimTemplates = []corev1.PersistentVolumeClaim{{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels: pvcLabels,
-			Name:   PvcName,
+			Labels:      pvcLabels,
+			Annotations: pvcAnnotations,
+			Name:        PvcName,
 		},
 		Spec: *dc.Spec.StorageConfig.CassandraDataVolumeClaimSpec,
 	}}
@@ -137,8 +145,9 @@ func newStatefulSetForCassandraDatacenter(
 	for _, storage := range dc.Spec.StorageConfig.AdditionalVolumes {
 		pvc := corev1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:   storage.Name,
-				Labels: pvcLabels,
+				Name:        storage.Name,
+				Labels:      pvcLabels,
+				Annotations: pvcAnnotations,
 			},
 			Spec: storage.PVCSpec,
 		}
@@ -172,12 +181,13 @@ func newStatefulSetForCassandraDatacenter(
 			},
 			Replicas:             &replicaCountInt32,
 			ServiceName:          dc.GetAllPodsServiceName(),
-			PodManagementPolicy:  appsv1.ParallelPodManagement,
+			PodManagementPolicy:  podManagementPolicy(dc),
 			Template:             *template,
 			VolumeClaimTemplates: volumeClaimTemplates,
 		},
 	}
 	result.Annotations = map[string]string{}
+	oplabels.AddOperatorAnnotations(result.Annotations, dc)
 
 	if sts != nil && sts.Spec.ServiceName != "" && sts.Spec.ServiceName != result.Spec.ServiceName {
 		result.Spec.ServiceName = sts.Spec.ServiceName
@@ -192,3 +202,89 @@ func newStatefulSetForCassandraDatacenter(
 
 	return result, nil
 }
+
+// podManagementPolicy returns the PodManagementPolicy of the datacenter, Parallel if unset
+func podManagementPolicy(dc *api.CassandraDatacenter) appsv1.PodManagementPolicyType {
+	if dc.Spec.PodManagementPolicy == "" {
+		return appsv1.ParallelPodManagement
+	}
+	return appsv1.PodManagementPolicyType(dc.Spec.PodManagementPolicy)
+}
+
+// StorageConfigEqual reports whether two StorageConfigs request the same storage. Fields that
+// Kubernetes defaults on the claim (such as a nil volumeMode) are ignored and quantities are
+// compared semantically, so only a meaningful change makes them unequal.
+func StorageConfigEqual(a, b api.StorageConfig) bool {
+	if (a.CassandraDataVolumeClaimSpec == nil) != (b.CassandraDataVolumeClaimSpec == nil) {
+		return false
+	}
+	if a.CassandraDataVolumeClaimSpec != nil &&
+		!claimSpecEqual(*a.CassandraDataVolumeClaimSpec, *b.CassandraDataVolumeClaimSpec) {
+		return false
+	}
+
+	if len(a.AdditionalVolumes) != len(b.AdditionalVolumes) {
+		return false
+	}
+	for i := range a.AdditionalVolumes {
+		volA, volB := a.AdditionalVolumes[i], b.AdditionalVolumes[i]
+		if volA.Name != volB.Name || volA.MountPath != volB.MountPath ||
+			!claimSpecEqual(volA.PVCSpec, volB.PVCSpec) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// VolumeClaimTemplatesEqual reports whether two sets of volumeClaimTemplates, matched by name,
+// request the same storage size, access modes and storage class. As the templates of a StatefulSet
+// are immutable, the StatefulSet has to be recreated when they are not. The size of the server data
+// template is left out, CheckVolumeExpansion grows those claims in place.
+func VolumeClaimTemplatesEqual(a, b []corev1.PersistentVolumeClaim) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	templatesB := make(map[string]*corev1.PersistentVolumeClaim, len(b))
+	for i := range b {
+		templatesB[b[i].Name] = &b[i]
+	}
+
+	for i := range a {
+		templateA := &a[i]
+		templateB, found := templatesB[templateA.Name]
+		if !found {
+			return false
+		}
+
+		storageA := templateA.Spec.Resources.Requests[corev1.ResourceStorage]
+		storageB := templateB.Spec.Resources.Requests[corev1.ResourceStorage]
+		if templateA.Name != PvcName && storageA.Cmp(storageB) != 0 {
+			return false
+		}
+
+		if !utils.ElementsMatch(templateA.Spec.AccessModes, templateB.Spec.AccessModes) {
+			return false
+		}
+
+		if !equality.Semantic.DeepEqual(templateA.Spec.StorageClassName, templateB.Spec.StorageClassName) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func claimSpecEqual(a, b corev1.PersistentVolumeClaimSpec) bool {
+	return equality.Semantic.DeepEqual(withClaimSpecDefaults(a), withClaimSpecDefaults(b))
+}
+
+func withClaimSpecDefaults(spec corev1.PersistentVolumeClaimSpec) corev1.PersistentVolumeClaimSpec {
+	spec = *spec.DeepCopy()
+	if spec.VolumeMode == nil {
+		volumeMode := corev1.PersistentVolumeFilesystem
+		spec.VolumeMode = &volumeMode
+	}
+	return spec
+}
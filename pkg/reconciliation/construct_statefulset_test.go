@@ -6,8 +6,10 @@ package reconciliation
 import (
 	"fmt"
 	"github.com/k8ssandra/cass-operator/pkg/oplabels"
+	"github.com/k8ssandra/cass-operator/pkg/utils"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"reflect"
 	"testing"
@@ -122,6 +124,44 @@ func Test_newStatefulSetForCassandraDatacenter_additionalLabels(t *testing.T) {
 
 }
 
+func Test_newStatefulSetForCassandraDatacenter_additionalLabelsAndAnnotations(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "piclem",
+			ServerType:    "cassandra",
+			ServerVersion: "4.0.1",
+			StorageConfig: api.StorageConfig{
+				CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{},
+			},
+			AdditionalLabels: map[string]string{
+				oplabels.ManagedByLabel: "someone-else",
+				api.RackLabel:           "other-rack",
+				"cost-center":           "db",
+			},
+			AdditionalAnnotations: map[string]string{
+				"billing/owner": "team-a",
+			},
+		},
+	}
+
+	statefulset, err := newStatefulSetForCassandraDatacenter(nil, "default", dc, 1, false)
+	require.NoError(t, err)
+
+	// the labels set by the operator win on conflict
+	for _, labels := range []map[string]string{statefulset.Labels, statefulset.Spec.VolumeClaimTemplates[0].Labels} {
+		assert.Equal(t, oplabels.ManagedByLabelValue, labels[oplabels.ManagedByLabel])
+		assert.Equal(t, "default", labels[api.RackLabel])
+		assert.Equal(t, "db", labels["cost-center"])
+	}
+
+	assert.Equal(t, "team-a", statefulset.Annotations["billing/owner"])
+	assert.Contains(t, statefulset.Annotations, utils.ResourceHashAnnotationKey)
+	assert.Equal(t, map[string]string{"billing/owner": "team-a"}, statefulset.Spec.VolumeClaimTemplates[0].Annotations)
+}
+
 func Test_newStatefulSetForCassandraDatacenter_rackNodeAffinitylabels(t *testing.T) {
 	dc := &api.CassandraDatacenter{
 		Spec: api.CassandraDatacenterSpec{
@@ -176,6 +216,29 @@ func Test_newStatefulSetForCassandraDatacenter_ServiceName(t *testing.T) {
 	assert.Equal(t, dc.GetAllPodsServiceName(), sts.Spec.ServiceName)
 }
 
+func Test_newStatefulSetForCassandraDatacenter_PodManagementPolicy(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "test",
+			ServerType:    "cassandra",
+			ServerVersion: "4.0.3",
+			Size:          1,
+			StorageConfig: api.StorageConfig{
+				CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{},
+			},
+		},
+	}
+
+	sts, err := newStatefulSetForCassandraDatacenter(nil, "default", dc, 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, appsv1.ParallelPodManagement, sts.Spec.PodManagementPolicy)
+
+	dc.Spec.PodManagementPolicy = "OrderedReady"
+	sts, err = newStatefulSetForCassandraDatacenter(nil, "default", dc, 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, appsv1.OrderedReadyPodManagement, sts.Spec.PodManagementPolicy)
+}
+
 func Test_newStatefulSetForCassandraDatacenterWithAdditionalVolumes(t *testing.T) {
 	type args struct {
 		rackName     string
@@ -456,3 +519,121 @@ func int64Ptr(n int64) *int64 {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestStorageConfigEqual(t *testing.T) {
+	storageClass := "standard"
+	filesystem := corev1.PersistentVolumeFilesystem
+	storageConfig := func(size string, volumeMode *corev1.PersistentVolumeMode) api.StorageConfig {
+		return api.StorageConfig{
+			CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				VolumeMode:       volumeMode,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		a    api.StorageConfig
+		b    api.StorageConfig
+		want bool
+	}{
+		{
+			name: "identical",
+			a:    storageConfig("1Gi", nil),
+			b:    storageConfig("1Gi", nil),
+			want: true,
+		},
+		{
+			name: "only the defaulted volumeMode differs",
+			a:    storageConfig("1Gi", nil),
+			b:    storageConfig("1Gi", &filesystem),
+			want: true,
+		},
+		{
+			name: "same size in different units",
+			a:    storageConfig("1Gi", nil),
+			b:    storageConfig("1024Mi", nil),
+			want: true,
+		},
+		{
+			name: "size changed",
+			a:    storageConfig("1Gi", nil),
+			b:    storageConfig("2Gi", nil),
+			want: false,
+		},
+		{
+			name: "claim spec removed",
+			a:    storageConfig("1Gi", nil),
+			b:    api.StorageConfig{},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StorageConfigEqual(tt.a, tt.b))
+		})
+	}
+}
+
+func TestVolumeClaimTemplatesEqual(t *testing.T) {
+	template := func(name, size, storageClass string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: v1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		a    []corev1.PersistentVolumeClaim
+		b    []corev1.PersistentVolumeClaim
+		want bool
+	}{
+		{
+			name: "equal templates",
+			a:    []corev1.PersistentVolumeClaim{template(PvcName, "1Gi", "standard"), template("commitlogs", "1Gi", "standard")},
+			b:    []corev1.PersistentVolumeClaim{template("commitlogs", "1024Mi", "standard"), template(PvcName, "1Gi", "standard")},
+			want: true,
+		},
+		{
+			name: "storage class changed",
+			a:    []corev1.PersistentVolumeClaim{template(PvcName, "1Gi", "standard")},
+			b:    []corev1.PersistentVolumeClaim{template(PvcName, "1Gi", "fast")},
+			want: false,
+		},
+		{
+			name: "server data size changed",
+			a:    []corev1.PersistentVolumeClaim{template(PvcName, "1Gi", "standard")},
+			b:    []corev1.PersistentVolumeClaim{template(PvcName, "2Gi", "standard")},
+			want: true,
+		},
+		{
+			name: "additional volume size changed",
+			a:    []corev1.PersistentVolumeClaim{template(PvcName, "1Gi", "standard"), template("commitlogs", "1Gi", "standard")},
+			b:    []corev1.PersistentVolumeClaim{template(PvcName, "1Gi", "standard"), template("commitlogs", "2Gi", "standard")},
+			want: false,
+		},
+		{
+			name: "template added",
+			a:    []corev1.PersistentVolumeClaim{template(PvcName, "1Gi", "standard")},
+			b:    []corev1.PersistentVolumeClaim{template(PvcName, "1Gi", "standard"), template("commitlogs", "1Gi", "standard")},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, VolumeClaimTemplatesEqual(tt.a, tt.b))
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/k8ssandra/cass-operator/pkg/oplabels"
 	"github.com/k8ssandra/cass-operator/pkg/utils"
 
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -43,6 +45,86 @@ func newPodDisruptionBudgetForDatacenter(dc *api.CassandraDatacenter) *policyv1.
 	return pdb
 }
 
+// newNetworkPolicyForDatacenter creates a NetworkPolicy object restricting the traffic to the pods of the
+// Datacenter, see CreateNetworkPolicy
+func newNetworkPolicyForDatacenter(dc *api.CassandraDatacenter) *networkingv1.NetworkPolicy {
+	labels := dc.GetDatacenterLabels()
+	oplabels.AddOperatorLabels(labels, dc)
+
+	internodePorts := networkPolicyPorts(dc, "internode", "tls-internode", "internode-msg")
+	clientPorts := networkPolicyPorts(dc, "native", "tls-native")
+	openPorts := networkPolicyPorts(dc, "mgmt-api-http", "prometheus")
+
+	// The other datacenters of the cluster can be in other namespaces
+	clusterPeer := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{},
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: dc.GetClusterLabels(),
+		},
+	}
+
+	clientPeer := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{},
+	}
+	if len(dc.Spec.NetworkPolicyClientNamespaceLabels) > 0 {
+		clientPeer = networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: dc.Spec.NetworkPolicyClientNamespaceLabels,
+			},
+		}
+	}
+
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        dc.Name + "-network-policy",
+			Namespace:   dc.Namespace,
+			Labels:      labels,
+			Annotations: map[string]string{},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: dc.GetDatacenterLabels(),
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From:  []networkingv1.NetworkPolicyPeer{clusterPeer},
+					Ports: append(internodePorts, clientPorts...),
+				},
+				{
+					From:  []networkingv1.NetworkPolicyPeer{clientPeer},
+					Ports: clientPorts,
+				},
+				{
+					Ports: openPorts,
+				},
+			},
+		},
+	}
+
+	// add a hash here to facilitate checking if updates are needed
+	utils.AddHashAnnotation(networkPolicy)
+
+	return networkPolicy
+}
+
+// networkPolicyPorts returns the TCP ports of the server container with the given names
+func networkPolicyPorts(dc *api.CassandraDatacenter, names ...string) []networkingv1.NetworkPolicyPort {
+	ports := []networkingv1.NetworkPolicyPort{}
+	for _, containerPort := range ComputeServerPorts(dc) {
+		if utils.IndexOfString(names, containerPort.Name) < 0 {
+			continue
+		}
+		protocol := corev1.ProtocolTCP
+		port := intstr.FromInt(int(containerPort.ContainerPort))
+		ports = append(ports, networkingv1.NetworkPolicyPort{
+			Protocol: &protocol,
+			Port:     &port,
+		})
+	}
+	return ports
+}
+
 func setOperatorProgressStatus(rc *ReconciliationContext, newState api.ProgressState) error {
 	currentState := rc.Datacenter.Status.CassandraOperatorProgress
 	if currentState == newState {
@@ -47,6 +47,10 @@ type ReconciliationContext struct {
 	statefulSets           []*appsv1.StatefulSet
 	dcPods                 []*corev1.Pod
 	clusterPods            []*corev1.Pod
+
+	// rackTolerations holds the tolerations discovered for the racks whose nodes were found, see
+	// datacenterForRack
+	rackTolerations map[string][]corev1.Toleration
 }
 
 // CreateReconciliationContext gathers all information needed for computeReconciliationActions into a struct.
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -15,6 +16,7 @@ import (
 	"github.com/k8ssandra/cass-operator/pkg/events"
 	"github.com/k8ssandra/cass-operator/pkg/httphelper"
 	"github.com/k8ssandra/cass-operator/pkg/internal/result"
+	"github.com/k8ssandra/cass-operator/pkg/utils"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -62,9 +64,23 @@ func (rc *ReconciliationContext) DecommissionNodes(epData httphelper.CassMetadat
 	}
 
 	if currentSize <= targetSize {
+		if err := rc.clearScaleDownRefused(); err != nil {
+			return result.Error(err)
+		}
 		return result.Continue()
 	}
 
+	// Nodes are removed strictly one at a time, the next decommission only starts once the pod
+	// and the PVCs of the previously decommissioned node are gone
+	pending, err := rc.pendingDecommissionCleanup()
+	if err != nil {
+		return result.Error(err)
+	}
+	if pending != "" {
+		logger.Info("Waiting for the previously decommissioned node to be removed", "pending", pending)
+		return result.RequeueSoon(5)
+	}
+
 	decommRackInfo, err := rc.CalculateRackInfoForDecomm(int(currentSize))
 	if err != nil {
 		logger.Error(err, "error calculating rack info for decommissioning nodes")
@@ -73,6 +89,9 @@ func (rc *ReconciliationContext) DecommissionNodes(epData httphelper.CassMetadat
 
 	for idx := range decommRackInfo {
 		rackInfo := decommRackInfo[idx]
+		if rc.isRackPaused(rackInfo.RackName) {
+			continue
+		}
 		statefulSet := rc.statefulSets[idx]
 		desiredNodeCount := int32(rackInfo.NodeCount)
 		maxReplicas := *statefulSet.Spec.Replicas
@@ -81,6 +100,13 @@ func (rc *ReconciliationContext) DecommissionNodes(epData httphelper.CassMetadat
 		if maxReplicas > desiredNodeCount {
 			logger.V(1).Info("reconcile_racks::DecommissionNodes::scaleDownRack", "Rack", rackInfo.RackName, "maxReplicas", maxReplicas, "desiredNodeCount", desiredNodeCount)
 
+			if dc.GetConditionStatus(api.DatacenterScalingDown) != corev1.ConditionTrue {
+				if remaining := rc.scaleCooldownRemaining(); remaining > 0 {
+					logger.Info("Waiting for the scale cooldown to end before scaling down", "remaining", remaining)
+					return result.RequeueSoon(int(remaining.Seconds()) + 1)
+				}
+			}
+
 			dcPatch := client.MergeFrom(dc.DeepCopy())
 			updated := false
 
@@ -111,7 +137,12 @@ func (rc *ReconciliationContext) DecommissionNodes(epData httphelper.CassMetadat
 			}
 
 			err := rc.DecommissionNodeOnRack(rackInfo.RackName, epData, lastPodSuffix)
-			if err != nil {
+			if refused, ok := err.(*scaleDownRefusedError); ok {
+				return rc.refuseScaleDown(refused)
+			} else if err != nil {
+				return result.Error(err)
+			}
+			if err := rc.clearScaleDownRefused(); err != nil {
 				return result.Error(err)
 			}
 
@@ -131,11 +162,24 @@ func (rc *ReconciliationContext) DecommissionNodeOnRack(rackName string, epData
 				return fmt.Errorf("management API is not up on node that we are trying to decommission")
 			}
 
+			// callDecommission checks it again, this refuses before taking a snapshot of the node
+			if err := rc.ensureNotLastHealthyNode(pod, epData); err != nil {
+				return err
+			}
+
 			if err := rc.EnsurePodsCanAbsorbDecommData(pod, epData); err != nil {
 				return err
 			}
 
-			if err := rc.callDecommission(pod); err != nil {
+			if err := rc.ensureReplicationFactorsFit(pod); err != nil {
+				return err
+			}
+
+			if err := rc.TakeSnapshot([]*corev1.Pod{pod}, snapshotOperationDecommission); err != nil {
+				return err
+			}
+
+			if err := rc.callDecommission(pod, epData); err != nil {
 				return err
 			}
 
@@ -149,6 +193,14 @@ func (rc *ReconciliationContext) DecommissionNodeOnRack(rackName string, epData
 			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.LabeledPodAsDecommissioning,
 				"Labeled node as decommissioning %s", pod.Name)
 
+			// Kept in the status so that it is clear which node is being decommissioned, the label
+			// lets the scale down resume after a restart of the operator
+			dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+			rc.Datacenter.Status.DecommissioningNode = pod.Name
+			if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+				return err
+			}
+
 			return nil
 		}
 	}
@@ -157,28 +209,226 @@ func (rc *ReconciliationContext) DecommissionNodeOnRack(rackName string, epData
 	return fmt.Errorf("could not find pod to decommission on rack %s", rackName)
 }
 
-func (rc *ReconciliationContext) callDecommission(pod *corev1.Pod) error {
+// ensureNotLastHealthyNode refuses to decommission a node if no other node of the cluster
+// is up and normal, as that would leave the cluster completely unavailable.
+func (rc *ReconciliationContext) ensureNotLastHealthyNode(decommPod *corev1.Pod, epData httphelper.CassMetadataEndpoints) error {
+	decommHostID := ""
+	if nodeStatus, found := rc.Datacenter.Status.NodeStatuses[decommPod.Name]; found {
+		decommHostID = nodeStatus.HostID
+	}
+
+	healthyNodes := 0
+	for idx := range epData.Entity {
+		ep := &epData.Entity[idx]
+		if ep.GetRpcAddress() == decommPod.Status.PodIP || ep.EndpointIP == decommPod.Status.PodIP {
+			continue
+		}
+		if decommHostID != "" && ep.HostID == decommHostID {
+			continue
+		}
+		if ep.IsAlive == "true" && ep.HasStatus(httphelper.StatusNormal) {
+			healthyNodes++
+		}
+	}
+
+	if healthyNodes == 0 {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.RefusedDecommission,
+			"Refusing to decommission %s, no other node is up and normal", decommPod.Name)
+		return fmt.Errorf("refusing to decommission %s, no other node is up and normal", decommPod.Name)
+	}
+
+	return nil
+}
+
+// ensureReplicationFactorsFit refuses to decommission a node if the datacenter is being scaled
+// below the replication factor of one of the keyspaces. The check is skipped when the replication
+// settings can't be read from the management API.
+func (rc *ReconciliationContext) ensureReplicationFactorsFit(decommPod *corev1.Pod) error {
+	dc := rc.Datacenter
+	if dc.GetDeletionTimestamp() != nil || dc.GetConditionStatus(api.DatacenterDecommission) == corev1.ConditionTrue {
+		return nil
+	}
+
+	keyspaces, err := rc.NodeMgmtClient.ListKeyspaces(decommPod)
+	if err != nil {
+		rc.ReqLogger.Info("Unable to list the keyspaces, not checking their replication factor", "error", err.Error())
+		return nil
+	}
+
+	maxKeyspace, maxReplicationFactor := "", 0
+	for _, keyspace := range keyspaces {
+		replication, err := rc.NodeMgmtClient.GetKeyspaceReplication(decommPod, keyspace)
+		if err != nil {
+			rc.ReqLogger.Info("Unable to get the replication of keyspace", "keyspace", keyspace, "error", err.Error())
+			continue
+		}
+
+		if rf := ReplicationFactorForDatacenter(replication, dc.Name); rf > maxReplicationFactor {
+			maxKeyspace, maxReplicationFactor = keyspace, rf
+		}
+	}
+
+	// the webhook rejects the sizes below MinimumSafeSize from now on
+	if int32(maxReplicationFactor) != dc.Status.MaxReplicationFactor {
+		dcPatch := client.MergeFrom(dc.DeepCopy())
+		dc.Status.MaxReplicationFactor = int32(maxReplicationFactor)
+		if err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch); err != nil {
+			return err
+		}
+	}
+
+	if dc.Spec.Size < api.MinimumSafeSize(*dc) {
+		return &scaleDownRefusedError{
+			podName:           decommPod.Name,
+			keyspace:          maxKeyspace,
+			replicationFactor: maxReplicationFactor,
+			size:              dc.Spec.Size,
+		}
+	}
+
+	return nil
+}
+
+// scaleDownRefusedError is returned when the scale down would leave fewer nodes than the
+// replication factor of a keyspace
+type scaleDownRefusedError struct {
+	podName           string
+	keyspace          string
+	replicationFactor int
+	size              int32
+}
+
+func (e *scaleDownRefusedError) Error() string {
+	return fmt.Sprintf("refusing to decommission %s, keyspace %s has a replication factor of %d for a size of %d",
+		e.podName, e.keyspace, e.replicationFactor, e.size)
+}
+
+// refuseScaleDown sets the ScaleDownRefused condition and stops the reconcile without requeueing it,
+// retrying would fail the same way until the size is changed, which triggers a new reconcile.
+func (rc *ReconciliationContext) refuseScaleDown(refused *scaleDownRefusedError) result.ReconcileResult {
+	rc.ReqLogger.Info("Refusing to scale down", "reason", refused.Error())
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	message := fmt.Sprintf("keyspace %s has a replication factor of %d for a size of %d",
+		refused.keyspace, refused.replicationFactor, refused.size)
+	condition := api.NewDatacenterConditionWithReason(api.DatacenterScaleDownRefused, corev1.ConditionTrue, "ReplicationFactor", message)
+	if rc.setCondition(condition) {
+		if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+			return result.Error(err)
+		}
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.RefusedDecommission,
+			"Refusing to decommission %s, keyspace %s has a replication factor of %d for a size of %d",
+			refused.podName, refused.keyspace, refused.replicationFactor, refused.size)
+	}
+
+	return result.Done()
+}
+
+// clearScaleDownRefused clears the ScaleDownRefused condition once the size no longer requires it
+func (rc *ReconciliationContext) clearScaleDownRefused() error {
+	if rc.Datacenter.GetConditionStatus(api.DatacenterScaleDownRefused) != corev1.ConditionTrue {
+		return nil
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.setCondition(api.NewDatacenterCondition(api.DatacenterScaleDownRefused, corev1.ConditionFalse))
+	return rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch)
+}
+
+// ReplicationFactorForDatacenter returns the replication factor of a keyspace in the datacenter,
+// given the replication settings of the keyspace, or 0 if it can't be determined
+func ReplicationFactorForDatacenter(replication map[string]string, dcName string) int {
+	value, found := replication[dcName]
+	if strings.HasSuffix(replication["class"], "SimpleStrategy") {
+		value, found = replication["replication_factor"]
+	}
+	if !found {
+		return 0
+	}
+
+	rf, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return rf
+}
+
+var (
+	// decommissionAttempts is how many decommission requests are sent to a node before giving up
+	decommissionAttempts = 3
+	// decommissionRetryBackoff is the wait before the first retry, doubled on every following one
+	decommissionRetryBackoff = 30 * time.Second
+)
+
+// callDecommission sends the decommission request of the pod. All the scale downs go through here,
+// including the requests sent again by CheckDecommissioningNodes, so the last healthy node of the
+// cluster is never decommissioned. A failed request, or a decommission that failed midway, is sent
+// again by the following reconciles once the backoff has passed, up to decommissionAttempts times,
+// then the DecommissionFailed condition is set.
+func (rc *ReconciliationContext) callDecommission(pod *corev1.Pod, epData httphelper.CassMetadataEndpoints) error {
 	if !isPodUp(pod) {
 		// The pod must be started before it can be decommissioned
 		rc.ReqLogger.V(1).Info("Error while trying to decommission, pod isn't running.", "Pod", pod)
 		return nil
 	}
 
+	if err := rc.ensureNotLastHealthyNode(pod, epData); err != nil {
+		return err
+	}
+
+	attempts, retryAt := decommissionAttemptsOf(pod)
+	if time.Now().Before(retryAt) {
+		rc.ReqLogger.V(1).Info("Waiting before sending the decommission request again", "Pod", pod.Name, "retryAt", retryAt)
+		return nil
+	}
+	if attempts >= decommissionAttempts {
+		return rc.setDecommissionFailed(pod, fmt.Errorf("the node did not start decommissioning"))
+	}
+
 	features, err := rc.NodeMgmtClient.FeatureSet(pod)
 	if err != nil {
 		return err
-	} else if features.Supports(httphelper.AsyncSSTableTasks) {
-		if jobId, err := rc.NodeMgmtClient.CallDecommissionNode(pod, true); err != nil {
+	}
+
+	attempts++
+	if err := rc.recordDecommissionAttempt(pod, attempts, ""); err != nil {
+		return err
+	}
+
+	if features.Supports(httphelper.AsyncSSTableTasks) {
+		jobId, err := rc.NodeMgmtClient.CallDecommissionNode(pod, true)
+		if err != nil {
+			rc.ReqLogger.Info("Decommission request failed", "Pod", pod.Name, "attempt", attempts, "error", err.Error())
+			if attempts >= decommissionAttempts {
+				return rc.setDecommissionFailed(pod, err)
+			}
+			return nil
+		}
+		rc.ReqLogger.V(1).Info(fmt.Sprintf("Decommission requested, returned jobId: %s", jobId))
+		if err := rc.recordDecommissionAttempt(pod, attempts, jobId); err != nil {
 			return err
-		} else {
-			rc.ReqLogger.V(1).Info(fmt.Sprintf("Decommission requested, returned jobId: %s", jobId))
+		}
+		if rc.Datacenter.GetConditionStatus(api.DatacenterDecommissionFailed) == corev1.ConditionTrue {
+			dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+			rc.setCondition(api.NewDatacenterCondition(api.DatacenterDecommissionFailed, corev1.ConditionFalse))
+			if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+				return err
+			}
 		}
 	} else {
-		// Fallback to older code
+		// Fallback to older code, the request only returns once the node left the ring. If it fails,
+		// the node does not start decommissioning and CheckDecommissioningNodes sends it again.
 		pod := pod
 		go func(pod *corev1.Pod) {
-			if err := rc.NodeMgmtClient.CallDecommissionNodeEndpoint(pod); err != nil {
-				rc.ReqLogger.V(1).Info(fmt.Sprintf("Error from decommission attempt. This is only an attempt and can fail. Error: %v", err))
+			// The request outlives the reconcile, an operator instance that is shutting down
+			// leaves it to the next leader
+			called := utils.RunIfLeader(func() {
+				if err := rc.NodeMgmtClient.CallDecommissionNodeEndpoint(pod); err != nil {
+					rc.ReqLogger.V(1).Info(fmt.Sprintf("Error from decommission attempt. This is only an attempt and can fail. Error: %v", err))
+				}
+			})
+			if !called {
+				rc.ReqLogger.Info("Not the leader anymore, skipping the decommission request", "pod", pod.Name)
 			}
 		}(pod)
 	}
@@ -186,6 +436,76 @@ func (rc *ReconciliationContext) callDecommission(pod *corev1.Pod) error {
 	return nil
 }
 
+// decommissionAttemptsOf returns how many decommission requests were sent to the pod, and the time
+// before which the next one must not be sent
+func decommissionAttemptsOf(pod *corev1.Pod) (int, time.Time) {
+	attempts, err := strconv.Atoi(pod.Annotations[api.DecommissionAttemptsAnnotation])
+	if err != nil || attempts <= 0 {
+		return 0, time.Time{}
+	}
+	lastAttempt, err := time.Parse(time.RFC3339, pod.Annotations[api.DecommissionLastAttemptAnnotation])
+	if err != nil {
+		return attempts, time.Time{}
+	}
+
+	backoff := decommissionRetryBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+	}
+	return attempts, lastAttempt.Add(backoff)
+}
+
+// recordDecommissionAttempt records on the pod the number of decommission requests sent to it, and
+// the id of the decommission job of the last one if it was accepted
+func (rc *ReconciliationContext) recordDecommissionAttempt(pod *corev1.Pod, attempts int, jobId string) error {
+	patch := client.MergeFrom(pod.DeepCopy())
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, api.DecommissionAttemptsAnnotation, strconv.Itoa(attempts))
+	if jobId == "" {
+		metav1.SetMetaDataAnnotation(&pod.ObjectMeta, api.DecommissionLastAttemptAnnotation, time.Now().UTC().Format(time.RFC3339))
+		delete(pod.Annotations, api.DecommissionJobAnnotation)
+	} else {
+		metav1.SetMetaDataAnnotation(&pod.ObjectMeta, api.DecommissionJobAnnotation, jobId)
+	}
+	return rc.Client.Patch(rc.Ctx, pod, patch)
+}
+
+// decommissionJobFailed returns true if the decommission job of the pod failed midway, for instance
+// because streaming its data to the other nodes failed
+func (rc *ReconciliationContext) decommissionJobFailed(pod *corev1.Pod) bool {
+	jobId := pod.Annotations[api.DecommissionJobAnnotation]
+	if jobId == "" {
+		return false
+	}
+
+	details, err := rc.NodeMgmtClient.JobDetails(pod, jobId)
+	if err != nil {
+		rc.ReqLogger.Info("Unable to get the status of the decommission job", "Pod", pod.Name, "jobId", jobId, "error", err.Error())
+		return false
+	}
+	if details.Status != "ERROR" {
+		return false
+	}
+
+	rc.ReqLogger.Info("Decommission job failed", "Pod", pod.Name, "jobId", jobId, "error", details.Error)
+	return true
+}
+
+// setDecommissionFailed sets the DecommissionFailed condition once the decommission of the pod
+// failed on every attempt
+func (rc *ReconciliationContext) setDecommissionFailed(pod *corev1.Pod, decommErr error) error {
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	message := fmt.Sprintf("pod %s: %s", pod.Name, decommErr.Error())
+	condition := api.NewDatacenterConditionWithReason(api.DatacenterDecommissionFailed, corev1.ConditionTrue, "DecommissionRequestFailed", message)
+	if rc.setCondition(condition) {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.DecommissionFailed,
+			"Decommission of pod %s failed after %d attempts: %s", pod.Name, decommissionAttempts, decommErr.Error())
+		if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Wait for decommissioning nodes to finish before continuing to reconcile
 func (rc *ReconciliationContext) CheckDecommissioningNodes(epData httphelper.CassMetadataEndpoints) result.ReconcileResult {
 	if rc.Datacenter.GetConditionStatus(api.DatacenterScalingDown) != corev1.ConditionTrue {
@@ -196,10 +516,16 @@ func (rc *ReconciliationContext) CheckDecommissioningNodes(epData httphelper.Cas
 
 	for _, pod := range rc.dcPods {
 		if pod.Labels[api.CassNodeState] == stateDecommissioning {
+			if !IsDoneDecommissioning(pod, epData, nodeStatuses, rc.ReqLogger) && rc.isDecommissionCancelled(pod) {
+				if err := rc.cancelDecommission(pod, epData); err != nil {
+					return result.Error(err)
+				}
+				return result.RequeueSoon(5)
+			}
 			if !IsDoneDecommissioning(pod, epData, nodeStatuses, rc.ReqLogger) {
-				if !HasStartedDecommissioning(pod, epData, nodeStatuses) {
-					rc.ReqLogger.V(1).Info("Decommission has not started trying again", "Pod", pod.Name)
-					err := rc.callDecommission(pod)
+				if rc.decommissionJobFailed(pod) || !HasStartedDecommissioning(pod, epData, nodeStatuses) {
+					rc.ReqLogger.V(1).Info("Decommission has not started or failed, trying again", "Pod", pod.Name)
+					err := rc.callDecommission(pod, epData)
 					if err != nil {
 						return result.Error(err)
 					}
@@ -223,11 +549,15 @@ func (rc *ReconciliationContext) CheckDecommissioningNodes(epData httphelper.Cas
 			api.DatacenterScalingDown, corev1.ConditionFalse)) || updated
 
 	if updated {
+		rc.Datacenter.Status.LastScaleOperation = metav1.Now()
+		rc.Datacenter.Status.DecommissioningNode = ""
 		err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch)
 		if err != nil {
 			rc.ReqLogger.Error(err, "error patching datacenter status for scaling down finished")
 			return result.Error(err)
 		}
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.ScaledDownDatacenter,
+			"Finished scaling down the datacenter to %d nodes", rc.Datacenter.Spec.Size)
 		// Requeue after updating to ensure we verify previous steps with the new size
 		return result.RequeueSoon(0)
 	}
@@ -235,6 +565,50 @@ func (rc *ReconciliationContext) CheckDecommissioningNodes(epData httphelper.Cas
 	return result.Continue()
 }
 
+// isDecommissionCancelled returns true if the pod being decommissioned is part of the desired
+// racks again, which happens when the size of the datacenter is increased back during a scale down.
+func (rc *ReconciliationContext) isDecommissionCancelled(pod *corev1.Pod) bool {
+	dc := rc.Datacenter
+	if dc.GetDeletionTimestamp() != nil || dc.GetConditionStatus(api.DatacenterDecommission) == corev1.ConditionTrue {
+		return false
+	}
+
+	for _, rackInfo := range rc.desiredRackInformation {
+		if pod.Labels[api.RackLabel] != rackInfo.RackName {
+			continue
+		}
+		stsName := newNamespacedNameForStatefulSet(dc, rackInfo.RackName).Name
+		for i := 0; i < rackInfo.NodeCount; i++ {
+			if pod.Name == fmt.Sprintf("%s-%d", stsName, i) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// cancelDecommission brings back a node whose decommission is no longer wanted. If the node has
+// not started leaving the ring yet, it is simply labeled as started again. Otherwise the pod is
+// deleted, as restarting Cassandra aborts the decommission and the node rejoins the ring.
+func (rc *ReconciliationContext) cancelDecommission(pod *corev1.Pod, epData httphelper.CassMetadataEndpoints) error {
+	rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.CancelledDecommission,
+		"Cancelling decommission of pod %s", pod.Name)
+
+	if HasStartedDecommissioning(pod, epData, rc.Datacenter.Status.NodeStatuses) {
+		rc.ReqLogger.Info("Restarting pod to abort its decommission", "Pod", pod.Name)
+		return rc.Client.Delete(rc.Ctx, pod)
+	}
+
+	rc.ReqLogger.Info("Marking node as started again", "Pod", pod.Name)
+	patch := client.MergeFrom(pod.DeepCopy())
+	metav1.SetMetaDataLabel(&pod.ObjectMeta, api.CassNodeState, stateStarted)
+	delete(pod.Annotations, api.DecommissionAttemptsAnnotation)
+	delete(pod.Annotations, api.DecommissionLastAttemptAnnotation)
+	delete(pod.Annotations, api.DecommissionJobAnnotation)
+	return rc.Client.Patch(rc.Ctx, pod, patch)
+}
+
 func (rc *ReconciliationContext) cleanUpAfterDecommissionedPod(pod *corev1.Pod) result.ReconcileResult {
 	rc.ReqLogger.Info("Scaling down statefulset")
 	err := rc.RemoveDecommissionedPodFromSts(pod)
@@ -256,6 +630,9 @@ func (rc *ReconciliationContext) cleanUpAfterDecommissionedPod(pod *corev1.Pod)
 		return result.Error(err)
 	}
 
+	rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.DecommissionedNode,
+		"Removed decommissioned node %s", pod.Name)
+
 	return nil
 }
 
@@ -377,6 +754,46 @@ func (rc *ReconciliationContext) RemoveDecommissionedPodFromSts(pod *corev1.Pod)
 	}
 }
 
+// pendingDecommissionCleanup returns the name of a pod beyond the replicas of its StatefulSet,
+// or of a PVC of the last decommissioned pod being deleted, which are left to remove after a
+// decommission. It is empty if the previous decommission is fully cleaned up.
+func (rc *ReconciliationContext) pendingDecommissionCleanup() (string, error) {
+	lastDecommissioned := make([]string, 0, len(rc.statefulSets))
+	for _, sts := range rc.statefulSets {
+		if sts == nil {
+			continue
+		}
+		for _, pod := range rc.dcPods {
+			if !strings.HasPrefix(pod.Name, sts.Name+"-") {
+				continue
+			}
+			ordinal, err := strconv.Atoi(strings.TrimPrefix(pod.Name, sts.Name+"-"))
+			if err == nil && int32(ordinal) >= *sts.Spec.Replicas {
+				return pod.Name, nil
+			}
+		}
+		lastDecommissioned = append(lastDecommissioned, fmt.Sprintf("%s-%d", sts.Name, *sts.Spec.Replicas))
+	}
+
+	pvcList, err := rc.listPVCs()
+	if err != nil {
+		return "", err
+	}
+	for _, pvc := range pvcList.Items {
+		if pvc.GetDeletionTimestamp() == nil {
+			continue
+		}
+		// The claims of a pod are named after the volume and the pod
+		for _, podName := range lastDecommissioned {
+			if strings.HasSuffix(pvc.Name, "-"+podName) {
+				return pvc.Name, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
 func stsLastPodSuffix(maxReplicas int32) string {
 	return fmt.Sprintf("sts-%v", maxReplicas-1)
 }
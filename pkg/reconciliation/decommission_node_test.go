@@ -5,19 +5,26 @@ package reconciliation
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/events"
 	"github.com/k8ssandra/cass-operator/pkg/httphelper"
 	"github.com/k8ssandra/cass-operator/pkg/internal/result"
 	"github.com/k8ssandra/cass-operator/pkg/mocks"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -165,3 +172,680 @@ func (s *statusMock) Patch(ctx context.Context, obj client.Object, patch client.
 	s.called = s.called + 1
 	return nil
 }
+
+func TestDecommissionNodeOnRack_SnapshotBeforeDestructive(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	podIP := "192.168.101.11"
+
+	rc.Datacenter.Spec.SnapshotBeforeDestructive = true
+
+	calledPaths := []string{}
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do", mock.Anything).
+		Return(func(req *http.Request) *http.Response {
+			calledPaths = append(calledPaths, req.URL.Path)
+			body := "OK"
+			if req.URL.Path == "/api/v0/metadata/versions/features" {
+				body = `{"cassandra_version": "4.0.1", "features": ["async_sstable_tasks"]}`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}
+		}, nil)
+
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+			Labels: map[string]string{
+				api.RackLabel:     "rack1",
+				api.CassNodeState: stateStarted,
+			},
+		},
+		Status: v1.PodStatus{
+			PodIP: podIP,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "cassandra",
+				Ready: true,
+				State: v1.ContainerState{
+					Running: &v1.ContainerStateRunning{
+						StartedAt: metav1.NewTime(time.Now().Add(-time.Minute)),
+					},
+				},
+			}},
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pod))
+	rc.dcPods = []*v1.Pod{pod}
+
+	epData := httphelper.CassMetadataEndpoints{
+		Entity: []httphelper.EndpointState{
+			{
+				RpcAddress: podIP,
+				Load:       "1000",
+			},
+			{
+				RpcAddress: "192.168.101.12",
+				IsAlive:    "true",
+				Status:     string(httphelper.StatusNormal),
+			},
+		},
+	}
+
+	err := rc.DecommissionNodeOnRack("rack1", epData, "1")
+	assert.NoError(err)
+
+	assert.Equal([]string{
+		"/api/v0/ops/keyspace",
+		"/api/v0/ops/node/snapshots",
+		"/api/v0/metadata/versions/features",
+		"/api/v1/ops/node/decommission",
+	}, calledPaths)
+	assert.True(strings.HasPrefix(rc.Datacenter.Status.LastSnapshotName, "cass-operator-decommission-"))
+	assert.Equal(stateDecommissioning, pod.Labels[api.CassNodeState])
+	assert.Equal(pod.Name, rc.Datacenter.Status.DecommissioningNode)
+}
+
+func TestDecommissionNodeOnRack_RefuseBelowReplicationFactor(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	podIP := "192.168.101.11"
+
+	rc.Datacenter.Spec.Size = 2
+
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do", mock.Anything).
+		Return(func(req *http.Request) *http.Response {
+			body := "OK"
+			switch req.URL.Path {
+			case "/api/v0/ops/keyspace":
+				body = `["system", "app"]`
+			case "/api/v0/ops/keyspace/replication":
+				if req.URL.Query().Get("keyspaceName") == "app" {
+					body = fmt.Sprintf(`{"class": "org.apache.cassandra.locator.NetworkTopologyStrategy", "%s": "3"}`, rc.Datacenter.Name)
+				} else {
+					body = `{"class": "org.apache.cassandra.locator.LocalStrategy"}`
+				}
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}
+		}, nil)
+
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+			Labels: map[string]string{
+				api.RackLabel:     "rack1",
+				api.CassNodeState: stateStarted,
+			},
+		},
+		Status: v1.PodStatus{
+			PodIP: podIP,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "cassandra",
+				Ready: true,
+				State: v1.ContainerState{
+					Running: &v1.ContainerStateRunning{
+						StartedAt: metav1.NewTime(time.Now().Add(-time.Minute)),
+					},
+				},
+			}},
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pod))
+	rc.dcPods = []*v1.Pod{pod}
+
+	epData := httphelper.CassMetadataEndpoints{
+		Entity: []httphelper.EndpointState{
+			{
+				RpcAddress: podIP,
+				Load:       "1000",
+			},
+			{
+				RpcAddress: "192.168.101.12",
+				IsAlive:    "true",
+				Status:     string(httphelper.StatusNormal),
+			},
+		},
+	}
+
+	err := rc.DecommissionNodeOnRack("rack1", epData, "1")
+	assert.Error(err)
+	assert.IsType(&scaleDownRefusedError{}, err)
+	assert.Contains(err.Error(), "keyspace app has a replication factor of 3")
+	assert.Equal(stateStarted, pod.Labels[api.CassNodeState])
+	assert.Empty(rc.Datacenter.Status.DecommissioningNode)
+	// recorded for the webhook to reject the next attempts
+	assert.Equal(int32(3), rc.Datacenter.Status.MaxReplicationFactor)
+
+	// The refusal is reported once and not requeued
+	fakeRecorder := rc.Recorder.(*record.FakeRecorder)
+	assert.Equal(result.Done(), rc.refuseScaleDown(err.(*scaleDownRefusedError)))
+	assert.Equal(v1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterScaleDownRefused))
+	assert.Equal(result.Done(), rc.refuseScaleDown(err.(*scaleDownRefusedError)))
+	assert.Len(fakeRecorder.Events, 1)
+	assert.Contains(<-fakeRecorder.Events, events.RefusedDecommission)
+
+	assert.NoError(rc.clearScaleDownRefused())
+	assert.Equal(v1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterScaleDownRefused))
+}
+
+func TestReplicationFactorForDatacenter(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(3, ReplicationFactorForDatacenter(map[string]string{
+		"class": "org.apache.cassandra.locator.NetworkTopologyStrategy", "dc1": "3", "dc2": "5"}, "dc1"))
+	assert.Equal(2, ReplicationFactorForDatacenter(map[string]string{
+		"class": "org.apache.cassandra.locator.SimpleStrategy", "replication_factor": "2"}, "dc1"))
+	assert.Equal(0, ReplicationFactorForDatacenter(map[string]string{
+		"class": "org.apache.cassandra.locator.NetworkTopologyStrategy", "dc2": "3"}, "dc1"))
+	assert.Equal(0, ReplicationFactorForDatacenter(map[string]string{
+		"class": "org.apache.cassandra.locator.LocalStrategy"}, "dc1"))
+}
+
+func TestDecommissionNodeOnRack_RefuseLastHealthyNode(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	podIP := "192.168.101.11"
+
+	// Any call to the management API would fail the test
+	mockHttpClient := &mocks.HttpClient{}
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+			Labels: map[string]string{
+				api.RackLabel:     "rack1",
+				api.CassNodeState: stateStarted,
+			},
+		},
+		Status: v1.PodStatus{
+			PodIP: podIP,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "cassandra",
+				Ready: true,
+				State: v1.ContainerState{
+					Running: &v1.ContainerStateRunning{
+						StartedAt: metav1.NewTime(time.Now().Add(-time.Minute)),
+					},
+				},
+			}},
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pod))
+	rc.dcPods = []*v1.Pod{pod}
+
+	// The node being decommissioned is the only one that is up and normal
+	epData := httphelper.CassMetadataEndpoints{
+		Entity: []httphelper.EndpointState{
+			{
+				RpcAddress: podIP,
+				IsAlive:    "true",
+				Status:     string(httphelper.StatusNormal),
+				Load:       "1000",
+			},
+			{
+				RpcAddress: "192.168.101.12",
+				IsAlive:    "false",
+				Status:     string(httphelper.StatusNormal),
+			},
+		},
+	}
+
+	err := rc.DecommissionNodeOnRack("rack1", epData, "1")
+	assert.Error(err)
+	assert.Equal(stateStarted, pod.Labels[api.CassNodeState])
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestCheckDecommissioningNodes_SizeIncreasedBack(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.SetCondition(api.DatacenterCondition{
+		Status: v1.ConditionTrue,
+		Type:   api.DatacenterScalingDown,
+	})
+
+	// The datacenter was scaled from 3 to 2 nodes and back to 3 while the last node was decommissioning
+	rc.desiredRackInformation = []*RackInformation{{RackName: "default", NodeCount: 3}}
+	stsName := newNamespacedNameForStatefulSet(rc.Datacenter, "default").Name
+
+	makePod := func(podIP string) *v1.Pod {
+		labels := rc.Datacenter.GetRackLabels("default")
+		labels[api.CassNodeState] = stateDecommissioning
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      stsName + "-2",
+				Namespace: rc.Datacenter.Namespace,
+				Labels:    labels,
+			},
+			Status: v1.PodStatus{
+				PodIP: podIP,
+			},
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, pod))
+		rc.dcPods = []*v1.Pod{pod}
+		return pod
+	}
+
+	// Decommission not started yet, the node is simply marked as started again
+	pod := makePod("192.168.101.11")
+	epData := httphelper.CassMetadataEndpoints{
+		Entity: []httphelper.EndpointState{{
+			RpcAddress: pod.Status.PodIP,
+			Status:     string(httphelper.StatusNormal),
+		}},
+	}
+
+	r := rc.CheckDecommissioningNodes(epData)
+	assert.Equal(result.RequeueSoon(5), r)
+	assert.Equal(stateStarted, pod.Labels[api.CassNodeState])
+
+	// The node is already leaving, so the pod is restarted to abort the decommission
+	assert.NoError(rc.Client.Delete(rc.Ctx, pod))
+	pod = makePod("192.168.101.12")
+	epData.Entity[0].RpcAddress = pod.Status.PodIP
+	epData.Entity[0].Status = string(httphelper.StatusLeaving)
+
+	r = rc.CheckDecommissioningNodes(epData)
+	assert.Equal(result.RequeueSoon(5), r)
+	err := rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(pod), &v1.Pod{})
+	assert.True(errors.IsNotFound(err), "pod should have been deleted")
+
+	// Once no node is decommissioning anymore, the scale down is over
+	rc.dcPods = []*v1.Pod{}
+	r = rc.CheckDecommissioningNodes(epData)
+	assert.Equal(result.RequeueSoon(0), r)
+	assert.Equal(v1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterScalingDown))
+}
+
+func TestCallDecommission_Retry(t *testing.T) {
+	defer func(backoff time.Duration) { decommissionRetryBackoff = backoff }(decommissionRetryBackoff)
+
+	tests := []struct {
+		name          string
+		failures      int
+		wantCalls     int
+		wantJob       string
+		wantCondition v1.ConditionStatus
+	}{
+		{"succeeds after two failures", 2, 3, "job-1", v1.ConditionUnknown},
+		{"gives up after every attempt failed", decommissionAttempts, decommissionAttempts, "", v1.ConditionTrue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rc, _, cleanupMockScr := setupTest()
+			defer cleanupMockScr()
+			fakeRecorder := record.NewFakeRecorder(10)
+			rc.Recorder = fakeRecorder
+
+			decommissionCalls := 0
+			mockHttpClient := &mocks.HttpClient{}
+			mockHttpClient.On("Do", mock.Anything).
+				Return(func(req *http.Request) *http.Response {
+					status := http.StatusOK
+					body := "OK"
+					switch req.URL.Path {
+					case "/api/v0/metadata/versions/features":
+						body = `{"cassandra_version": "4.0.1", "features": ["async_sstable_tasks"]}`
+					case "/api/v1/ops/node/decommission":
+						decommissionCalls++
+						if decommissionCalls <= tt.failures {
+							status = http.StatusInternalServerError
+							body = "streaming error"
+						} else {
+							body = "job-1"
+						}
+					}
+					return &http.Response{
+						StatusCode: status,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}
+				}, nil)
+
+			rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+				Client:   mockHttpClient,
+				Log:      rc.ReqLogger,
+				Protocol: "http",
+			}
+
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-1",
+					Namespace: rc.Datacenter.Namespace,
+				},
+				Status: v1.PodStatus{
+					PodIP: "192.168.101.11",
+					ContainerStatuses: []v1.ContainerStatus{{
+						Name:  "cassandra",
+						Ready: true,
+					}},
+				},
+			}
+			assert.NoError(rc.Client.Create(rc.Ctx, pod))
+
+			epData := httphelper.CassMetadataEndpoints{
+				Entity: []httphelper.EndpointState{
+					{
+						RpcAddress: "192.168.101.12",
+						IsAlive:    "true",
+						Status:     string(httphelper.StatusNormal),
+					},
+				},
+			}
+
+			// A failed request is not sent again before the backoff has passed
+			decommissionRetryBackoff = time.Hour
+			assert.NoError(rc.callDecommission(pod, epData))
+			assert.NoError(rc.callDecommission(pod, epData))
+			assert.Equal(1, decommissionCalls)
+			assert.Equal("1", pod.Annotations[api.DecommissionAttemptsAnnotation])
+
+			// The following reconciles send it again, up to decommissionAttempts times
+			decommissionRetryBackoff = 0
+			for i := 1; i < decommissionAttempts; i++ {
+				assert.NoError(rc.callDecommission(pod, epData))
+			}
+			assert.Equal(tt.wantCalls, decommissionCalls)
+			assert.Equal(tt.wantJob, pod.Annotations[api.DecommissionJobAnnotation])
+			assert.Equal(tt.wantCondition, rc.Datacenter.GetConditionStatus(api.DatacenterDecommissionFailed))
+			if tt.wantCondition == v1.ConditionTrue {
+				assert.Len(fakeRecorder.Events, 1)
+				assert.Contains(<-fakeRecorder.Events, events.DecommissionFailed)
+
+				// Once it gave up, no request is sent anymore
+				assert.NoError(rc.callDecommission(pod, epData))
+				assert.Equal(tt.wantCalls, decommissionCalls)
+				assert.Len(fakeRecorder.Events, 0)
+			} else {
+				assert.Len(fakeRecorder.Events, 0)
+			}
+		})
+	}
+}
+
+func TestDecommissionJobFailed(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do", mock.Anything).
+		Return(func(req *http.Request) *http.Response {
+			body := fmt.Sprintf(`{"id":"%s","type":"decommission","status":"ERROR","error":"streaming failed"}`, req.URL.Query().Get("job_id"))
+			if req.URL.Query().Get("job_id") == "job-ok" {
+				body = `{"id":"job-ok","type":"decommission","status":"WAITING"}`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}
+		}, nil)
+
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+		},
+		Status: v1.PodStatus{
+			PodIP: "192.168.101.11",
+		},
+	}
+	assert.False(rc.decommissionJobFailed(pod))
+
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, api.DecommissionJobAnnotation, "job-ok")
+	assert.False(rc.decommissionJobFailed(pod))
+
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, api.DecommissionJobAnnotation, "job-failed")
+	assert.True(rc.decommissionJobFailed(pod))
+}
+
+func TestCheckDecommissioningNodes_ScaledDownEvent(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	rc.Datacenter.SetCondition(*api.NewDatacenterCondition(api.DatacenterScalingDown, v1.ConditionTrue))
+	assert.NoError(rc.Client.Status().Update(rc.Ctx, rc.Datacenter))
+	rc.dcPods = nil
+
+	assert.Equal(result.RequeueSoon(0), rc.CheckDecommissioningNodes(httphelper.CassMetadataEndpoints{}))
+	assert.Equal(v1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterScalingDown))
+	assert.Len(fakeRecorder.Events, 1)
+	assert.Contains(<-fakeRecorder.Events, events.ScaledDownDatacenter)
+
+	// Already finished, no more events
+	assert.Equal(result.Continue(), rc.CheckDecommissioningNodes(httphelper.CassMetadataEndpoints{}))
+	assert.Len(fakeRecorder.Events, 0)
+}
+
+func TestCheckDecommissioningNodes_RefuseLastHealthyNode(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	// Any call to the management API would fail the test
+	mockHttpClient := &mocks.HttpClient{}
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	rc.Datacenter.SetCondition(*api.NewDatacenterCondition(api.DatacenterScalingDown, v1.ConditionTrue))
+	assert.NoError(rc.Client.Status().Update(rc.Ctx, rc.Datacenter))
+
+	podIP := "192.168.101.11"
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+			Labels: map[string]string{
+				api.RackLabel:     "rack1",
+				api.CassNodeState: stateDecommissioning,
+			},
+		},
+		Status: v1.PodStatus{
+			PodIP: podIP,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "cassandra",
+				Ready: true,
+			}},
+		},
+	}
+	rc.dcPods = []*v1.Pod{pod}
+
+	// The decommission has not started, and the other node went down in the meantime
+	epData := httphelper.CassMetadataEndpoints{
+		Entity: []httphelper.EndpointState{
+			{
+				RpcAddress: podIP,
+				IsAlive:    "true",
+				Status:     string(httphelper.StatusNormal),
+			},
+			{
+				RpcAddress: "192.168.101.12",
+				IsAlive:    "false",
+				Status:     string(httphelper.StatusNormal),
+			},
+		},
+	}
+
+	res := rc.CheckDecommissioningNodes(epData)
+	_, err := res.Output()
+	assert.Error(err)
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestDeletePodPvcs_AdditionalVolumes(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-0",
+			Namespace: rc.Datacenter.Namespace,
+		},
+	}
+	for _, volumeName := range []string{PvcName, "commitlog"} {
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      volumeName + "-" + pod.Name,
+				Namespace: rc.Datacenter.Namespace,
+			},
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, pvc))
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name: volumeName,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+			},
+		})
+	}
+
+	assert.NoError(rc.DeletePodPvcs(pod))
+
+	pvcs := &v1.PersistentVolumeClaimList{}
+	assert.NoError(rc.Client.List(rc.Ctx, pvcs, client.InNamespace(rc.Datacenter.Namespace)))
+	assert.Empty(pvcs.Items, "the commitlog PVC is deleted along with the data PVC")
+}
+
+func TestDecommissionNodes_SerializedScaleDown(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	mockHttpClient := &mocks.HttpClient{}
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	// The datacenter goes from 4 nodes down to 1
+	rc.Datacenter.Spec.Size = 1
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	sts, err := newStatefulSetForCassandraDatacenter(nil, "default", rc.Datacenter, 4, false)
+	assert.NoError(err)
+	assert.NoError(rc.Client.Create(rc.Ctx, sts))
+	rc.statefulSets = []*appsv1.StatefulSet{sts}
+
+	rc.dcPods = []*v1.Pod{}
+	pvcs := []*v1.PersistentVolumeClaim{}
+	for i := 0; i < 4; i++ {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", sts.Name, i),
+				Namespace: rc.Datacenter.Namespace,
+				Labels:    rc.Datacenter.GetRackLabels("default"),
+			},
+		}
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "server-data-" + pod.Name,
+				Namespace:  rc.Datacenter.Namespace,
+				Labels:     rc.Datacenter.GetDatacenterLabels(),
+				Finalizers: []string{"kubernetes.io/pvc-protection"},
+			},
+		}
+		pod.Spec.Volumes = []v1.Volume{{
+			Name: "server-data",
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+			},
+		}}
+		assert.NoError(rc.Client.Create(rc.Ctx, pod))
+		assert.NoError(rc.Client.Create(rc.Ctx, pvc))
+		rc.dcPods = append(rc.dcPods, pod)
+		pvcs = append(pvcs, pvc)
+	}
+
+	pending, err := rc.pendingDecommissionCleanup()
+	assert.NoError(err)
+	assert.Equal("", pending)
+
+	// The highest ordinal left the ring, its StatefulSet is scaled down and its PVC deleted
+	assert.Nil(rc.cleanUpAfterDecommissionedPod(rc.dcPods[3]))
+	assert.Equal(int32(3), *sts.Spec.Replicas)
+	recorder := rc.Recorder.(*record.FakeRecorder)
+	assert.Contains(<-recorder.Events, events.DeletedPvc)
+	assert.Contains(<-recorder.Events, events.DecommissionedNode)
+
+	epData := httphelper.CassMetadataEndpoints{}
+
+	// The pod is still being terminated, the next node must wait
+	assert.Equal(result.RequeueSoon(5), rc.DecommissionNodes(epData))
+	pending, err = rc.pendingDecommissionCleanup()
+	assert.NoError(err)
+	assert.Equal(rc.dcPods[3].Name, pending)
+
+	// The pod is gone but its PVC is not reclaimed yet
+	assert.NoError(rc.Client.Delete(rc.Ctx, rc.dcPods[3]))
+	rc.dcPods = rc.dcPods[:3]
+	assert.Equal(result.RequeueSoon(5), rc.DecommissionNodes(epData))
+	pending, err = rc.pendingDecommissionCleanup()
+	assert.NoError(err)
+	assert.Equal(pvcs[3].Name, pending)
+
+	// Once the PVC is reclaimed, the decommission moves on to the next highest ordinal
+	pvc := &v1.PersistentVolumeClaim{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: pvcs[3].Name, Namespace: pvcs[3].Namespace}, pvc))
+	pvc.Finalizers = nil
+	assert.NoError(rc.Client.Update(rc.Ctx, pvc))
+
+	pending, err = rc.pendingDecommissionCleanup()
+	assert.NoError(err)
+	assert.Equal("", pending)
+
+	// A PVC stuck in deletion that doesn't belong to the last decommissioned pod doesn't block
+	assert.NoError(rc.Client.Delete(rc.Ctx, pvcs[1]))
+	pending, err = rc.pendingDecommissionCleanup()
+	assert.NoError(err)
+	assert.Equal("", pending)
+
+	res := rc.DecommissionNodes(epData)
+	assert.True(res.Completed())
+	_, err = res.Output()
+	assert.EqualError(err, "management API is not up on node that we are trying to decommission")
+	mockHttpClient.AssertNotCalled(t, "Do", mock.Anything)
+}
@@ -0,0 +1,136 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+)
+
+// DryRunClient reads through the wrapped client but only logs the writes, so that a reconcile
+// computes the desired resources of a datacenter without changing anything in the cluster
+type DryRunClient struct {
+	client.Client
+	Log logr.Logger
+}
+
+// NewDryRunClient wraps the client so that its Create, Update, Patch and Delete calls, including
+// the status ones, are logged and skipped
+func NewDryRunClient(cli client.Client, logger logr.Logger) *DryRunClient {
+	return &DryRunClient{Client: cli, Log: logger.WithName("dry-run")}
+}
+
+func (c *DryRunClient) logWrite(verb string, obj client.Object, keysAndValues ...interface{}) {
+	keysAndValues = append([]interface{}{
+		"verb", verb,
+		"kind", reflect.Indirect(reflect.ValueOf(obj)).Type().Name(),
+		"name", obj.GetName(),
+		"namespace", obj.GetNamespace(),
+	}, keysAndValues...)
+	c.Log.Info("Skipping write in dry run mode", keysAndValues...)
+}
+
+func (c *DryRunClient) logPatch(verb string, obj client.Object, patch client.Patch) {
+	data, err := patch.Data(obj)
+	if err != nil {
+		c.Log.Error(err, "error computing the skipped patch", "name", obj.GetName())
+	}
+	c.logWrite(verb, obj, "patch", string(data))
+}
+
+func (c *DryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.logWrite("create", obj, "object", obj)
+	return nil
+}
+
+func (c *DryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.logWrite("update", obj, "object", obj)
+	return nil
+}
+
+func (c *DryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.logPatch("patch", obj, patch)
+	return nil
+}
+
+func (c *DryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.logWrite("delete", obj)
+	return nil
+}
+
+func (c *DryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	c.logWrite("deleteAllOf", obj)
+	return nil
+}
+
+func (c *DryRunClient) Status() client.StatusWriter {
+	return &dryRunStatusWriter{client: c}
+}
+
+type dryRunStatusWriter struct {
+	client *DryRunClient
+}
+
+func (w *dryRunStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	w.client.logWrite("updateStatus", obj, "object", obj)
+	return nil
+}
+
+func (w *dryRunStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	w.client.logPatch("patchStatus", obj, patch)
+	return nil
+}
+
+// errDryRunRequest is returned for the management API requests skipped in dry run mode
+var errDryRunRequest = errors.New("management API request skipped in dry run mode")
+
+// dryRunHttpClient sends the GET requests of the management API client, and only logs the other
+// requests, which change the state of the nodes
+type dryRunHttpClient struct {
+	httphelper.HttpClient
+	log logr.Logger
+}
+
+func (c *dryRunHttpClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		return c.HttpClient.Do(req)
+	}
+	c.log.Info("Skipping management API request in dry run mode", "method", req.Method, "url", req.URL.String())
+	return nil, errDryRunRequest
+}
+
+// dryRunRecorder only logs the events
+type dryRunRecorder struct {
+	log logr.Logger
+}
+
+func (r *dryRunRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.log.Info("Skipping event in dry run mode", "reason", reason, "eventType", eventtype, "message", message)
+}
+
+func (r *dryRunRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *dryRunRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// UseDryRun keeps the reconcile from changing the nodes or recording events, on top of the
+// DryRunClient the context was created with: only the GET requests of the management API client
+// are sent, and the events are logged
+func (rc *ReconciliationContext) UseDryRun() {
+	logger := rc.ReqLogger.WithName("dry-run")
+	rc.NodeMgmtClient.Client = &dryRunHttpClient{HttpClient: rc.NodeMgmtClient.Client, log: logger}
+	rc.Recorder = &dryRunRecorder{log: logger}
+}
@@ -0,0 +1,117 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/k8ssandra/cass-operator/pkg/mocks"
+)
+
+func TestDryRunClient_SkipsWrites(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	mockClient := &mocks.Client{}
+	k8sMockClientGet(mockClient, nil).Once()
+	cli := NewDryRunClient(mockClient, rc.ReqLogger)
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"}}
+	patch := client.MergeFrom(service.DeepCopy())
+	service.Labels = map[string]string{"key": "value"}
+
+	assert.NoError(cli.Get(rc.Ctx, client.ObjectKeyFromObject(service), service))
+	assert.NoError(cli.Create(rc.Ctx, service))
+	assert.NoError(cli.Update(rc.Ctx, service))
+	assert.NoError(cli.Patch(rc.Ctx, service, patch))
+	assert.NoError(cli.Delete(rc.Ctx, service))
+	assert.NoError(cli.DeleteAllOf(rc.Ctx, service))
+	assert.NoError(cli.Status().Update(rc.Ctx, service))
+	assert.NoError(cli.Status().Patch(rc.Ctx, service, patch))
+
+	// Only the read reached the wrapped client
+	mockClient.AssertExpectations(t)
+	for _, method := range []string{"Create", "Update", "Patch", "Delete", "DeleteAllOf", "Status"} {
+		mockClient.AssertNotCalled(t, method, mock.Anything, mock.Anything)
+	}
+}
+
+func TestCalculateReconciliationActions_DryRun(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	fakeClient := rc.Client
+	rc.Client = NewDryRunClient(fakeClient, rc.ReqLogger)
+
+	for i := 0; i < 3; i++ {
+		_, err := rc.CalculateReconciliationActions()
+		assert.NoError(err)
+	}
+
+	// The services and StatefulSets were computed, but nothing was written
+	services := &corev1.ServiceList{}
+	assert.NoError(fakeClient.List(rc.Ctx, services, client.InNamespace(rc.Datacenter.Namespace)))
+	assert.Empty(services.Items)
+
+	statefulSets := &appsv1.StatefulSetList{}
+	assert.NoError(fakeClient.List(rc.Ctx, statefulSets, client.InNamespace(rc.Datacenter.Namespace)))
+	assert.Empty(statefulSets.Items)
+
+	dc := rc.Datacenter.DeepCopy()
+	assert.NoError(fakeClient.Get(rc.Ctx, client.ObjectKeyFromObject(rc.Datacenter), dc))
+	assert.Empty(dc.Finalizers)
+}
+
+func TestUseDryRun_SkipsManagementApiWrites(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool { return req.Method == http.MethodGet })).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"entity": []}`)),
+		}, nil).
+		Once()
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+	rc.UseDryRun()
+
+	pod := makeMockReadyStartedPod()
+	pod.Status.PodIP = "10.0.0.1"
+
+	// The reads reach the nodes
+	_, err := rc.NodeMgmtClient.CallMetadataEndpointsEndpoint(pod)
+	assert.NoError(err)
+
+	// The requests changing the nodes don't
+	assert.Error(rc.NodeMgmtClient.CallDecommissionNodeEndpoint(pod))
+	mockHttpClient.AssertExpectations(t)
+	mockHttpClient.AssertNumberOfCalls(t, "Do", 1)
+
+	// Nor do the events
+	rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, "Test", "event %d", 1)
+	assert.Len(fakeRecorder.Events, 0)
+}
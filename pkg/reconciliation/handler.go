@@ -6,6 +6,7 @@ package reconciliation
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/k8ssandra/cass-operator/pkg/oplabels"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -13,11 +14,13 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/events"
 	"github.com/k8ssandra/cass-operator/pkg/httphelper"
 	"github.com/k8ssandra/cass-operator/pkg/internal/result"
 	"github.com/k8ssandra/cass-operator/pkg/psp"
@@ -165,11 +168,47 @@ func (rc *ReconciliationContext) CalculateReconciliationActions() (reconcile.Res
 		if err := rc.PSPHealthUpdater.Update(*rc.Datacenter); err != nil {
 			return reconcile.Result{}, err
 		}
+
+		if err := rc.updateLastReconciledTime(); err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
 	return result, err
 }
 
+// lastReconciledTimeInterval limits how often the status is patched only to record the last reconcile
+const lastReconciledTimeInterval = time.Minute
+
+// updateLastReconciledTime records in the status that a reconcile completed without error. The
+// timestamp is refreshed at most once per lastReconciledTimeInterval to avoid a status write on
+// every reconcile.
+func (rc *ReconciliationContext) updateLastReconciledTime() error {
+	last := rc.Datacenter.Status.LastReconciledTime
+	if !last.IsZero() && time.Since(last.Time) < lastReconciledTimeInterval {
+		return nil
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.Datacenter.Status.LastReconciledTime = metav1.Now()
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for last reconciled time")
+		return err
+	}
+	return nil
+}
+
+// RecordReconcileError adds the error of a failed reconcile to the error history in the status
+func (rc *ReconciliationContext) RecordReconcileError(reconcileErr error) error {
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.Datacenter.Status.AddReconcileError(reconcileErr.Error(), metav1.Now())
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for reconcile errors")
+		return err
+	}
+	return nil
+}
+
 // This file contains various definitions and plumbing setup used for reconciliation.
 
 // For information on log usage, see:
@@ -196,6 +235,13 @@ func (rc *ReconciliationContext) addFinalizer() error {
 func (rc *ReconciliationContext) IsValid(dc *api.CassandraDatacenter) error {
 	var errs []error = []error{}
 
+	// Unsupported access modes only warn, they don't make the datacenter invalid
+	if claim := dc.Spec.StorageConfig.CassandraDataVolumeClaimSpec; claim != nil {
+		if err := rc.checkAccessModes(claim); err != nil {
+			return err
+		}
+	}
+
 	// Basic validation up here
 
 	// validate the required superuser
@@ -234,3 +280,46 @@ func (rc *ReconciliationContext) IsValid(dc *api.CassandraDatacenter) error {
 
 	return nil
 }
+
+// NormalizeAccessModes removes duplicated access modes, and returns separately the ones that
+// are not supported for Cassandra data volumes, which are only ever mounted by a single pod.
+func NormalizeAccessModes(modes []corev1.PersistentVolumeAccessMode) (normalized, unsupported []corev1.PersistentVolumeAccessMode) {
+	seen := map[corev1.PersistentVolumeAccessMode]bool{}
+	for _, mode := range modes {
+		if seen[mode] {
+			continue
+		}
+		seen[mode] = true
+		normalized = append(normalized, mode)
+		if mode != corev1.ReadWriteOnce && mode != corev1.ReadWriteOncePod {
+			unsupported = append(unsupported, mode)
+		}
+	}
+	return normalized, unsupported
+}
+
+// checkAccessModes sets the UnsupportedAccessModes condition if the data volumes use access modes
+// that Cassandra doesn't support, and warns about them once when the condition is set
+func (rc *ReconciliationContext) checkAccessModes(claim *corev1.PersistentVolumeClaimSpec) error {
+	_, unsupported := NormalizeAccessModes(claim.AccessModes)
+	if len(unsupported) == 0 && rc.Datacenter.GetConditionStatus(api.DatacenterUnsupportedAccessModes) != corev1.ConditionTrue {
+		return nil
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	condition := api.NewDatacenterCondition(api.DatacenterUnsupportedAccessModes, corev1.ConditionFalse)
+	if len(unsupported) > 0 {
+		condition = api.NewDatacenterConditionWithReason(api.DatacenterUnsupportedAccessModes, corev1.ConditionTrue,
+			"UnsupportedAccessModes", fmt.Sprintf("%v", unsupported))
+	}
+	if !rc.setCondition(condition) {
+		return nil
+	}
+
+	if len(unsupported) > 0 {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.UnsupportedAccessMode,
+			"Unsupported access modes %v in storageConfig.cassandraDataVolumeClaimSpec, Cassandra data volumes should be ReadWriteOnce",
+			unsupported)
+	}
+	return rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch)
+}
@@ -6,19 +6,27 @@ package reconciliation
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/cass-operator/pkg/dynamicwatch"
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/k8ssandra/cass-operator/pkg/internal/result"
 	"github.com/k8ssandra/cass-operator/pkg/mocks"
 )
 
@@ -178,6 +186,55 @@ func TestProcessDeletion_NoFinalizer(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+// TestProcessDeletion_EphemeralStorage verifies that an ephemeral datacenter is torn down
+// without decommissioning its nodes, even if decommission on delete was requested
+func TestProcessDeletion_EphemeralStorage(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	// Any call to the management API would fail the test
+	mockHttpClient := &mocks.HttpClient{}
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{Client: mockHttpClient, Log: rc.ReqLogger, Protocol: "http"}
+
+	rc.Datacenter.Spec.StorageConfig.UseEphemeralStorage = true
+	rc.Datacenter.Annotations = map[string]string{api.DecommissionOnDeleteAnnotation: "true"}
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+	rc.Datacenter.SetCondition(api.DatacenterCondition{
+		Status: v1.ConditionTrue,
+		Type:   api.DatacenterScalingDown,
+	})
+	assert.NoError(rc.Client.Status().Update(rc.Ctx, rc.Datacenter))
+
+	sts, err := newStatefulSetForCassandraDatacenter(nil, "default", rc.Datacenter, 1, false)
+	assert.NoError(err)
+	assert.NoError(rc.Client.Create(rc.Ctx, sts))
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    rc.Datacenter.GetRackLabels("default"),
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pod))
+
+	emptySecretWatcher(rc)
+
+	rc.Datacenter.SetFinalizers([]string{api.Finalizer})
+	now := metav1.Now()
+	rc.Datacenter.SetDeletionTimestamp(&now)
+
+	r := rc.ProcessDeletion()
+	assert.Equal(result.Done(), r)
+	assert.Empty(rc.Datacenter.GetFinalizers())
+
+	err = rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(sts), &appsv1.StatefulSet{})
+	assert.True(errors.IsNotFound(err), "statefulset should have been deleted")
+
+	mockHttpClient.AssertExpectations(t)
+}
+
 func TestAddFinalizer(t *testing.T) {
 	assert := assert.New(t)
 	rc, _, cleanupMockScr := setupTest()
@@ -486,3 +543,84 @@ func TestAddFinalizer(t *testing.T) {
 // 		t.Error("Reconcile did not return an empty result.")
 // 	}
 // }
+
+func TestCheckAccessModes_ReadWriteMany(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	normalized, unsupported := NormalizeAccessModes([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce, v1.ReadWriteMany, v1.ReadWriteOnce})
+	assert.Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteOnce, v1.ReadWriteMany}, normalized)
+	assert.Equal([]v1.PersistentVolumeAccessMode{v1.ReadWriteMany}, unsupported)
+
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	_ = rc.IsValid(rc.Datacenter)
+	assert.Equal(0, len(fakeRecorder.Events))
+
+	rc.Datacenter.Spec.StorageConfig.CassandraDataVolumeClaimSpec.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteMany}
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	// Warned once, not on every reconcile
+	_ = rc.IsValid(rc.Datacenter)
+	_ = rc.IsValid(rc.Datacenter)
+	assert.Equal(1, len(fakeRecorder.Events))
+	assert.Contains(<-fakeRecorder.Events, "ReadWriteMany")
+	assert.Equal(v1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterUnsupportedAccessModes))
+}
+
+func TestCalculateReconciliationActions_LastReconciledTime(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	_, err := rc.CalculateReconciliationActions()
+	assert.NoError(t, err)
+	assert.False(t, rc.Datacenter.Status.LastReconciledTime.IsZero(), "a successful reconcile should record its time")
+
+	// A recent timestamp is not refreshed on every reconcile
+	recent := metav1.NewTime(time.Now().Add(-10 * time.Second).Truncate(time.Second))
+	rc.Datacenter.Status.LastReconciledTime = recent
+	assert.NoError(t, rc.updateLastReconciledTime())
+	assert.Equal(t, recent, rc.Datacenter.Status.LastReconciledTime)
+
+	// A failed reconcile leaves the timestamp alone
+	lastReconciled := metav1.NewTime(rc.Datacenter.Status.LastReconciledTime.Add(-time.Hour))
+	rc.Datacenter.Status.LastReconciledTime = lastReconciled
+	rc.Datacenter.SetFinalizers(nil)
+
+	mockClient := &mocks.Client{}
+	rc.Client = mockClient
+	k8sMockClientUpdate(mockClient, fmt.Errorf("failed to update CassandraDatacenter with removed finalizers"))
+
+	_, err = rc.CalculateReconciliationActions()
+	assert.Error(t, err)
+	assert.Equal(t, lastReconciled, rc.Datacenter.Status.LastReconciledTime)
+}
+
+func TestRecordReconcileError(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	assert.NoError(t, rc.RecordReconcileError(fmt.Errorf("connection refused")))
+	assert.NoError(t, rc.RecordReconcileError(fmt.Errorf("conflict")))
+
+	dc := &api.CassandraDatacenter{}
+	assert.NoError(t, rc.Client.Get(rc.Ctx, types.NamespacedName{Name: rc.Datacenter.Name, Namespace: rc.Datacenter.Namespace}, dc))
+	assert.Len(t, dc.Status.ReconcileErrors, 2)
+	assert.Equal(t, "connection refused", dc.Status.ReconcileErrors[0].Message)
+	assert.Equal(t, "conflict", dc.Status.ReconcileErrors[1].Message)
+	assert.False(t, dc.Status.ReconcileErrors[1].Time.IsZero())
+}
+
+func TestIsValid_UnevenRacks(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	// The rack balance is only validated by the webhook, an existing datacenter keeps reconciling
+	rc.Datacenter.Spec.Size = 5
+	rc.Datacenter.Spec.Racks = []api.Rack{{Name: "rack1"}, {Name: "rack2"}, {Name: "rack3"}}
+	rc.Datacenter.Spec.StrictRackBalance = true
+	assert.NoError(rc.IsValid(rc.Datacenter))
+}
@@ -0,0 +1,50 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+)
+
+const (
+	nodeStateDesired         = "desired"
+	nodeStateReady           = "ready"
+	nodeStateDecommissioning = "decommissioning"
+)
+
+var datacenterNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "cass_operator_datacenter_nodes",
+	Help: "Number of Cassandra nodes of the CassandraDatacenters, by state: desired, ready or decommissioning",
+}, []string{"datacenter", "namespace", "state"})
+
+func init() {
+	metrics.Registry.MustRegister(datacenterNodes)
+}
+
+// updateNodeMetrics sets the node count gauges of the datacenter from its size and its pods
+func (rc *ReconciliationContext) updateNodeMetrics() {
+	dc := rc.Datacenter
+	ready, _ := rc.countReadyAndStarted()
+	decommissioning := 0
+	for _, pod := range rc.dcPods {
+		if pod.Labels[api.CassNodeState] == stateDecommissioning {
+			decommissioning++
+		}
+	}
+
+	datacenterNodes.WithLabelValues(dc.Name, dc.Namespace, nodeStateDesired).Set(float64(dc.Spec.Size))
+	datacenterNodes.WithLabelValues(dc.Name, dc.Namespace, nodeStateReady).Set(float64(ready))
+	datacenterNodes.WithLabelValues(dc.Name, dc.Namespace, nodeStateDecommissioning).Set(float64(decommissioning))
+}
+
+// DeleteDatacenterMetrics removes the series of a deleted datacenter, so that it doesn't keep
+// being reported with its last node counts
+func DeleteDatacenterMetrics(namespace, name string) {
+	for _, state := range []string{nodeStateDesired, nodeStateReady, nodeStateDecommissioning} {
+		datacenterNodes.DeleteLabelValues(name, namespace, state)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+)
+
+func TestUpdateNodeMetrics(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	dc := rc.Datacenter
+	dc.Spec.Size = 3
+
+	decommissioning := makeMockReadyStartedPod()
+	decommissioning.Labels[api.CassNodeState] = stateDecommissioning
+	notReady := makeMockReadyStartedPod()
+	notReady.Status.ContainerStatuses[0].Ready = false
+	rc.dcPods = []*corev1.Pod{makeMockReadyStartedPod(), decommissioning, notReady}
+
+	rc.updateNodeMetrics()
+	assert.Equal(3.0, testutil.ToFloat64(datacenterNodes.WithLabelValues(dc.Name, dc.Namespace, nodeStateDesired)))
+	assert.Equal(2.0, testutil.ToFloat64(datacenterNodes.WithLabelValues(dc.Name, dc.Namespace, nodeStateReady)))
+	assert.Equal(1.0, testutil.ToFloat64(datacenterNodes.WithLabelValues(dc.Name, dc.Namespace, nodeStateDecommissioning)))
+
+	// The series of a deleted datacenter are cleared
+	series := testutil.CollectAndCount(datacenterNodes)
+	DeleteDatacenterMetrics(dc.Namespace, dc.Name)
+	assert.Equal(series-3, testutil.CollectAndCount(datacenterNodes))
+}
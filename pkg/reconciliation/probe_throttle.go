@@ -0,0 +1,54 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// probeThrottle limits how often the nodes of a datacenter are probed through their management API.
+// The reconciliation context is created again on every reconcile, so the time of the last probe of
+// each datacenter is kept here.
+type probeThrottle struct {
+	interval  time.Duration
+	lock      sync.Mutex
+	lastProbe map[types.NamespacedName]time.Time
+}
+
+func newProbeThrottle(interval time.Duration) *probeThrottle {
+	return &probeThrottle{
+		interval:  interval,
+		lastProbe: make(map[types.NamespacedName]time.Time),
+	}
+}
+
+// allow returns true, and records the probe, if the datacenter was not probed in the last interval
+func (t *probeThrottle) allow(dc types.NamespacedName, now time.Time) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if last, found := t.lastProbe[dc]; found && now.Sub(last) < t.interval {
+		return false
+	}
+	t.lastProbe[dc] = now
+	return true
+}
+
+// reset forgets the last probe of the datacenter
+func (t *probeThrottle) reset(dc types.NamespacedName) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.lastProbe, dc)
+}
+
+// resetProbeThrottles forgets the probes, and the wait for the seed endpoints, of a deleted datacenter
+func resetProbeThrottles(dc types.NamespacedName) {
+	clockSkewProbes.reset(dc)
+	gossipProbes.reset(dc)
+	seedEndpointsWaits.reset(dc)
+}
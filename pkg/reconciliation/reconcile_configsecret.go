@@ -81,6 +81,29 @@ func (rc *ReconciliationContext) CheckConfigSecret() result.ReconcileResult {
 	return result.Continue()
 }
 
+// CheckConfigHash updates the hash of the configuration in the status of the datacenter. It
+// matches the config hash annotation of the pods running the latest configuration.
+func (rc *ReconciliationContext) CheckConfigHash() result.ReconcileResult {
+	configHash, err := getConfigHash(rc.Datacenter)
+	if err != nil {
+		rc.ReqLogger.Error(err, "error computing the config hash")
+		return result.Error(err)
+	}
+
+	if rc.Datacenter.Status.ConfigHash == configHash {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.Datacenter.Status.ConfigHash = configHash
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for config hash")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
 // checkDatacenterNameAnnotation Checks to see if the secret has the datacenter annotation.
 // If the secret does not have the annotation, it is added, and the secret is patched. The
 // secret should be the one specifiied by ConfigSecret.
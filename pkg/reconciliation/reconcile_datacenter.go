@@ -4,8 +4,11 @@
 package reconciliation
 
 import (
+	"context"
+
 	"github.com/k8ssandra/cass-operator/pkg/events"
 	"github.com/k8ssandra/cass-operator/pkg/internal/result"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/oplabels"
 	"github.com/k8ssandra/cass-operator/pkg/utils"
 )
 
@@ -39,14 +43,18 @@ func (rc *ReconciliationContext) ProcessDeletion() result.ReconcileResult {
 		rc.Datacenter.Spec.Size = 0
 	}
 
-	if rc.Datacenter.Status.GetConditionStatus(api.DatacenterScalingDown) == corev1.ConditionTrue {
+	// The data of an ephemeral datacenter is disposable, so there's no need to decommission
+	// the nodes gracefully before tearing everything down
+	ephemeral := rc.Datacenter.Spec.StorageConfig.UseEphemeralStorage
+
+	if !ephemeral && rc.Datacenter.Status.GetConditionStatus(api.DatacenterScalingDown) == corev1.ConditionTrue {
 		// ScalingDown is still happening
 		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.DecommissionDatacenter, "Datacenter is decommissioning")
 		rc.ReqLogger.V(1).Info("Waiting for the decommission to complete first, before deleting")
 		return result.Continue()
 	}
 
-	if _, found := rc.Datacenter.Annotations[api.DecommissionOnDeleteAnnotation]; found {
+	if _, found := rc.Datacenter.Annotations[api.DecommissionOnDeleteAnnotation]; found && !ephemeral {
 		podList, err := rc.listPods(rc.Datacenter.GetDatacenterLabels())
 		if err != nil {
 			rc.ReqLogger.Error(err, "Failed to list pods, unable to proceed with deletion")
@@ -89,11 +97,22 @@ func (rc *ReconciliationContext) ProcessDeletion() result.ReconcileResult {
 		rc.ReqLogger.Error(err, "Failed to remove dynamic secret watches for CassandraDatacenter")
 	}
 
+	if ephemeral {
+		if err := rc.deleteStatefulSets(); err != nil {
+			rc.ReqLogger.Error(err, "Failed to delete StatefulSets for CassandraDatacenter")
+			return result.Error(err)
+		}
+	}
+
 	if err := rc.deletePVCs(); err != nil {
 		rc.ReqLogger.Error(err, "Failed to delete PVCs for CassandraDatacenter")
 		return result.Error(err)
 	}
 
+	resetProbeThrottles(types.NamespacedName{
+		Name:      rc.Datacenter.GetName(),
+		Namespace: rc.Datacenter.GetNamespace()})
+
 	if utils.IsPSPEnabled() {
 		rc.RemoveDcFromNodeToDcMap(types.NamespacedName{
 			Name:      rc.Datacenter.GetName(),
@@ -147,6 +166,94 @@ func (rc *ReconciliationContext) deletePVCs() error {
 	return nil
 }
 
+// deleteStatefulSets deletes the StatefulSets of all the racks, along with their pods
+func (rc *ReconciliationContext) deleteStatefulSets() error {
+	rc.ReqLogger.Info("reconciler::deleteStatefulSets")
+
+	statefulSets, err := ListManagedStatefulSets(rc.Ctx, rc.Client, rc.Datacenter)
+	if err != nil {
+		return err
+	}
+
+	for _, statefulSet := range statefulSets {
+		if err := rc.Client.Delete(rc.Ctx, statefulSet); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		rc.ReqLogger.Info("Deleted StatefulSet", "statefulSetName", statefulSet.Name)
+	}
+
+	return nil
+}
+
+// ListManagedStatefulSets returns the StatefulSets of the datacenter that are managed by the operator
+func ListManagedStatefulSets(ctx context.Context, c client.Client, dc *api.CassandraDatacenter) ([]*appsv1.StatefulSet, error) {
+	selector := dc.GetDatacenterLabels()
+	selector[oplabels.ManagedByLabel] = oplabels.ManagedByLabelValue
+
+	statefulSetList := &appsv1.StatefulSetList{}
+	if err := c.List(ctx, statefulSetList, client.InNamespace(dc.Namespace), client.MatchingLabels(selector)); err != nil {
+		return nil, err
+	}
+
+	statefulSets := make([]*appsv1.StatefulSet, 0, len(statefulSetList.Items))
+	for i := range statefulSetList.Items {
+		statefulSets = append(statefulSets, &statefulSetList.Items[i])
+	}
+
+	return statefulSets, nil
+}
+
+// ManagedObjectCounts counts the statefulsets, services and PVCs the operator manages for the datacenter
+func (rc *ReconciliationContext) ManagedObjectCounts() (api.ManagedObjectCounts, error) {
+	counts := api.ManagedObjectCounts{}
+
+	statefulSets, err := ListManagedStatefulSets(rc.Ctx, rc.Client, rc.Datacenter)
+	if err != nil {
+		return counts, err
+	}
+	counts.StatefulSets = len(statefulSets)
+
+	selector := rc.Datacenter.GetDatacenterLabels()
+	selector[oplabels.ManagedByLabel] = oplabels.ManagedByLabelValue
+	serviceList := &corev1.ServiceList{}
+	if err := rc.Client.List(rc.Ctx, serviceList, client.InNamespace(rc.Datacenter.Namespace), client.MatchingLabels(selector)); err != nil {
+		return counts, err
+	}
+	counts.Services = len(serviceList.Items)
+
+	pvcList, err := rc.listPVCs()
+	if err != nil {
+		return counts, err
+	}
+	counts.PersistentVolumeClaims = len(pvcList.Items)
+
+	return counts, nil
+}
+
+// CheckManagedObjectCounts updates the number of managed objects in the status, which helps
+// noticing unexpected extra or missing objects
+func (rc *ReconciliationContext) CheckManagedObjectCounts() result.ReconcileResult {
+	counts, err := rc.ManagedObjectCounts()
+	if err != nil {
+		return result.Error(err)
+	}
+	if rc.Datacenter.Status.ManagedObjects == counts {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.Datacenter.Status.ManagedObjects = counts
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for managed objects")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
 func (rc *ReconciliationContext) listPVCs() (*corev1.PersistentVolumeClaimList, error) {
 	rc.ReqLogger.Info("reconciler::listPVCs")
 
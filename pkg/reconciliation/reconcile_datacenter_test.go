@@ -9,11 +9,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/internal/result"
 	"github.com/k8ssandra/cass-operator/pkg/mocks"
 )
 
@@ -117,3 +120,69 @@ func TestDeletePVCs_FailedToDelete(t *testing.T) {
 
 	assert.EqualError(t, err, "failed to delete")
 }
+
+func TestListManagedStatefulSets(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	for _, rackName := range []string{"rack1", "rack2"} {
+		sts, err := newStatefulSetForCassandraDatacenter(nil, rackName, rc.Datacenter, 1, false)
+		assert.NoError(t, err)
+		assert.NoError(t, rc.Client.Create(rc.Ctx, sts))
+	}
+
+	otherDc := rc.Datacenter.DeepCopy()
+	otherDc.Name = "dc2"
+	otherSts, err := newStatefulSetForCassandraDatacenter(nil, "rack1", otherDc, 1, false)
+	assert.NoError(t, err)
+	assert.NoError(t, rc.Client.Create(rc.Ctx, otherSts))
+
+	unmanagedSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unmanaged",
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    rc.Datacenter.GetDatacenterLabels(),
+		},
+	}
+	assert.NoError(t, rc.Client.Create(rc.Ctx, unmanagedSts))
+
+	statefulSets, err := ListManagedStatefulSets(rc.Ctx, rc.Client, rc.Datacenter)
+	assert.NoError(t, err)
+
+	names := []string{}
+	for _, sts := range statefulSets {
+		names = append(names, sts.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		newNamespacedNameForStatefulSet(rc.Datacenter, "rack1").Name,
+		newNamespacedNameForStatefulSet(rc.Datacenter, "rack2").Name,
+	}, names)
+}
+
+func TestCheckManagedObjectCounts(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{{Name: "rack1"}, {Name: "rack2"}}
+	assert.NoError(t, rc.CalculateRackInformation())
+	assert.False(t, rc.CheckHeadlessServices().Completed())
+	assert.False(t, rc.CheckRackCreation().Completed())
+
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "server-data-pod-0",
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    rc.Datacenter.GetRackLabels("rack1"),
+		},
+	}
+	assert.NoError(t, rc.Client.Create(rc.Ctx, pvc))
+
+	assert.Equal(t, result.Continue(), rc.CheckManagedObjectCounts())
+
+	// The seed service is labelled for the whole cluster, so it's not counted for the datacenter
+	assert.Equal(t, api.ManagedObjectCounts{
+		StatefulSets:           2,
+		Services:               3,
+		PersistentVolumeClaims: 1,
+	}, rc.Datacenter.Status.ManagedObjects)
+}
@@ -4,10 +4,16 @@
 package reconciliation
 
 import (
+	"reflect"
+	"sync"
+	"time"
+
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/events"
 	"github.com/k8ssandra/cass-operator/pkg/internal/result"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/k8ssandra/cass-operator/pkg/utils"
@@ -34,7 +40,7 @@ func (rc *ReconciliationContext) CreateEndpointsForAdditionalSeedService() resul
 		return result.Error(err)
 	}
 
-	rc.Recorder.Eventf(rc.Datacenter, "Normal", "CreatedResource", "Created endpoints %s", endpoints.Name)
+	rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.CreatedResource, "Created endpoints %s", endpoints.Name)
 
 	return result.Continue()
 }
@@ -84,12 +90,27 @@ func (rc *ReconciliationContext) CheckAdditionalSeedEndpoints() result.Reconcile
 		for _, subset := range currentEndpoints.Subsets {
 			for _, addr := range subset.Addresses {
 				if addr.TargetRef != nil {
+					if dc.Spec.PruneStaleSeeds {
+						stale, err := rc.isStaleSeedAddress(addr)
+						if err != nil {
+							logger.Error(err, "Could not look up the pod of a seed address", "pod", addr.TargetRef.Name)
+							return result.Error(err)
+						}
+						if stale {
+							logger.Info("Pruning stale seed address", "ip", addr.IP, "pod", addr.TargetRef.Name)
+							continue
+						}
+					}
 					// Managed by something else, so we want to keep this
 					desiredEndpoints.Subsets[0].Addresses = append(desiredEndpoints.Subsets[0].Addresses, addr)
 				}
 			}
 		}
 
+		if dc.Spec.PruneStaleSeeds {
+			desiredEndpoints.Subsets[0].Addresses = uniqueEndpointAddresses(desiredEndpoints.Subsets[0].Addresses)
+		}
+
 		// if we found the endpoints already, check if it needs updating
 		if !utils.ResourcesHaveSameHash(currentEndpoints, desiredEndpoints) {
 			resourceVersion := currentEndpoints.GetResourceVersion()
@@ -128,3 +149,116 @@ func (rc *ReconciliationContext) GetAdditionalSeedEndpoint() (*corev1.Endpoints,
 	err := rc.Client.Get(rc.Ctx, nsName, currentEndpoints)
 	return currentEndpoints, err
 }
+
+// SeedEndpointsSynced reports whether the endpoints of the seed service have the IPs of exactly the
+// pods labeled as seeds. The endpoints controller updates them after the seed labels change, until
+// then the nodes reloading their seeds through the service would get the previous ones.
+func (rc *ReconciliationContext) SeedEndpointsSynced() (bool, error) {
+	dc := rc.Datacenter
+	nsName := types.NamespacedName{Name: dc.GetSeedServiceName(), Namespace: dc.Namespace}
+	endpoints := &corev1.Endpoints{}
+	if err := rc.Client.Get(rc.Ctx, nsName, endpoints); err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	endpointIPs := utils.StringSet{}
+	for _, subset := range endpoints.Subsets {
+		// the seed service publishes the addresses of the pods that are not ready too
+		for _, addr := range append(subset.Addresses, subset.NotReadyAddresses...) {
+			endpointIPs[addr.IP] = true
+		}
+	}
+
+	// the terminating pods are published too, until they are gone
+	seedIPs := utils.StringSet{}
+	for _, pod := range rc.clusterPods {
+		if pod.Labels[api.SeedNodeLabel] != "true" || pod.Status.PodIP == "" ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		seedIPs[pod.Status.PodIP] = true
+	}
+
+	return reflect.DeepEqual(endpointIPs, seedIPs), nil
+}
+
+// seedEndpointsSyncTimeout is how long the reconciles wait for the endpoints of the seed service to
+// follow the seed labels before moving on
+const seedEndpointsSyncTimeout = time.Minute
+
+// seedEndpointsWait keeps since when the endpoints of the seed service of each datacenter are out
+// of sync with the seed labels. The reconciliation context is created again on every reconcile.
+type seedEndpointsWait struct {
+	lock  sync.Mutex
+	since map[types.NamespacedName]time.Time
+}
+
+var seedEndpointsWaits = &seedEndpointsWait{since: make(map[types.NamespacedName]time.Time)}
+
+// expired records that the endpoints of the datacenter are out of sync, and returns whether they
+// have been for longer than seedEndpointsSyncTimeout, and whether the timeout just expired
+func (w *seedEndpointsWait) expired(dc types.NamespacedName, now time.Time) (bool, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	since, found := w.since[dc]
+	if !found {
+		w.since[dc] = now
+		return false, false
+	}
+	if since.IsZero() {
+		return true, false
+	}
+	if now.Sub(since) < seedEndpointsSyncTimeout {
+		return false, false
+	}
+	// a zero time marks the timeout as reported
+	w.since[dc] = time.Time{}
+	return true, true
+}
+
+// reset forgets the wait of the datacenter, once its endpoints are in sync
+func (w *seedEndpointsWait) reset(dc types.NamespacedName) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	delete(w.since, dc)
+}
+
+// isStaleSeedAddress reports whether the address references a pod of this cluster that is being
+// deleted or was recreated with a new IP. The pods that are not found, such as the pods of other
+// Kubernetes clusters, and the pods that don't belong to this cluster are left alone.
+func (rc *ReconciliationContext) isStaleSeedAddress(addr corev1.EndpointAddress) (bool, error) {
+	ref := addr.TargetRef
+	namespace := rc.Datacenter.Namespace
+	if ref == nil || ref.Kind != "Pod" || (ref.Namespace != "" && ref.Namespace != namespace) {
+		return false, nil
+	}
+
+	pod := &corev1.Pod{}
+	if err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, pod); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !labels.SelectorFromSet(rc.Datacenter.GetClusterLabels()).Matches(labels.Set(pod.Labels)) {
+		return false, nil
+	}
+
+	return pod.GetDeletionTimestamp() != nil || !utils.GetPodIPSet([]*corev1.Pod{pod})[addr.IP], nil
+}
+
+// uniqueEndpointAddresses removes the addresses whose IP is already in the list
+func uniqueEndpointAddresses(addresses []corev1.EndpointAddress) []corev1.EndpointAddress {
+	seen := utils.StringSet{}
+	unique := make([]corev1.EndpointAddress, 0, len(addresses))
+	for _, addr := range addresses {
+		if seen[addr.IP] {
+			continue
+		}
+		seen[addr.IP] = true
+		unique = append(unique, addr)
+	}
+	return unique
+}
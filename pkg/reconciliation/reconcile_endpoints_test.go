@@ -0,0 +1,167 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+)
+
+func TestCheckAdditionalSeedEndpoints_PruneStaleSeeds(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	dc := rc.Datacenter
+	dc.Spec.AdditionalSeeds = []string{"192.168.1.1"}
+	dc.Spec.PruneStaleSeeds = true
+	assert.NoError(rc.Client.Update(rc.Ctx, dc))
+
+	// pod-b was recreated with a new IP, pod-d is being deleted and pod-e is not a pod of the cluster
+	for name, ip := range map[string]string{"pod-a": "10.0.0.1", "pod-b": "10.0.0.9", "pod-d": "10.0.0.4", "pod-e": "10.0.0.6"} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  dc.Namespace,
+				Labels:     dc.GetClusterLabels(),
+				Finalizers: []string{"test/finalizer"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+		if name == "pod-e" {
+			pod.Labels = map[string]string{"app": "other"}
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, pod))
+		if name == "pod-d" {
+			assert.NoError(rc.Client.Delete(rc.Ctx, pod))
+		}
+	}
+
+	podRef := func(name, namespace string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{Kind: "Pod", Name: name, Namespace: namespace}
+	}
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dc.GetAdditionalSeedsServiceName(),
+			Namespace: dc.Namespace,
+		},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{
+				{IP: "192.168.1.1"},
+				{IP: "10.0.0.1", TargetRef: podRef("pod-a", dc.Namespace)},
+				{IP: "10.0.0.2", TargetRef: podRef("pod-b", dc.Namespace)},
+				{IP: "10.0.0.1", TargetRef: podRef("pod-a", dc.Namespace)},
+				{IP: "10.5.0.1", TargetRef: podRef("pod-c", "other-namespace")},
+				{IP: "10.0.0.4", TargetRef: podRef("pod-d", dc.Namespace)},
+				{IP: "10.0.0.5", TargetRef: podRef("pod-e", dc.Namespace)},
+				{IP: "10.6.0.1", TargetRef: podRef("remote-pod", dc.Namespace)},
+			},
+		}},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, endpoints))
+
+	assert.False(rc.CheckAdditionalSeedEndpoints().Completed())
+
+	current, err := rc.GetAdditionalSeedEndpoint()
+	assert.NoError(err)
+	var ips []string
+	for _, addr := range current.Subsets[0].Addresses {
+		ips = append(ips, addr.IP)
+	}
+	// the stale IP of pod-b, the deleted pod-d and the duplicate of pod-a are pruned. The pods of
+	// other namespaces, the pods that are not found, such as the pods of other Kubernetes clusters,
+	// and the pods of other applications are kept.
+	assert.Equal([]string{"192.168.1.1", "10.0.0.1", "10.5.0.1", "10.0.0.5", "10.6.0.1"}, ips)
+}
+
+func TestSeedEndpointsSynced(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	dc := rc.Datacenter
+	seedPod := func(name, ip string) *corev1.Pod {
+		labels := dc.GetClusterLabels()
+		labels[api.SeedNodeLabel] = "true"
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: dc.Namespace, Labels: labels},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+	}
+	notSeed := seedPod("pod-c", "10.0.0.3")
+	delete(notSeed.Labels, api.SeedNodeLabel)
+	rc.clusterPods = []*corev1.Pod{seedPod("pod-a", "10.0.0.1"), seedPod("pod-b", "10.0.0.2"), notSeed}
+
+	// The endpoints controller hasn't created the endpoints yet
+	synced, err := rc.SeedEndpointsSynced()
+	assert.NoError(err)
+	assert.False(synced)
+
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: dc.GetSeedServiceName(), Namespace: dc.Namespace},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+			NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}},
+		}},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, endpoints))
+
+	synced, err = rc.SeedEndpointsSynced()
+	assert.NoError(err)
+	assert.True(synced)
+
+	// pod-b is no longer a seed and pod-c became one, the endpoints lag behind
+	delete(rc.clusterPods[1].Labels, api.SeedNodeLabel)
+	notSeed.Labels[api.SeedNodeLabel] = "true"
+	synced, err = rc.SeedEndpointsSynced()
+	assert.NoError(err)
+	assert.False(synced)
+
+	endpoints.Subsets[0].NotReadyAddresses = []corev1.EndpointAddress{{IP: "10.0.0.3"}}
+	assert.NoError(rc.Client.Update(rc.Ctx, endpoints))
+	synced, err = rc.SeedEndpointsSynced()
+	assert.NoError(err)
+	assert.True(synced)
+
+	// The seed service keeps publishing a terminating seed pod until it is gone
+	now := metav1.Now()
+	rc.clusterPods[0].DeletionTimestamp = &now
+	synced, err = rc.SeedEndpointsSynced()
+	assert.NoError(err)
+	assert.True(synced)
+}
+
+func TestSeedEndpointsWait(t *testing.T) {
+	assert := assert.New(t)
+
+	wait := &seedEndpointsWait{since: make(map[types.NamespacedName]time.Time)}
+	dc := types.NamespacedName{Name: "dc1", Namespace: "test"}
+	start := time.Now()
+
+	expired, justExpired := wait.expired(dc, start)
+	assert.False(expired)
+	assert.False(justExpired)
+	expired, _ = wait.expired(dc, start.Add(seedEndpointsSyncTimeout/2))
+	assert.False(expired)
+
+	// The timeout is reported once, then the reconciles stop waiting
+	expired, justExpired = wait.expired(dc, start.Add(seedEndpointsSyncTimeout))
+	assert.True(expired)
+	assert.True(justExpired)
+	expired, justExpired = wait.expired(dc, start.Add(2*seedEndpointsSyncTimeout))
+	assert.True(expired)
+	assert.False(justExpired)
+
+	// Once the endpoints are in sync, the next change is waited for again
+	wait.reset(dc)
+	expired, _ = wait.expired(dc, start.Add(3*seedEndpointsSyncTimeout))
+	assert.False(expired)
+}
@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -26,6 +28,7 @@ import (
 	taskapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
 	"github.com/k8ssandra/cass-operator/pkg/events"
 	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/k8ssandra/cass-operator/pkg/images"
 	"github.com/k8ssandra/cass-operator/pkg/internal/result"
 	"github.com/k8ssandra/cass-operator/pkg/oplabels"
 	"github.com/k8ssandra/cass-operator/pkg/psp"
@@ -52,6 +55,10 @@ func (rc *ReconciliationContext) CalculateRackInformation() error {
 
 	rc.ReqLogger.Info("reconcile_racks::calculateRackInformation")
 
+	if err := rc.detectRacks(); err != nil {
+		return err
+	}
+
 	// Create RackInformation
 
 	nodeCount := int(rc.Datacenter.Spec.Size)
@@ -65,16 +72,7 @@ func (rc *ReconciliationContext) CalculateRackInformation() error {
 		nodeCount = 0
 	}
 
-	// 3 seeds per datacenter (this could be two, but we would like three seeds per cluster
-	// and it's not easy for us to know if we're in a multi DC cluster in this part of the code)
-	// OR all of the nodes, if there's less than 3
-	// OR one per rack if there are four or more racks
-	seedCount := 3
-	if nodeCount < 3 {
-		seedCount = nodeCount
-	} else if rackCount > 3 {
-		seedCount = rackCount
-	}
+	seedCount := desiredSeedCount(nodeCount, rackCount, int(rc.Datacenter.Spec.SeedsPerRack))
 
 	var desiredRackInformation []*RackInformation
 
@@ -102,6 +100,35 @@ func (rc *ReconciliationContext) CalculateRackInformation() error {
 	return nil
 }
 
+// maxAutoSeeds caps the number of seeds computed from the size of the datacenter
+const maxAutoSeeds = 10
+
+// desiredSeedCount returns the number of seeds of the datacenter, never more than its nodes.
+// With seedsPerRack unset, there are 3 seeds per datacenter (this could be two, but we would like
+// three seeds per cluster and it's not easy for us to know if we're in a multi DC cluster in this
+// part of the code), or one per rack if there are four or more racks. Large datacenters get one
+// seed per 10 nodes, up to maxAutoSeeds.
+func desiredSeedCount(nodeCount, rackCount, seedsPerRack int) int {
+	seedCount := seedsPerRack * rackCount
+	if seedsPerRack <= 0 {
+		seedCount = nodeCount / 10
+		if seedCount > maxAutoSeeds {
+			seedCount = maxAutoSeeds
+		}
+		if seedCount < 3 {
+			seedCount = 3
+		}
+		if seedCount < rackCount {
+			seedCount = rackCount
+		}
+	}
+
+	if nodeCount < seedCount {
+		seedCount = nodeCount
+	}
+	return seedCount
+}
+
 func (rc *ReconciliationContext) CheckSuperuserSecretCreation() result.ReconcileResult {
 	rc.ReqLogger.Info("reconcile_racks::CheckSuperuserSecretCreation")
 
@@ -162,7 +189,10 @@ func (rc *ReconciliationContext) CheckRackCreation() result.ReconcileResult {
 }
 
 func (rc *ReconciliationContext) desiredStatefulSetForExistingStatefulSet(sts *appsv1.StatefulSet, rackName string) (desiredSts *appsv1.StatefulSet, err error) {
-	dc := rc.Datacenter
+	dc, err := rc.datacenterForRack(rackName, sts)
+	if err != nil {
+		return nil, err
+	}
 
 	// when Cass Operator was released, we accidentally used the incorrect managed-by
 	// label of "cassandra-operator" we have since fixed this to be "cass-operator",
@@ -170,7 +200,50 @@ func (rc *ReconciliationContext) desiredStatefulSetForExistingStatefulSet(sts *a
 	// StatefulSet. Consequently, we must preserve the old labels in this case.
 	usesDefunct := usesDefunctPvcManagedByLabel(sts)
 
-	return newStatefulSetForCassandraDatacenter(sts, rackName, dc, int(*sts.Spec.Replicas), usesDefunct)
+	desiredSts, err = newStatefulSetForCassandraDatacenter(sts, rackName, dc, int(*sts.Spec.Replicas), usesDefunct)
+	if err != nil {
+		return nil, err
+	}
+
+	// once the replaced nodes rejoined the ring, see CheckReplaceAddressRemoval(), the replace
+	// address stays out of the pod template until a different one is configured
+	if removed := sts.Annotations[api.ReplaceAddressRemovedAnnotation]; dc.Spec.RemoveReplaceAddressAfterReplace && removed != "" {
+		template := desiredSts.Spec.Template.DeepCopy()
+		if removeReplaceAddressEnv(template) == removed {
+			desiredSts.Spec.Template = *template
+			metav1.SetMetaDataAnnotation(&desiredSts.ObjectMeta, api.ReplaceAddressRemovedAnnotation, removed)
+			delete(desiredSts.Annotations, utils.ResourceHashAnnotationKey)
+			utils.AddHashAnnotation(desiredSts)
+		}
+	}
+
+	keepServerEnvOrder(desiredSts, sts)
+
+	return desiredSts, nil
+}
+
+// keepServerEnvOrder keeps the order of the environment of the server container of the existing
+// StatefulSet when only the order changed, so that reordering it doesn't restart the pods.
+func keepServerEnvOrder(desiredSts, sts *appsv1.StatefulSet) {
+	var currentEnv []corev1.EnvVar
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name == CassandraContainerName {
+			currentEnv = container.Env
+		}
+	}
+
+	for i := range desiredSts.Spec.Template.Spec.Containers {
+		container := &desiredSts.Spec.Template.Spec.Containers[i]
+		if container.Name != CassandraContainerName {
+			continue
+		}
+		if len(currentEnv) == 0 || reflect.DeepEqual(container.Env, currentEnv) || !EnvVarsEqual(container.Env, currentEnv) {
+			return
+		}
+		container.Env = append([]corev1.EnvVar{}, currentEnv...)
+		delete(desiredSts.Annotations, utils.ResourceHashAnnotationKey)
+		utils.AddHashAnnotation(desiredSts)
+	}
 }
 
 func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
@@ -186,8 +259,29 @@ func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
 				Info("Skipping rack because CanaryUpgrade is turned on")
 			return result.Continue()
 		}
+		if rc.isRackPaused(rackName) {
+			logger.
+				WithValues("rackName", rackName).
+				Info("Skipping rack because it is paused")
+			continue
+		}
 		statefulSet := rc.statefulSets[idx]
 
+		if rc.isStatefulSetReconciled(statefulSet) {
+			logger.
+				WithValues("rackName", rackName).
+				Info("Skipping statefulset unchanged since the last reconciled generation")
+			if rc.rackUpgradeInProgress(rackName, statefulSet) {
+				if podName := rc.rackPodBeingUpdated(statefulSet); podName != "" {
+					if err := rc.setRollingOperation(api.RollingOperationUpgrade, podName); err != nil {
+						return result.Error(err)
+					}
+				}
+				return result.RequeueSoon(10)
+			}
+			continue
+		}
+
 		desiredSts, err := rc.desiredStatefulSetForExistingStatefulSet(statefulSet, rackName)
 
 		if err != nil {
@@ -216,6 +310,27 @@ func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
 			desiredSts.Labels = utils.MergeMap(map[string]string{}, statefulSet.Labels, desiredSts.Labels)
 			desiredSts.Annotations = utils.MergeMap(map[string]string{}, statefulSet.Annotations, desiredSts.Annotations)
 
+			// volumeClaimTemplates and podManagementPolicy can't be updated, the StatefulSet has to be
+			// recreated to use new ones. The pods are orphaned and adopted by the new StatefulSet.
+			if !VolumeClaimTemplatesEqual(statefulSet.Spec.VolumeClaimTemplates, desiredSts.Spec.VolumeClaimTemplates) {
+				logger.
+					WithValues("rackName", rackName).
+					Info("statefulset volumeClaimTemplates changed, recreating it")
+				if err := rc.deleteStatefulSet(statefulSet); err != nil {
+					return result.Error(err)
+				}
+				return result.RequeueSoon(10)
+			}
+			if statefulSet.Spec.PodManagementPolicy != desiredSts.Spec.PodManagementPolicy {
+				logger.
+					WithValues("rackName", rackName).
+					Info("statefulset podManagementPolicy changed, recreating it")
+				if err := rc.deleteStatefulSet(statefulSet); err != nil {
+					return result.Error(err)
+				}
+				return result.RequeueSoon(10)
+			}
+
 			// copy the stuff that can't be updated
 			desiredSts.Spec.VolumeClaimTemplates = statefulSet.Spec.VolumeClaimTemplates
 			// selector must match podTemplate.Labels, those can't be updated either
@@ -243,6 +358,13 @@ func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
 				return result.Error(err)
 			}
 
+			if cassandraContainerImage(&statefulSet.Spec.Template) != cassandraContainerImage(&desiredSts.Spec.Template) {
+				rackPods := FilterPodListByLabels(rc.dcPods, dc.GetRackLabels(rackName))
+				if err := rc.TakeSnapshot(rackPods, snapshotOperationUpgrade); err != nil {
+					return result.Error(err)
+				}
+			}
+
 			desiredSts.DeepCopyInto(statefulSet)
 
 			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.UpdatingRack,
@@ -251,6 +373,10 @@ func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
 			dcPatch := client.MergeFrom(dc.DeepCopy())
 			updated := rc.setCondition(
 				api.NewDatacenterCondition(api.DatacenterUpdating, corev1.ConditionTrue))
+			if !dc.RollingOperationInProgress() {
+				dc.Status.RollingOperation = &api.RollingOperation{Type: api.RollingOperationUpgrade}
+				updated = true
+			}
 
 			if updated {
 				err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch)
@@ -291,26 +417,19 @@ func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
 			// call us back when these changes are done and the new pods are back to ready
 			return result.Done()
 		} else {
+			if err := rc.markStatefulSetReconciled(statefulSet); err != nil {
+				return result.Error(err)
+			}
 
 			// the pod template is right, but if any pods don't match it,
 			// or are missing, we should not move onto the next rack,
 			// because there's an upgrade in progress
-
-			status := statefulSet.Status
-			if statefulSet.Generation != status.ObservedGeneration ||
-				status.Replicas != status.ReadyReplicas ||
-				status.Replicas != status.CurrentReplicas ||
-				status.Replicas != status.UpdatedReplicas {
-
-				logger.Info(
-					"waiting for upgrade to finish on statefulset",
-					"statefulset", statefulSet.Name,
-					"replicas", status.Replicas,
-					"readyReplicas", status.ReadyReplicas,
-					"currentReplicas", status.CurrentReplicas,
-					"updatedReplicas", status.UpdatedReplicas,
-				)
-
+			if rc.rackUpgradeInProgress(rackName, statefulSet) {
+				if podName := rc.rackPodBeingUpdated(statefulSet); podName != "" {
+					if err := rc.setRollingOperation(api.RollingOperationUpgrade, podName); err != nil {
+						return result.Error(err)
+					}
+				}
 				return result.RequeueSoon(10)
 			}
 		}
@@ -320,6 +439,114 @@ func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
 	return result.Continue()
 }
 
+// rackUpgradeInProgress returns true if the pods of the rack don't all match the pod template
+// of its statefulset yet
+func (rc *ReconciliationContext) rackUpgradeInProgress(rackName string, statefulSet *appsv1.StatefulSet) bool {
+	// waiting on a rack that is entirely down would block the other racks
+	if rc.isRackDown(rackName) {
+		rc.ReqLogger.
+			WithValues("rackName", rackName).
+			Info("Not waiting for rack because it is down")
+		return false
+	}
+
+	status := statefulSet.Status
+	if statefulSet.Generation != status.ObservedGeneration ||
+		status.Replicas != status.ReadyReplicas ||
+		status.Replicas != status.CurrentReplicas ||
+		status.Replicas != status.UpdatedReplicas {
+
+		rc.ReqLogger.Info(
+			"waiting for upgrade to finish on statefulset",
+			"statefulset", statefulSet.Name,
+			"replicas", status.Replicas,
+			"readyReplicas", status.ReadyReplicas,
+			"currentReplicas", status.CurrentReplicas,
+			"updatedReplicas", status.UpdatedReplicas,
+		)
+
+		return true
+	}
+	return false
+}
+
+// rackPodBeingUpdated returns the name of the pod the statefulset is replacing with its updated pod
+// template. The pods are updated from the highest ordinal, the first one that isn't updated and ready
+// is being processed.
+func (rc *ReconciliationContext) rackPodBeingUpdated(statefulSet *appsv1.StatefulSet) string {
+	revision := statefulSet.Status.UpdateRevision
+	if revision == "" || statefulSet.Spec.Replicas == nil {
+		return ""
+	}
+
+	for idx := *statefulSet.Spec.Replicas - 1; idx >= 0; idx-- {
+		pod := rc.getDCPodByName(getStatefulSetPodNameForIdx(statefulSet, idx))
+		if pod == nil {
+			continue
+		}
+		if pod.Labels[appsv1.ControllerRevisionHashLabelKey] != revision || !isServerReady(pod) {
+			return pod.Name
+		}
+	}
+	return ""
+}
+
+// setRollingOperation records in the status the rolling operation in progress and the pod it is
+// processing
+func (rc *ReconciliationContext) setRollingOperation(operationType api.RollingOperationType, podName string) error {
+	operation := &api.RollingOperation{Type: operationType, Pod: podName}
+	if current := rc.Datacenter.Status.RollingOperation; current != nil && *current == *operation {
+		return nil
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.Datacenter.Status.RollingOperation = operation
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for rolling operation")
+		return err
+	}
+	return nil
+}
+
+// reconciledGeneration is the value of the ReconciledGenerationAnnotation for the statefulset
+func (rc *ReconciliationContext) reconciledGeneration(statefulSet *appsv1.StatefulSet) string {
+	return fmt.Sprintf("%d/%d", rc.Datacenter.Generation, statefulSet.Generation)
+}
+
+// isStatefulSetReconciled returns true if SkipUnchangedStatefulSets is enabled, the datacenter
+// generation was fully reconciled and the statefulset was found up to date for it, without being
+// modified since.
+func (rc *ReconciliationContext) isStatefulSetReconciled(statefulSet *appsv1.StatefulSet) bool {
+	dc := rc.Datacenter
+	if !dc.Spec.SkipUnchangedStatefulSets || dc.Status.ObservedGeneration != dc.Generation {
+		return false
+	}
+	return statefulSet.Annotations[api.ReconciledGenerationAnnotation] == rc.reconciledGeneration(statefulSet)
+}
+
+// markStatefulSetReconciled records that the statefulset is up to date for the current generation
+// of the datacenter, when SkipUnchangedStatefulSets is enabled
+func (rc *ReconciliationContext) markStatefulSetReconciled(statefulSet *appsv1.StatefulSet) error {
+	generation := rc.reconciledGeneration(statefulSet)
+	if !rc.Datacenter.Spec.SkipUnchangedStatefulSets || statefulSet.Annotations[api.ReconciledGenerationAnnotation] == generation {
+		return nil
+	}
+
+	patch := client.MergeFrom(statefulSet.DeepCopy())
+	metav1.SetMetaDataAnnotation(&statefulSet.ObjectMeta, api.ReconciledGenerationAnnotation, generation)
+	return rc.Client.Patch(rc.Ctx, statefulSet, patch)
+}
+
+// cassandraContainerImage returns the image of the Cassandra container in the pod template
+func cassandraContainerImage(template *corev1.PodTemplateSpec) string {
+	for _, container := range template.Spec.Containers {
+		if container.Name == CassandraContainerName {
+			return container.Image
+		}
+	}
+	return ""
+}
+
 func (rc *ReconciliationContext) CheckRackForceUpgrade() result.ReconcileResult {
 	// This code is *very* similar to CheckRackPodTemplate(), but it's not an exact
 	// copy. Some 3 to 5 line parts could maybe be extracted into functions.
@@ -340,7 +567,12 @@ func (rc *ReconciliationContext) CheckRackForceUpgrade() result.ReconcileResult
 
 			// have to use zero here, because each statefulset is created with no replicas
 			// in GetStatefulSetForRack()
-			desiredSts, err := newStatefulSetForCassandraDatacenter(statefulSet, rackName, dc, nextRack.NodeCount, false)
+			rackDc, err := rc.datacenterForRack(rackName, statefulSet)
+			if err != nil {
+				return result.Error(err)
+			}
+
+			desiredSts, err := newStatefulSetForCassandraDatacenter(statefulSet, rackName, rackDc, nextRack.NodeCount, false)
 			if err != nil {
 				logger.Error(err, "error calling newStatefulSetForCassandraDatacenter")
 				return result.Error(err)
@@ -369,6 +601,9 @@ func (rc *ReconciliationContext) CheckRackForceUpgrade() result.ReconcileResult
 
 			dcPatch := client.MergeFrom(dc.DeepCopy())
 			rc.setCondition(api.NewDatacenterCondition(api.DatacenterUpdating, corev1.ConditionTrue))
+			if !dc.RollingOperationInProgress() {
+				dc.Status.RollingOperation = &api.RollingOperation{Type: api.RollingOperationUpgrade}
+			}
 
 			if err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch); err != nil {
 				logger.Error(err, "error patching datacenter status for updating condition")
@@ -426,6 +661,9 @@ func (rc *ReconciliationContext) CheckRackLabels() result.ReconcileResult {
 
 	for idx := range rc.desiredRackInformation {
 		rackInfo := rc.desiredRackInformation[idx]
+		if rc.isRackPaused(rackInfo.RackName) {
+			continue
+		}
 		statefulSet := rc.statefulSets[idx]
 		patch := client.MergeFrom(statefulSet.DeepCopy())
 
@@ -448,6 +686,134 @@ func (rc *ReconciliationContext) CheckRackLabels() result.ReconcileResult {
 			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.LabeledRackResource,
 				"Update rack labels for StatefulSet %s", statefulSet.Name)
 		}
+
+		stsAnnotations := statefulSet.GetAnnotations()
+		if shouldUpdate, updatedAnnotations := shouldUpdateAdditionalAnnotations(stsAnnotations, rc.Datacenter); shouldUpdate {
+			rc.ReqLogger.Info("Updating annotations",
+				"statefulSet", statefulSet.Name,
+				"current", stsAnnotations,
+				"desired", updatedAnnotations)
+			patch := client.MergeFrom(statefulSet.DeepCopy())
+			statefulSet.SetAnnotations(updatedAnnotations)
+
+			if err := rc.Client.Patch(rc.Ctx, statefulSet, patch); err != nil {
+				return result.Error(err)
+			}
+		}
+	}
+
+	return result.Continue()
+}
+
+// CheckRackOwnerReferences sets the datacenter back as the controller of the rack statefulsets
+// that lost their owner reference, for example after a manual edit, so that they are garbage
+// collected with the datacenter.
+func (rc *ReconciliationContext) CheckRackOwnerReferences() result.ReconcileResult {
+	rc.ReqLogger.Info("reconcile_racks::CheckRackOwnerReferences")
+
+	for idx := range rc.desiredRackInformation {
+		statefulSet := rc.statefulSets[idx]
+		if statefulSet == nil || metav1.GetControllerOf(statefulSet) != nil {
+			continue
+		}
+
+		patch := client.MergeFrom(statefulSet.DeepCopy())
+		if err := setControllerReference(rc.Datacenter, statefulSet, rc.Scheme); err != nil {
+			rc.ReqLogger.Error(err, "error calling setControllerReference for statefulset", "statefulSet", statefulSet.Name)
+			return result.Error(err)
+		}
+		if err := rc.Client.Patch(rc.Ctx, statefulSet, patch); err != nil {
+			rc.ReqLogger.Error(err, "error restoring the owner reference of statefulset", "statefulSet", statefulSet.Name)
+			return result.Error(err)
+		}
+
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.RestoredOwnerReference,
+			"Restored the owner reference of StatefulSet %s", statefulSet.Name)
+	}
+
+	return result.Continue()
+}
+
+// labelMigrationLists returns empty lists of the kinds the operator creates for a datacenter, other
+// than its StatefulSets and pods
+func labelMigrationLists() []client.ObjectList {
+	return []client.ObjectList{
+		&corev1.ServiceList{},
+		&corev1.PersistentVolumeClaimList{},
+		&corev1.SecretList{},
+		&policyv1.PodDisruptionBudgetList{},
+		&networkingv1.NetworkPolicyList{},
+	}
+}
+
+// migrateObjectLabels patches the labels of obj if some of its label keys are migrated, and
+// returns true if it did
+func (rc *ReconciliationContext) migrateObjectLabels(obj client.Object) (bool, error) {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	objLabels := obj.GetLabels()
+	if objLabels == nil || !oplabels.MigrateLabels(objLabels) {
+		return false, nil
+	}
+
+	rc.ReqLogger.Info("Migrating labels", "kind", reflect.Indirect(reflect.ValueOf(obj)).Type().Name(), "name", obj.GetName())
+	obj.SetLabels(objLabels)
+	return true, rc.Client.Patch(rc.Ctx, obj, patch)
+}
+
+// CheckLabelMigrations adds the label keys configured in the operator's labelMigrations to the
+// resources of the datacenter still carrying the old keys: statefulsets, pods, services, PVCs,
+// secrets, pod disruption budgets and network policies. The old keys are left in place, since the
+// statefulset selectors can't be changed.
+func (rc *ReconciliationContext) CheckLabelMigrations() result.ReconcileResult {
+	rc.ReqLogger.Info("reconcile_racks::CheckLabelMigrations")
+
+	if !oplabels.HasLabelMigrations() {
+		return result.Continue()
+	}
+
+	for _, statefulSet := range rc.statefulSets {
+		if statefulSet == nil {
+			continue
+		}
+		migrated, err := rc.migrateObjectLabels(statefulSet)
+		if err != nil {
+			return result.Error(err)
+		}
+		if !migrated {
+			continue
+		}
+
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.LabeledRackResource,
+			"Migrated labels for StatefulSet %s", statefulSet.Name)
+	}
+
+	for _, pod := range rc.dcPods {
+		if _, err := rc.migrateObjectLabels(pod); err != nil {
+			return result.Error(err)
+		}
+	}
+
+	// The other resources are found by their labels once migrated, the old keys may include the
+	// ones selecting the datacenter
+	dcSelector := labels.SelectorFromSet(rc.Datacenter.GetDatacenterLabels())
+	for _, list := range labelMigrationLists() {
+		if err := rc.Client.List(rc.Ctx, list, client.InNamespace(rc.Datacenter.Namespace)); err != nil {
+			return result.Error(err)
+		}
+		objects, err := meta.ExtractList(list)
+		if err != nil {
+			return result.Error(err)
+		}
+		for _, object := range objects {
+			obj := object.(client.Object)
+			migrated := utils.MergeMap(map[string]string{}, obj.GetLabels())
+			if !oplabels.MigrateLabels(migrated) || !dcSelector.Matches(labels.Set(migrated)) {
+				continue
+			}
+			if _, err := rc.migrateObjectLabels(obj); err != nil {
+				return result.Error(err)
+			}
+		}
 	}
 
 	return result.Continue()
@@ -461,6 +827,9 @@ func (rc *ReconciliationContext) CheckRackStoppedState() result.ReconcileResult
 	racksUpdated := false
 	for idx := range rc.desiredRackInformation {
 		rackInfo := rc.desiredRackInformation[idx]
+		if rc.isRackPaused(rackInfo.RackName) {
+			continue
+		}
 		statefulSet := rc.statefulSets[idx]
 
 		stopped := rc.Datacenter.Spec.Stopped
@@ -537,6 +906,9 @@ func (rc *ReconciliationContext) CheckRackStoppedState() result.ReconcileResult
 // checkSeedLabels loops over all racks and makes sure that the proper pods are labelled as seeds.
 func (rc *ReconciliationContext) checkSeedLabels() (int, error) {
 	rc.ReqLogger.Info("reconcile_racks::CheckSeedLabels")
+	if err := rc.checkSplitBrainSeeds(); err != nil {
+		return 0, err
+	}
 	seedCount := 0
 	for idx := range rc.desiredRackInformation {
 		rackInfo := rc.desiredRackInformation[idx]
@@ -590,6 +962,25 @@ func (rc *ReconciliationContext) CheckPodsReady(endpointData httphelper.CassMeta
 	if err != nil {
 		return result.Error(err)
 	}
+
+	// the nodes reload their seeds through the seed service, so wait for its endpoints to follow
+	// the seed labels
+	seedsSynced, err := rc.SeedEndpointsSynced()
+	if err != nil {
+		return result.Error(err)
+	}
+	dcName := types.NamespacedName{Name: rc.Datacenter.Name, Namespace: rc.Datacenter.Namespace}
+	if seedsSynced {
+		seedEndpointsWaits.reset(dcName)
+	} else if expired, justExpired := seedEndpointsWaits.expired(dcName, time.Now()); !expired {
+		rc.ReqLogger.Info("Waiting for the endpoints of the seed service to follow the seed labels")
+		return result.RequeueSoon(2)
+	} else if justExpired {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.SeedEndpointsNotSynced,
+			"Endpoints of the seed service %s did not follow the seed labels within %s, the nodes may reload stale seeds",
+			rc.Datacenter.GetSeedServiceName(), seedEndpointsSyncTimeout)
+	}
+
 	err = rc.refreshSeeds()
 	if err != nil {
 		return result.Error(err)
@@ -611,7 +1002,7 @@ func (rc *ReconciliationContext) CheckPodsReady(endpointData httphelper.CassMeta
 	rackWaitingForANode, err := rc.startOneNodePerRack(endpointData, seedCount)
 
 	if err != nil {
-		return result.Error(err)
+		return startFailureResult(err)
 	}
 	if rackWaitingForANode != "" {
 		return result.RequeueSoon(2)
@@ -620,6 +1011,11 @@ func (rc *ReconciliationContext) CheckPodsReady(endpointData httphelper.CassMeta
 	// step 3 - get all nodes up
 	// if the cluster isn't healthy, that's ok, but go back to step 1
 	clusterHealthy := rc.isClusterHealthy()
+	if clusterHealthy && rc.Datacenter.Spec.GossipHealthCheck {
+		if clusterHealthy, err = rc.checkGossipConsistency(); err != nil {
+			return result.Error(err)
+		}
+	}
 	if err := rc.updateHealth(clusterHealthy); err != nil {
 		return result.Error(err)
 	}
@@ -633,7 +1029,7 @@ func (rc *ReconciliationContext) CheckPodsReady(endpointData httphelper.CassMeta
 
 	needsMoreNodes, err := rc.startAllNodes(endpointData)
 	if err != nil {
-		return result.Error(err)
+		return startFailureResult(err)
 	}
 	if needsMoreNodes {
 		return result.RequeueSoon(2)
@@ -755,6 +1151,12 @@ func (rc *ReconciliationContext) CheckRackScale() result.ReconcileResult {
 
 	for idx := range rc.desiredRackInformation {
 		rackInfo := rc.desiredRackInformation[idx]
+		if rc.isRackPaused(rackInfo.RackName) {
+			logger.
+				WithValues("rackName", rackInfo.RackName).
+				Info("Skipping rack because it is paused")
+			continue
+		}
 		statefulSet := rc.statefulSets[idx]
 
 		// By the time we get here we know all the racks are ready for that particular size
@@ -778,6 +1180,12 @@ func (rc *ReconciliationContext) CheckRackScale() result.ReconcileResult {
 			} else if dc.GetConditionStatus(api.DatacenterReady) == corev1.ConditionTrue {
 				// We weren't resuming from a stopped state, so we must be growing the
 				// size of the rack and this isn't the initialization stage
+				if dc.GetConditionStatus(api.DatacenterScalingUp) != corev1.ConditionTrue {
+					if remaining := rc.scaleCooldownRemaining(); remaining > 0 {
+						logger.Info("Waiting for the scale cooldown to end before scaling up", "remaining", remaining)
+						return result.RequeueSoon(int(remaining.Seconds()) + 1)
+					}
+				}
 				updated = rc.setCondition(
 					api.NewDatacenterCondition(
 						api.DatacenterScalingUp, corev1.ConditionTrue)) || updated
@@ -812,12 +1220,31 @@ func (rc *ReconciliationContext) CheckRackScale() result.ReconcileResult {
 	return result.Continue()
 }
 
+// scaleCooldownRemaining returns how long to wait before starting a new scale operation,
+// according to ScaleCooldownSeconds
+func (rc *ReconciliationContext) scaleCooldownRemaining() time.Duration {
+	dc := rc.Datacenter
+	if dc.Spec.ScaleCooldownSeconds <= 0 || dc.Status.LastScaleOperation.IsZero() {
+		return 0
+	}
+
+	cooldown := time.Duration(dc.Spec.ScaleCooldownSeconds) * time.Second
+	remaining := time.Until(dc.Status.LastScaleOperation.Add(cooldown))
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
 // CheckRackPodLabels checks each pod and its volume(s) and makes sure they have the
 // proper labels
 func (rc *ReconciliationContext) CheckRackPodLabels() result.ReconcileResult {
 	rc.ReqLogger.Info("reconcile_racks::CheckRackPodLabels")
 
 	for idx := range rc.desiredRackInformation {
+		if rc.isRackPaused(rc.desiredRackInformation[idx].RackName) {
+			continue
+		}
 		statefulSet := rc.statefulSets[idx]
 
 		if err := rc.ReconcilePods(statefulSet); err != nil {
@@ -828,6 +1255,36 @@ func (rc *ReconciliationContext) CheckRackPodLabels() result.ReconcileResult {
 	return result.Continue()
 }
 
+// CheckAdditionalMetadataKeys records the keys of the additional labels and annotations in the
+// status once the racks have been labeled, so that the keys later dropped from the spec can be
+// removed from the resources. Nothing is recorded while a rack is paused, its resources still
+// carry the previous keys.
+func (rc *ReconciliationContext) CheckAdditionalMetadataKeys() result.ReconcileResult {
+	rc.ReqLogger.Info("reconcile_racks::CheckAdditionalMetadataKeys")
+
+	dc := rc.Datacenter
+	for _, rack := range dc.GetRacks() {
+		if rack.Paused {
+			return result.Continue()
+		}
+	}
+
+	labelKeys := oplabels.SortedKeys(dc.Spec.AdditionalLabels)
+	annotationKeys := oplabels.SortedKeys(dc.Spec.AdditionalAnnotations)
+	if reflect.DeepEqual(labelKeys, dc.Status.AdditionalLabelKeys) && reflect.DeepEqual(annotationKeys, dc.Status.AdditionalAnnotationKeys) {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(dc.DeepCopy())
+	dc.Status.AdditionalLabelKeys = labelKeys
+	dc.Status.AdditionalAnnotationKeys = annotationKeys
+	if err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch); err != nil {
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
 func (rc *ReconciliationContext) upsertUser(user api.CassandraUser) error {
 	dc := rc.Datacenter
 	namespace := dc.ObjectMeta.Namespace
@@ -874,6 +1331,10 @@ func (rc *ReconciliationContext) UpdateSecretWatches() error {
 		name := types.NamespacedName{Name: user.SecretName, Namespace: dc.Namespace}
 		names = append(names, name)
 	}
+	if dc.Spec.HotReloadTLS {
+		// The changes of the keystore secret are applied as soon as they are noticed
+		names = append(names, rc.keystoreSecret())
+	}
 	dcNamespacedName := types.NamespacedName{Name: dc.Name, Namespace: dc.Namespace}
 	err := rc.SecretWatches.UpdateWatch(dcNamespacedName, names)
 
@@ -1159,28 +1620,234 @@ func (rc *ReconciliationContext) UpdateStatus() result.ReconcileResult {
 	return result.Continue()
 }
 
-func (rc *ReconciliationContext) updateHealth(healthy bool) error {
-	updated := false
-	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+// ProgressPercent returns the percentage of the desired nodes that are ready and, when a
+// rollout of a rack's StatefulSet is in progress, already running its updated revision.
+func (rc *ReconciliationContext) ProgressPercent() int {
+	desiredNodes := 0
+	updateRevisions := map[string]string{}
+	for idx, rackInfo := range rc.desiredRackInformation {
+		desiredNodes += rackInfo.NodeCount
+		if idx < len(rc.statefulSets) && rc.statefulSets[idx] != nil {
+			updateRevisions[rackInfo.RackName] = rc.statefulSets[idx].Status.UpdateRevision
+		}
+	}
 
-	if !healthy {
-		updated = rc.setCondition(
-			api.NewDatacenterCondition(
-				api.DatacenterHealthy, corev1.ConditionFalse))
-	} else {
-		updated = rc.setCondition(
-			api.NewDatacenterCondition(
-				api.DatacenterHealthy, corev1.ConditionTrue))
+	if desiredNodes == 0 {
+		return 100
 	}
 
-	if updated {
-		err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch)
-		if err != nil {
-			return err
+	doneNodes := 0
+	for _, pod := range rc.dcPods {
+		if !isServerReady(pod) {
+			continue
 		}
+		revision := updateRevisions[pod.Labels[api.RackLabel]]
+		if revision != "" && pod.Labels[appsv1.ControllerRevisionHashLabelKey] != revision {
+			continue
+		}
+		doneNodes++
 	}
 
-	return nil
+	if doneNodes >= desiredNodes {
+		return 100
+	}
+	return doneNodes * 100 / desiredNodes
+}
+
+// PodsRemainingToCreate returns how many of the desired pods of the datacenter do not
+// exist yet
+func (rc *ReconciliationContext) PodsRemainingToCreate() int {
+	desiredNodes := 0
+	for _, rackInfo := range rc.desiredRackInformation {
+		desiredNodes += rackInfo.NodeCount
+	}
+
+	remaining := desiredNodes - len(rc.dcPods)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// CheckProgressPercent updates the reconcile progress percentage in the status
+func (rc *ReconciliationContext) CheckProgressPercent() result.ReconcileResult {
+	if !rc.IsInitialized() {
+		if remaining := rc.PodsRemainingToCreate(); remaining > 0 {
+			rc.ReqLogger.Info("Waiting for the pods of the datacenter to be created", "remaining", remaining)
+		}
+	}
+
+	progress := rc.ProgressPercent()
+	if rc.Datacenter.Status.ProgressPercent == progress {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.Datacenter.Status.ProgressPercent = progress
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for progress percentage")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
+// RackStatuses returns the desired and ready node counts of each rack, with a phase
+// summarizing them and the image its pods run
+func (rc *ReconciliationContext) RackStatuses(serverImage string) []api.RackStatus {
+	podsByRack := GroupPodsByLabel(rc.dcPods, api.RackLabel)
+
+	statuses := make([]api.RackStatus, 0, len(rc.desiredRackInformation))
+	for _, rackInfo := range rc.desiredRackInformation {
+		diff := RackReplicaDiff(rackInfo.NodeCount, podsByRack[rackInfo.RackName])
+		status := api.RackStatus{
+			Name:          rackInfo.RackName,
+			DesiredNodes:  rackInfo.NodeCount,
+			ReadyNodes:    rackInfo.NodeCount - diff,
+			ObservedImage: observedServerImage(podsByRack[rackInfo.RackName], serverImage),
+		}
+
+		switch {
+		case diff == 0:
+			status.Phase = api.RackPhaseReady
+		case diff < 0:
+			status.Phase = api.RackPhaseScalingDown
+		case status.ReadyNodes == 0:
+			status.Phase = api.RackPhaseDown
+		default:
+			status.Phase = api.RackPhasePending
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// observedServerImage returns the image of the cassandra container of the pods. When the pods
+// run different images, it returns one that is not the desired image.
+func observedServerImage(pods []*corev1.Pod, desiredImage string) string {
+	observed := ""
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == CassandraContainerName && (observed == "" || observed == desiredImage) {
+				observed = container.Image
+			}
+		}
+	}
+	return observed
+}
+
+// CheckRackStatuses updates the per-rack statuses of the datacenter, along with the server image
+// its pods are desired to run, and the node count metrics
+func (rc *ReconciliationContext) CheckRackStatuses() result.ReconcileResult {
+	rc.updateNodeMetrics()
+
+	serverImage, err := makeImage(rc.Datacenter)
+	if err != nil {
+		return result.Error(err)
+	}
+
+	statuses := rc.RackStatuses(serverImage)
+	if reflect.DeepEqual(rc.Datacenter.Status.RackStatuses, statuses) && rc.Datacenter.Status.ServerImage == serverImage {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.Datacenter.Status.RackStatuses = statuses
+	rc.Datacenter.Status.ServerImage = serverImage
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for rack statuses")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
+// UnhealthyPods returns the sorted names of the pods that are not ready, crash looping or
+// running on a node that is not ready
+func (rc *ReconciliationContext) UnhealthyPods() ([]string, error) {
+	unhealthy := utils.StringSet{}
+	for _, pod := range findAllPodsNotReady(rc.dcPods) {
+		unhealthy[pod.Name] = true
+	}
+	for _, pod := range FilterPodsInCrashLoop(rc.dcPods) {
+		unhealthy[pod.Name] = true
+	}
+
+	for _, pod := range rc.dcPods {
+		if pod.Spec.NodeName == "" || unhealthy[pod.Name] {
+			continue
+		}
+		node, err := rc.getNode(pod.Spec.NodeName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				unhealthy[pod.Name] = true
+				continue
+			}
+			return nil, err
+		}
+		if !utils.IsNodeReady(node) {
+			unhealthy[pod.Name] = true
+		}
+	}
+
+	names := make([]string, 0, len(unhealthy))
+	for name := range unhealthy {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// CheckUnhealthyPods updates the list of unhealthy pods in the status of the datacenter
+func (rc *ReconciliationContext) CheckUnhealthyPods() result.ReconcileResult {
+	unhealthyPods, err := rc.UnhealthyPods()
+	if err != nil {
+		rc.ReqLogger.Error(err, "error listing unhealthy pods")
+		return result.Error(err)
+	}
+
+	if len(unhealthyPods) == 0 {
+		unhealthyPods = nil
+	}
+	if reflect.DeepEqual(rc.Datacenter.Status.UnhealthyPods, unhealthyPods) {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.Datacenter.Status.UnhealthyPods = unhealthyPods
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for unhealthy pods")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
+func (rc *ReconciliationContext) updateHealth(healthy bool) error {
+	updated := false
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+
+	if !healthy {
+		updated = rc.setCondition(
+			api.NewDatacenterCondition(
+				api.DatacenterHealthy, corev1.ConditionFalse))
+	} else {
+		updated = rc.setCondition(
+			api.NewDatacenterCondition(
+				api.DatacenterHealthy, corev1.ConditionTrue))
+	}
+
+	if updated {
+		err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func hasBeenXMinutes(x int, sinceTime time.Time) bool {
@@ -1316,7 +1983,399 @@ func (rc *ReconciliationContext) isClusterHealthy() bool {
 		}
 	}
 
-	return true
+	return true
+}
+
+// UnreachableEndpoints returns the addresses of the nodes of the datacenter that are not alive and
+// NORMAL in the gossip state of a node
+func UnreachableEndpoints(epData httphelper.CassMetadataEndpoints, dcName string) []string {
+	var unreachable []string
+	for _, ep := range epData.Entity {
+		if ep.Datacenter != dcName {
+			continue
+		}
+		if ep.IsAlive != "true" || !ep.HasStatus(httphelper.StatusNormal) {
+			unreachable = append(unreachable, ep.GetRpcAddress())
+		}
+	}
+	sort.Strings(unreachable)
+	return unreachable
+}
+
+// gossipCheckInterval is the minimum time between two reads of the gossip state of the nodes of a
+// datacenter
+const gossipCheckInterval = time.Minute
+
+var gossipProbes = newProbeThrottle(gossipCheckInterval)
+
+// checkGossipConsistency reads the gossip state of the started nodes and sets the GossipInconsistency
+// condition when one of them sees another node of the datacenter as unreachable. Nodes whose
+// management API can't be reached are skipped. A node is expectedly down during a rolling operation
+// or while a pod is not ready, the check is skipped then, and otherwise runs at most once every
+// gossipCheckInterval. When it doesn't run, the result of the last check is returned.
+func (rc *ReconciliationContext) checkGossipConsistency() (bool, error) {
+	consistent := rc.Datacenter.GetConditionStatus(api.DatacenterGossipInconsistency) != corev1.ConditionTrue
+	if rc.Datacenter.Status.RollingOperation != nil {
+		return consistent, nil
+	}
+	for _, pod := range rc.dcPods {
+		if !isServerReady(pod) {
+			return consistent, nil
+		}
+	}
+	dcName := types.NamespacedName{Name: rc.Datacenter.Name, Namespace: rc.Datacenter.Namespace}
+	if !gossipProbes.allow(dcName, time.Now()) {
+		return consistent, nil
+	}
+
+	pods := FilterPodListByCassNodeState(rc.dcPods, stateStarted)
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	condition := api.NewDatacenterCondition(api.DatacenterGossipInconsistency, corev1.ConditionFalse)
+	var message string
+	for _, pod := range pods {
+		epData, err := rc.NodeMgmtClient.CallMetadataEndpointsEndpoint(pod)
+		if err != nil {
+			rc.ReqLogger.Error(err, "unable to read the gossip state of the node", "pod", pod.Name)
+			continue
+		}
+		if unreachable := UnreachableEndpoints(epData, rc.Datacenter.Name); len(unreachable) > 0 {
+			message = fmt.Sprintf("pod %s sees %s as unreachable", pod.Name, strings.Join(unreachable, ", "))
+			condition = api.NewDatacenterConditionWithReason(api.DatacenterGossipInconsistency,
+				corev1.ConditionTrue, "UnreachableEndpoints", message)
+			break
+		}
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	if rc.setCondition(condition) {
+		if message != "" {
+			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.GossipInconsistency,
+				"Gossip is inconsistent, %s", message)
+		}
+		if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+			return false, err
+		}
+	}
+
+	return message == "", nil
+}
+
+// desiredSeedsForRack returns the names of the pods in the rack that should be labelled as
+// seeds, which are the first SeedCount ready pods ordered by name.
+func (rc *ReconciliationContext) desiredSeedsForRack(rackInfo *RackInformation) utils.StringSet {
+	rackLabels := rc.Datacenter.GetRackLabels(rackInfo.RackName)
+	rackPods := FilterPodListByLabels(rc.dcPods, rackLabels)
+	sort.SliceStable(rackPods, func(i, j int) bool {
+		return rackPods[i].Name < rackPods[j].Name
+	})
+	seeds := utils.StringSet{}
+	for _, pod := range rackPods {
+		if len(seeds) >= rackInfo.SeedCount {
+			break
+		}
+		if isServerReady(pod) {
+			seeds[pod.Name] = true
+		}
+	}
+	return seeds
+}
+
+// checkSplitBrainSeeds detects when the pods currently labelled as seeds share no member with
+// the seeds the operator would pick, for example after a bad manual edit of the seed labels.
+// Nodes that bootstrap off of two disjoint seed sets can end up forming separate rings, so
+// while this is the case the RecoveringSeeds condition is set and labelSeedPods re-converges
+// the labels to the desired set.
+func (rc *ReconciliationContext) checkSplitBrainSeeds() error {
+	rackSeeds := []utils.StringSet{}
+	for _, rackInfo := range rc.desiredRackInformation {
+		rackSeeds = append(rackSeeds, rc.desiredSeedsForRack(rackInfo))
+	}
+	desiredSeeds := utils.UnionStringSets(rackSeeds...)
+
+	// pods that are starting keep their seed label, see labelSeedPods()
+	labelledSeeds := utils.GetPodNameSet(utils.FilterPodsWithFn(
+		FilterPodListByLabel(rc.dcPods, api.SeedNodeLabel, "true"),
+		func(pod *corev1.Pod) bool {
+			return !isServerStarting(pod)
+		}))
+
+	splitBrain := len(desiredSeeds) > 0 && len(labelledSeeds) > 0 &&
+		len(utils.IntersectionStringSet(desiredSeeds, labelledSeeds)) == 0
+
+	if !splitBrain && rc.Datacenter.GetConditionStatus(api.DatacenterRecoveringSeeds) != corev1.ConditionTrue {
+		return nil
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	status := corev1.ConditionFalse
+	if splitBrain {
+		status = corev1.ConditionTrue
+	}
+	if !rc.setCondition(api.NewDatacenterCondition(api.DatacenterRecoveringSeeds, status)) {
+		return nil
+	}
+
+	if splitBrain {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.RecoveringSeeds,
+			"Seed labels do not overlap with the expected seeds, relabeling seed pods")
+	}
+
+	return rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch)
+}
+
+// configBuilderFailure returns the termination message of the server config init container
+// of the pod if it has failed, either on its last run or, while it waits to be restarted, on
+// the previous one. A failure is forgotten once the init container completes.
+func configBuilderFailure(pod *corev1.Pod) (string, bool) {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != ServerConfigContainerName {
+			continue
+		}
+		terminations := []*corev1.ContainerStateTerminated{status.State.Terminated}
+		if status.State.Waiting != nil {
+			terminations = append(terminations, status.LastTerminationState.Terminated)
+		}
+		for _, terminated := range terminations {
+			if terminated != nil && terminated.ExitCode != 0 {
+				message := terminated.Message
+				if message == "" {
+					message = fmt.Sprintf("exited with code %d (%s)", terminated.ExitCode, terminated.Reason)
+				}
+				return message, true
+			}
+		}
+	}
+	return "", false
+}
+
+// CheckConfigBuilderFeatures records in the status the features supported by the config builder
+// image of the statefulsets, to explain why the config fields of other features are ignored
+func (rc *ReconciliationContext) CheckConfigBuilderFeatures() result.ReconcileResult {
+	var image string
+	for _, sts := range rc.statefulSets {
+		if sts == nil {
+			continue
+		}
+		for _, container := range sts.Spec.Template.Spec.InitContainers {
+			if container.Name == ServerConfigContainerName {
+				image = container.Image
+			}
+		}
+		break
+	}
+	if image == "" {
+		return result.Continue()
+	}
+
+	features := images.ConfigBuilderFeatures(image)
+	if reflect.DeepEqual(features, rc.Datacenter.Status.ConfigBuilderFeatures) {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	rc.Datacenter.Status.ConfigBuilderFeatures = features
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
+// CheckConfigBuilderFailures surfaces failures of the server config init container, which
+// would otherwise leave the pod waiting to start without any indication as to why, through
+// the ConfigBuilderFailed condition.
+func (rc *ReconciliationContext) CheckConfigBuilderFailures() result.ReconcileResult {
+	var failedPod *corev1.Pod
+	var message string
+	for _, pod := range rc.dcPods {
+		if msg, failed := configBuilderFailure(pod); failed {
+			failedPod = pod
+			message = fmt.Sprintf("%s: %s", pod.Name, msg)
+			break
+		}
+	}
+
+	if failedPod == nil && rc.Datacenter.GetConditionStatus(api.DatacenterConfigBuilderFailed) != corev1.ConditionTrue {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	condition := api.NewDatacenterCondition(api.DatacenterConfigBuilderFailed, corev1.ConditionFalse)
+	if failedPod != nil {
+		condition = api.NewDatacenterConditionWithReason(api.DatacenterConfigBuilderFailed,
+			corev1.ConditionTrue, "InitContainerFailed", message)
+	}
+	if !rc.setCondition(condition) {
+		return result.Continue()
+	}
+
+	if failedPod != nil {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.ConfigBuilderFailed,
+			"Server config init container failed for pod %s", message)
+	}
+
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for config builder failure")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
+// CheckClusterFormationTimeout sets the ClusterFormationTimeout condition when the first seed of
+// a new cluster is still not ready ClusterFormationTimeoutSeconds after Cassandra was started on
+// it, so that a cluster that fails to form doesn't leave the datacenter waiting without a reason.
+func (rc *ReconciliationContext) CheckClusterFormationTimeout() result.ReconcileResult {
+	timeout := time.Duration(rc.Datacenter.Spec.ClusterFormationTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		return result.Continue()
+	}
+
+	var seed *corev1.Pod
+	if !rc.IsInitialized() && time.Since(rc.Datacenter.Status.LastServerNodeStarted.Time) > timeout {
+		seed = rc.findFormingSeed()
+	}
+
+	if seed == nil && rc.Datacenter.GetConditionStatus(api.DatacenterClusterFormationTimeout) != corev1.ConditionTrue {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	condition := api.NewDatacenterCondition(api.DatacenterClusterFormationTimeout, corev1.ConditionFalse)
+	var message string
+	if seed != nil {
+		message = fmt.Sprintf("%s: %s", seed.Name, podStatusSummary(seed))
+		condition = api.NewDatacenterConditionWithReason(api.DatacenterClusterFormationTimeout,
+			corev1.ConditionTrue, "SeedNotReady", message)
+	}
+	if !rc.setCondition(condition) {
+		return result.Continue()
+	}
+
+	if seed != nil {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.ClusterFormationTimeout,
+			"Cluster did not form within %s, seed pod %s, see the logs of its cassandra container", timeout, message)
+	}
+
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for cluster formation timeout")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
+// findFormingSeed returns the seed pod Cassandra is being started on while no node of the cluster
+// is ready yet, or nil
+func (rc *ReconciliationContext) findFormingSeed() *corev1.Pod {
+	for _, pod := range rc.clusterPods {
+		if isServerReady(pod) {
+			return nil
+		}
+	}
+	for _, pod := range rc.dcPods {
+		if pod.Labels[api.SeedNodeLabel] == "true" && isServerStarting(pod) {
+			return pod
+		}
+	}
+	return nil
+}
+
+// podStatusSummary describes the phase of the pod and the state of its cassandra container,
+// with the termination message of its last run if it restarted
+func podStatusSummary(pod *corev1.Pod) string {
+	summary := fmt.Sprintf("phase %s", pod.Status.Phase)
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != CassandraContainerName {
+			continue
+		}
+		summary += fmt.Sprintf(", ready %t, %d restarts", status.Ready, status.RestartCount)
+		if waiting := status.State.Waiting; waiting != nil {
+			summary += fmt.Sprintf(", waiting: %s", waiting.Reason)
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			summary += fmt.Sprintf(", last terminated: %s (exit code %d)", terminated.Reason, terminated.ExitCode)
+			if message := strings.TrimSpace(terminated.Message); message != "" {
+				summary += ": " + message
+			}
+		}
+	}
+	return summary
+}
+
+// syncPodCondition sets the condition to True, with the reason and the message, while some pods
+// are affected, emitting a Warning event when it becomes True, and back to False once no pod is
+// affected anymore.
+func (rc *ReconciliationContext) syncPodCondition(conditionType api.DatacenterConditionType, pods []*corev1.Pod, reason, message, eventReason, eventMessage string) result.ReconcileResult {
+	if len(pods) == 0 && rc.Datacenter.GetConditionStatus(conditionType) != corev1.ConditionTrue {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	condition := api.NewDatacenterCondition(conditionType, corev1.ConditionFalse)
+	if len(pods) > 0 {
+		condition = api.NewDatacenterConditionWithReason(conditionType, corev1.ConditionTrue, reason, message)
+	}
+	if !rc.setCondition(condition) {
+		return result.Continue()
+	}
+
+	if len(pods) > 0 {
+		rc.Recorder.Event(rc.Datacenter, corev1.EventTypeWarning, eventReason, eventMessage)
+	}
+
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status", "condition", conditionType)
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
+// CheckImagePullFailures sets the ImagePullFailed condition when the image of a container
+// can't be pulled, so that registry or credential issues don't leave pods silently Pending.
+func (rc *ReconciliationContext) CheckImagePullFailures() result.ReconcileResult {
+	failedPods := FilterPodsWithImagePullError(rc.dcPods)
+	var reason, message string
+	if len(failedPods) > 0 {
+		status, _ := imagePullError(failedPods[0])
+		reason = status.State.Waiting.Reason
+		message = fmt.Sprintf("%s: %s", failedPods[0].Name, status.Image)
+	}
+
+	return rc.syncPodCondition(api.DatacenterImagePullFailed, failedPods, reason, message,
+		events.ImagePullFailed, fmt.Sprintf("Failed to pull image for pod %s", message))
+}
+
+// CheckOOMKilledPods sets the CassandraOOMKilled condition when the cassandra container of a
+// pod was recently killed for running out of memory, which usually means the heap or the memory
+// limit need to be reviewed.
+func (rc *ReconciliationContext) CheckOOMKilledPods() result.ReconcileResult {
+	oomKilledPods := FilterPodsWithOOMKill(rc.dcPods, time.Now())
+	var podName string
+	if len(oomKilledPods) > 0 {
+		podName = oomKilledPods[0].Name
+	}
+
+	return rc.syncPodCondition(api.DatacenterCassandraOOMKilled, oomKilledPods, "OOMKilled", podName, events.CassandraOOMKilled,
+		fmt.Sprintf("Cassandra container of pod %s was OOMKilled, review the heap size and the memory limit", podName))
+}
+
+// CheckEvictedPods sets the PodEvictedDiskPressure condition when a pod was evicted by the
+// kubelet for the lack of local storage, so that the storage available on the nodes gets reviewed.
+func (rc *ReconciliationContext) CheckEvictedPods() result.ReconcileResult {
+	evictedPods := FilterEvictedPods(rc.dcPods)
+	var message, eventMessage string
+	if len(evictedPods) > 0 {
+		pod := evictedPods[0]
+		message = fmt.Sprintf("%s: %s", pod.Name, pod.Status.Message)
+		eventMessage = fmt.Sprintf("Pod %s was evicted, review the storage available on its node: %s", pod.Name, pod.Status.Message)
+	}
+
+	return rc.syncPodCondition(api.DatacenterPodEvictedDiskPressure, evictedPods, "Evicted", message, events.PodEvicted, eventMessage)
 }
 
 // labelSeedPods iterates over all pods for a statefulset and makes sure the right number of
@@ -1327,32 +2386,24 @@ func (rc *ReconciliationContext) labelSeedPods(rackInfo *RackInformation) (int,
 
 	rackLabels := rc.Datacenter.GetRackLabels(rackInfo.RackName)
 	rackPods := FilterPodListByLabels(rc.dcPods, rackLabels)
-	sort.SliceStable(rackPods, func(i, j int) bool {
-		return rackPods[i].Name < rackPods[j].Name
-	})
-	count := 0
+	desiredSeeds := rc.desiredSeedsForRack(rackInfo)
+	currentSeeds := utils.GetPodNameSet(FilterPodListByLabel(rackPods, api.SeedNodeLabel, "true"))
+	toAdd, toRemove := SeedLabelDelta(currentSeeds, desiredSeeds)
 	for _, pod := range rackPods {
 		patch := client.MergeFrom(pod.DeepCopy())
 
 		newLabels := make(map[string]string)
 		utils.MergeMap(newLabels, pod.GetLabels())
 
-		ready := isServerReady(pod)
 		starting := isServerStarting(pod)
 
-		isSeed := ready && count < rackInfo.SeedCount
-		currentVal := pod.GetLabels()[api.SeedNodeLabel]
-		if isSeed {
-			count++
-		}
-
 		// this is the main place we label pods as seeds / not-seeds
 		// the one exception to this is the very first node we bring up
 		// in an empty cluster, and we set that node as a seed
 		// in startOneNodePerRack()
 
 		shouldUpdate := false
-		if isSeed && currentVal != "true" {
+		if toAdd[pod.Name] {
 			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.LabeledPodAsSeed,
 				"Labeled as seed node pod %s", pod.Name)
 
@@ -1360,7 +2411,7 @@ func (rc *ReconciliationContext) labelSeedPods(rackInfo *RackInformation) (int,
 			shouldUpdate = true
 		}
 		// if this pod is starting, we should leave the seed label alone
-		if !isSeed && currentVal == "true" && !starting {
+		if toRemove[pod.Name] && !starting {
 			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.UnlabeledPodAsSeed,
 				"Unlabled as seed node pod %s", pod.Name)
 
@@ -1378,7 +2429,78 @@ func (rc *ReconciliationContext) labelSeedPods(rackInfo *RackInformation) (int,
 			}
 		}
 	}
-	return count, nil
+	return len(desiredSeeds), nil
+}
+
+// isRackPaused returns true if the rack is marked as paused in the spec, in which case
+// the operator leaves its StatefulSet alone
+func (rc *ReconciliationContext) isRackPaused(rackName string) bool {
+	for _, rack := range rc.Datacenter.GetRacks() {
+		if rack.Name == rackName {
+			return rack.Paused
+		}
+	}
+	return false
+}
+
+// isRackDown returns true if none of the pods of the rack are ready, once the datacenter has been
+// initialized. Operations that only concern the other racks don't need to wait for such a rack.
+func (rc *ReconciliationContext) isRackDown(rackName string) bool {
+	if rc.Datacenter.GetConditionStatus(api.DatacenterInitialized) != corev1.ConditionTrue {
+		return false
+	}
+
+	rackPods := FilterPodListByLabels(rc.dcPods, rc.Datacenter.GetRackLabels(rackName))
+	if len(rackPods) == 0 {
+		return false
+	}
+	for _, pod := range rackPods {
+		if isServerReady(pod) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckRackDown sets the RackDown condition, naming the racks that are entirely down
+func (rc *ReconciliationContext) CheckRackDown() result.ReconcileResult {
+	downRacks := []string{}
+	for _, rackInfo := range rc.desiredRackInformation {
+		if rc.isRackDown(rackInfo.RackName) {
+			downRacks = append(downRacks, rackInfo.RackName)
+		}
+	}
+
+	if len(downRacks) == 0 && rc.Datacenter.GetConditionStatus(api.DatacenterRackDown) != corev1.ConditionTrue {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	condition := api.NewDatacenterCondition(api.DatacenterRackDown, corev1.ConditionFalse)
+	if len(downRacks) > 0 {
+		condition = api.NewDatacenterConditionWithReason(api.DatacenterRackDown, corev1.ConditionTrue,
+			"RackDown", fmt.Sprintf("Racks down: %s", strings.Join(downRacks, ", ")))
+	}
+	current, _ := rc.Datacenter.GetCondition(api.DatacenterRackDown)
+	if !rc.setCondition(condition) {
+		if current.Message == condition.Message {
+			return result.Continue()
+		}
+		// Other racks are down, the transition time is kept
+		rc.Datacenter.SetCondition(*condition)
+	}
+
+	if len(downRacks) > 0 {
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.UnhealthyDatacenter,
+			"Racks down: %s", strings.Join(downRacks, ", "))
+	}
+
+	if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for down racks")
+		return result.Error(err)
+	}
+
+	return result.Continue()
 }
 
 // GetStatefulSetForRack returns the statefulset for the rack
@@ -1403,10 +2525,15 @@ func (rc *ReconciliationContext) GetStatefulSetForRack(
 		return nil, false, err
 	}
 
+	rackDc, err := rc.datacenterForRack(nextRack.RackName, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
 	desiredStatefulSet, err := newStatefulSetForCassandraDatacenter(
 		currentStatefulSet,
 		nextRack.RackName,
-		rc.Datacenter,
+		rackDc,
 		nextRack.NodeCount,
 		false)
 	if err != nil {
@@ -1425,6 +2552,40 @@ func (rc *ReconciliationContext) GetStatefulSetForRack(
 	return desiredStatefulSet, false, nil
 }
 
+// EffectivePodSpec returns the pod spec the operator applies to the pods of the rack, that is the
+// PodTemplateSpec of the datacenter merged with everything the operator manages
+func (rc *ReconciliationContext) EffectivePodSpec(rackName string) (corev1.PodSpec, error) {
+	found := false
+	for _, rack := range rc.Datacenter.GetRacks() {
+		if rack.Name == rackName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return corev1.PodSpec{}, fmt.Errorf("rack %s is not defined in the datacenter", rackName)
+	}
+
+	var existing *appsv1.StatefulSet
+	for idx, rackInfo := range rc.desiredRackInformation {
+		if rackInfo.RackName == rackName && idx < len(rc.statefulSets) {
+			existing = rc.statefulSets[idx]
+		}
+	}
+
+	rackDc, err := rc.datacenterForRack(rackName, existing)
+	if err != nil {
+		return corev1.PodSpec{}, err
+	}
+
+	statefulSet, err := newStatefulSetForCassandraDatacenter(nil, rackName, rackDc, 0, false)
+	if err != nil {
+		return corev1.PodSpec{}, err
+	}
+
+	return statefulSet.Spec.Template.Spec, nil
+}
+
 // ReconcileNextRack ensures that the resources for a rack have been properly created
 func (rc *ReconciliationContext) ReconcileNextRack(statefulSet *appsv1.StatefulSet) error {
 
@@ -1511,6 +2672,59 @@ func (rc *ReconciliationContext) CheckDcPodDisruptionBudget() result.ReconcileRe
 	return result.Continue()
 }
 
+// CheckNetworkPolicy creates or updates the NetworkPolicy of the datacenter when CreateNetworkPolicy is enabled
+func (rc *ReconciliationContext) CheckNetworkPolicy() result.ReconcileResult {
+	dc := rc.Datacenter
+	desiredPolicy := newNetworkPolicyForDatacenter(dc)
+
+	currentPolicy := &networkingv1.NetworkPolicy{}
+	err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: desiredPolicy.Name, Namespace: desiredPolicy.Namespace}, currentPolicy)
+	if err != nil && !errors.IsNotFound(err) {
+		return result.Error(err)
+	}
+
+	if !dc.Spec.CreateNetworkPolicy {
+		// Remove the policy created before the feature was disabled, but not one created by someone else
+		if err == nil && metav1.IsControlledBy(currentPolicy, dc) {
+			rc.ReqLogger.Info("Deleting the NetworkPolicy", "networkPolicyName", currentPolicy.Name)
+			if err := rc.Client.Delete(rc.Ctx, currentPolicy); err != nil && !errors.IsNotFound(err) {
+				return result.Error(err)
+			}
+			rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.DeletedResource,
+				"Deleted NetworkPolicy %s", currentPolicy.Name)
+		}
+		return result.Continue()
+	}
+
+	if err := setControllerReference(dc, desiredPolicy, rc.Scheme); err != nil {
+		return result.Error(err)
+	}
+
+	if errors.IsNotFound(err) {
+		rc.ReqLogger.Info("Creating a new NetworkPolicy", "networkPolicyName", desiredPolicy.Name)
+		if err := rc.Client.Create(rc.Ctx, desiredPolicy); err != nil {
+			return result.Error(err)
+		}
+		rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.CreatedResource,
+			"Created NetworkPolicy %s", desiredPolicy.Name)
+		return result.Continue()
+	}
+
+	if utils.ResourcesHaveSameHash(currentPolicy, desiredPolicy) {
+		return result.Continue()
+	}
+
+	rc.ReqLogger.Info("Updating the NetworkPolicy", "networkPolicyName", desiredPolicy.Name)
+	currentPolicy.SetLabels(desiredPolicy.GetLabels())
+	currentPolicy.SetAnnotations(utils.MergeMap(currentPolicy.GetAnnotations(), desiredPolicy.GetAnnotations()))
+	currentPolicy.Spec = desiredPolicy.Spec
+	if err := rc.Client.Update(rc.Ctx, currentPolicy); err != nil {
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
 // Updates the node count on a rack (statefulset)
 func (rc *ReconciliationContext) UpdateRackNodeCount(statefulSet *appsv1.StatefulSet, newNodeCount int32) error {
 	rc.ReqLogger.Info("reconcile_racks::updateRack")
@@ -1583,6 +2797,13 @@ func (rc *ReconciliationContext) ReconcilePods(statefulSet *appsv1.StatefulSet)
 				"Update rack labels for Pod %s", podName)
 		}
 
+		if rc.Datacenter.Spec.AnnotatePodOrdinals {
+			if err := rc.annotatePodOrdinal(pod, i); err != nil {
+				rc.ReqLogger.Error(err, "Unable to update pod with ordinal annotation", "Pod", podName)
+				return err
+			}
+		}
+
 		if pod.Spec.Volumes == nil || len(pod.Spec.Volumes) == 0 || pod.Spec.Volumes[0].PersistentVolumeClaim == nil {
 			continue
 		}
@@ -1637,14 +2858,50 @@ func (rc *ReconciliationContext) ReconcilePods(statefulSet *appsv1.StatefulSet)
 			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.LabeledRackResource,
 				"Update rack labels for PersistentVolumeClaim %s", pvc.Name)
 		}
+
+		pvcAnnotations := pvc.GetAnnotations()
+		if shouldUpdate, updatedAnnotations := shouldUpdateAdditionalAnnotations(pvcAnnotations, rc.Datacenter); shouldUpdate {
+			rc.ReqLogger.Info("Updating annotations",
+				"PVC", pvc.Name,
+				"current", pvcAnnotations,
+				"desired", updatedAnnotations)
+			pvcPatch := client.MergeFrom(pvc.DeepCopy())
+			pvc.SetAnnotations(updatedAnnotations)
+
+			if err := rc.Client.Patch(rc.Ctx, pvc, pvcPatch); err != nil {
+				rc.ReqLogger.Error(
+					err,
+					"Unable to update pvc with annotations",
+					"PVC", pvc,
+				)
+			}
+		}
 	}
 
 	return nil
 }
 
-func mergeInLabelsIfDifferent(existingLabels, newLabels map[string]string) (bool, map[string]string) {
+// annotatePodOrdinal adds the StatefulSet ordinal annotation to the pod. The rack name is in the
+// rack annotation of the pod template.
+func (rc *ReconciliationContext) annotatePodOrdinal(pod *corev1.Pod, ordinal int32) error {
+	ordinalValue := strconv.Itoa(int(ordinal))
+	if pod.Annotations[api.PodOrdinalAnnotation] == ordinalValue {
+		return nil
+	}
+
+	podPatch := client.MergeFrom(pod.DeepCopy())
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, api.PodOrdinalAnnotation, ordinalValue)
+	return rc.Client.Patch(rc.Ctx, pod, podPatch)
+}
+
+func mergeInLabelsIfDifferent(existingLabels, newLabels map[string]string, removedKeys []string) (bool, map[string]string) {
 	updatedLabels := utils.MergeMap(map[string]string{}, existingLabels, newLabels)
-	if reflect.DeepEqual(existingLabels, updatedLabels) {
+	for _, key := range removedKeys {
+		if _, desired := newLabels[key]; !desired {
+			delete(updatedLabels, key)
+		}
+	}
+	if len(existingLabels) == 0 && len(updatedLabels) == 0 || reflect.DeepEqual(existingLabels, updatedLabels) {
 		return false, existingLabels
 	} else {
 		return true, updatedLabels
@@ -1656,7 +2913,15 @@ func mergeInLabelsIfDifferent(existingLabels, newLabels map[string]string) (bool
 func shouldUpdateLabelsForClusterResource(resourceLabels map[string]string, dc *api.CassandraDatacenter) (bool, map[string]string) {
 	desired := dc.GetClusterLabels()
 	oplabels.AddOperatorLabels(desired, dc)
-	return mergeInLabelsIfDifferent(resourceLabels, desired)
+	return mergeInLabelsIfDifferent(resourceLabels, desired, oplabels.RemovedAdditionalLabels(dc))
+}
+
+// shouldUpdateAdditionalAnnotations will compare the annotations passed in with the additional annotations of the dc. It
+// will return the updated map and a boolean denoting whether the resource needs to be updated with the new annotations.
+func shouldUpdateAdditionalAnnotations(resourceAnnotations map[string]string, dc *api.CassandraDatacenter) (bool, map[string]string) {
+	desired := map[string]string{}
+	oplabels.AddOperatorAnnotations(desired, dc)
+	return mergeInLabelsIfDifferent(resourceAnnotations, desired, oplabels.RemovedAdditionalAnnotations(dc))
 }
 
 // shouldUpdateLabelsForRackResource will compare the labels passed in with what the labels should be for a rack level
@@ -1664,7 +2929,7 @@ func shouldUpdateLabelsForClusterResource(resourceLabels map[string]string, dc *
 func shouldUpdateLabelsForRackResource(resourceLabels map[string]string, dc *api.CassandraDatacenter, rackName string) (bool, map[string]string) {
 	desired := dc.GetRackLabels(rackName)
 	oplabels.AddOperatorLabels(desired, dc)
-	return mergeInLabelsIfDifferent(resourceLabels, desired)
+	return mergeInLabelsIfDifferent(resourceLabels, desired, oplabels.RemovedAdditionalLabels(dc))
 }
 
 func (rc *ReconciliationContext) labelServerPodStarting(pod *corev1.Pod) error {
@@ -1757,17 +3022,110 @@ func (rc *ReconciliationContext) findStartedNotReadyNodes() (bool, error) {
 				if err := rc.labelServerPodStarted(pod); err != nil {
 					return false, err
 				}
+				if rc.Datacenter.GetConditionStatus(api.DatacenterManagementApiUnreachable) == corev1.ConditionTrue {
+					// clears the condition now that the node is back
+					if err := rc.checkManagementApiReachable(pod); err != nil && !isManagementApiUnreachable(err) {
+						return false, err
+					}
+				}
 				return false, nil
 			}
+
+			// A started node can stay not ready because its management API is unreachable, which
+			// is reported the same way as when a node is about to be started
+			if err := rc.checkManagementApiReachable(pod); err != nil && !isManagementApiUnreachable(err) {
+				return false, err
+			}
 		}
 	}
 	return false, nil
 }
 
+// managementApiUnreachableError is returned when a lifecycle command was not issued
+// because the management API of the pod could not be reached
+type managementApiUnreachableError struct {
+	podName string
+	err     error
+}
+
+func (e *managementApiUnreachableError) Error() string {
+	return fmt.Sprintf("management API of pod %s is unreachable: %v", e.podName, e.err)
+}
+
+// managementApiStartupGrace is how long after the start of the cassandra container a failing
+// management API is considered to be still starting rather than unreachable
+const managementApiStartupGrace = 2 * time.Minute
+
+// isManagementApiStarting returns true if the cassandra container of the pod started recently
+// enough that its management API may not be listening yet
+func isManagementApiStarting(pod *corev1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == CassandraContainerName && status.State.Running != nil {
+			return time.Since(status.State.Running.StartedAt.Time) < managementApiStartupGrace
+		}
+	}
+	return false
+}
+
+// checkManagementApiReachable probes the management API of the pod and keeps the
+// ManagementApiUnreachable condition up to date. A pod that is still starting is not reported,
+// so that normal startups don't flag the datacenter.
+func (rc *ReconciliationContext) checkManagementApiReachable(pod *corev1.Pod) error {
+	probeErr := rc.NodeMgmtClient.CallLivenessProbeEndpoint(pod)
+	if probeErr == nil && rc.Datacenter.GetConditionStatus(api.DatacenterManagementApiUnreachable) != corev1.ConditionTrue {
+		return nil
+	}
+	if probeErr != nil && isManagementApiStarting(pod) {
+		rc.ReqLogger.Info("Management API is not reachable yet, the pod is still starting", "pod", pod.Name)
+		return &managementApiUnreachableError{podName: pod.Name, err: probeErr}
+	}
+
+	dcPatch := client.MergeFrom(rc.Datacenter.DeepCopy())
+	condition := api.NewDatacenterCondition(api.DatacenterManagementApiUnreachable, corev1.ConditionFalse)
+	if probeErr != nil {
+		condition = api.NewDatacenterConditionWithReason(api.DatacenterManagementApiUnreachable,
+			corev1.ConditionTrue, "ProbeFailed", fmt.Sprintf("%s: %v", pod.Name, probeErr))
+	}
+	if rc.setCondition(condition) {
+		if probeErr != nil {
+			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeWarning, events.ManagementApiUnreachable,
+				"Management API of pod %s is unreachable: %v", pod.Name, probeErr)
+		}
+		if err := rc.Client.Status().Patch(rc.Ctx, rc.Datacenter, dcPatch); err != nil {
+			rc.ReqLogger.Error(err, "error patching datacenter status for management API reachability")
+			return err
+		}
+	}
+
+	if probeErr != nil {
+		return &managementApiUnreachableError{podName: pod.Name, err: probeErr}
+	}
+	return nil
+}
+
+func isManagementApiUnreachable(err error) bool {
+	_, ok := err.(*managementApiUnreachableError)
+	return ok
+}
+
+// startFailureResult requeues when a node was not started because its management API
+// could not be reached, instead of failing the whole reconcile
+func startFailureResult(err error) result.ReconcileResult {
+	if isManagementApiUnreachable(err) {
+		return result.RequeueSoon(10)
+	}
+	return result.Error(err)
+}
+
 func (rc *ReconciliationContext) startCassandra(endpointData httphelper.CassMetadataEndpoints, pod *corev1.Pod) error {
 	dc := rc.Datacenter
 	mgmtClient := rc.NodeMgmtClient
 
+	// Don't delete the pod below just because its management API can't be reached yet
+	if err := rc.checkManagementApiReachable(pod); err != nil {
+		return err
+	}
+
 	// Are we replacing this node?
 	shouldReplacePod := utils.IndexOfString(dc.Status.NodeReplacements, pod.Name) > -1
 
@@ -1964,59 +3322,207 @@ func isServerReadyToStart(pod *corev1.Pod) bool {
 	return pod.Labels[api.CassNodeState] == stateReadyToStart
 }
 
-func didServerLoseReadiness(pod *corev1.Pod) bool {
-	if pod.Labels[api.CassNodeState] == stateStarted {
-		return !isServerReady(pod)
+func didServerLoseReadiness(pod *corev1.Pod) bool {
+	if pod.Labels[api.CassNodeState] == stateStarted {
+		return !isServerReady(pod)
+	}
+	return false
+}
+
+func isServerReady(pod *corev1.Pod) bool {
+	status := pod.Status
+	statuses := status.ContainerStatuses
+	for _, status := range statuses {
+		if status.Name != "cassandra" {
+			continue
+		}
+		return status.Ready
+	}
+	return false
+}
+
+func (rc *ReconciliationContext) refreshSeeds() error {
+	rc.ReqLogger.Info("reconcile_racks::refreshSeeds")
+	if rc.Datacenter.Spec.Stopped || rc.Datacenter.GetDeletionTimestamp() != nil {
+		rc.ReqLogger.Info("cluster is stopped/deleted, skipping refreshSeeds")
+		return nil
+	}
+
+	startedPods := FilterPodListByCassNodeState(rc.clusterPods, stateStarted)
+
+	for _, pod := range startedPods {
+		if err := rc.NodeMgmtClient.CallReloadSeedsEndpoint(pod); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rc *ReconciliationContext) listPods(selector map[string]string) (*corev1.PodList, error) {
+	rc.ReqLogger.Info("reconcile_racks::listPods")
+
+	listOptions := &client.ListOptions{
+		Namespace:     rc.Datacenter.Namespace,
+		LabelSelector: labels.SelectorFromSet(selector),
+	}
+
+	podList := &corev1.PodList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: "v1",
+		},
+	}
+
+	return podList, rc.Client.List(rc.Ctx, podList, listOptions)
+}
+
+// CheckSuperuserSecretChange requests a rolling restart when the superuser secret has been modified
+// since it was last seen, if RestartOnCredentialChange is enabled.
+func (rc *ReconciliationContext) CheckSuperuserSecretChange() result.ReconcileResult {
+	dc := rc.Datacenter
+	logger := rc.ReqLogger
+
+	if !dc.Spec.RestartOnCredentialChange {
+		return result.Continue()
+	}
+
+	secret, err := rc.retrieveSuperuserSecret()
+	if err != nil {
+		// The secret is validated and created by earlier steps
+		logger.Error(err, "error retrieving superuser secret to check for changes")
+		return result.Continue()
+	}
+
+	lastVersion := dc.Status.SuperuserSecretResourceVersion
+	if lastVersion == secret.ResourceVersion {
+		return result.Continue()
+	}
+
+	dcPatch := client.MergeFrom(dc.DeepCopy())
+	dc.Status.SuperuserSecretResourceVersion = secret.ResourceVersion
+
+	// Nothing to restart the first time the secret is seen
+	if lastVersion != "" {
+		rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.RestartingCassandra,
+			"Superuser secret %s was modified, doing a rolling restart", secret.Name)
+		dc.Status.LastRollingRestart = metav1.Now()
+		_ = rc.setCondition(
+			api.NewDatacenterCondition(api.DatacenterRollingRestart, corev1.ConditionTrue))
+	}
+
+	if err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch); err != nil {
+		logger.Error(err, "error patching datacenter status for superuser secret change")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
+// keystoreSyncDelay is how long the reload of the SSL contexts waits after a change of the keystore
+// secret, for the kubelet to update the secret mounted in the pods
+var keystoreSyncDelay = 2 * time.Minute
+
+// CheckKeystoreSecretChange applies the certificates rotated in the keystore secret, if HotReloadTLS
+// is enabled. The SSL contexts of the nodes are reloaded when their management API supports it,
+// otherwise a rolling restart is requested.
+func (rc *ReconciliationContext) CheckKeystoreSecretChange() result.ReconcileResult {
+	dc := rc.Datacenter
+	logger := rc.ReqLogger
+
+	if !dc.Spec.HotReloadTLS {
+		return result.Continue()
+	}
+
+	secret, err := rc.retrieveSecret(rc.keystoreSecret())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return result.Continue()
+		}
+		logger.Error(err, "error retrieving keystore secret to check for changes")
+		return result.Error(err)
+	}
+
+	if lastVersion := dc.Status.KeystoreSecretResourceVersion; lastVersion != secret.ResourceVersion {
+		dcPatch := client.MergeFrom(dc.DeepCopy())
+		dc.Status.KeystoreSecretResourceVersion = secret.ResourceVersion
+
+		// Nothing to reload the first time the secret is seen. Another change before the reload
+		// delays it again.
+		if lastVersion != "" {
+			dc.Status.KeystoreSecretReloadTime = metav1.NewTime(time.Now().Add(keystoreSyncDelay))
+		}
+
+		if err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch); err != nil {
+			logger.Error(err, "error patching datacenter status for keystore secret change")
+			return result.Error(err)
+		}
+	}
+
+	reloadTime := dc.Status.KeystoreSecretReloadTime
+	if reloadTime.IsZero() {
+		return result.Continue()
 	}
-	return false
-}
 
-func isServerReady(pod *corev1.Pod) bool {
-	status := pod.Status
-	statuses := status.ContainerStatuses
-	for _, status := range statuses {
-		if status.Name != "cassandra" {
-			continue
-		}
-		return status.Ready
+	// Reloading before the kubelet updated the mounted secret would load the old keystore again
+	if remaining := time.Until(reloadTime.Time); remaining > 0 {
+		logger.Info("Waiting for the keystore secret to be updated in the pods", "remaining", remaining)
+		return result.RequeueSoon(int(remaining.Seconds()) + 1)
 	}
-	return false
-}
 
-func (rc *ReconciliationContext) refreshSeeds() error {
-	rc.ReqLogger.Info("reconcile_racks::refreshSeeds")
-	if rc.Datacenter.Spec.Stopped || rc.Datacenter.GetDeletionTimestamp() != nil {
-		rc.ReqLogger.Info("cluster is stopped/deleted, skipping refreshSeeds")
-		return nil
+	dcPatch := client.MergeFrom(dc.DeepCopy())
+	dc.Status.KeystoreSecretReloadTime = metav1.Time{}
+
+	reloaded, err := rc.reloadSslContexts()
+	if err != nil {
+		logger.Error(err, "error reloading the SSL contexts")
+		return result.Error(err)
 	}
 
-	startedPods := FilterPodListByCassNodeState(rc.clusterPods, stateStarted)
+	if reloaded {
+		rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.ReloadedTLS,
+			"Keystore secret %s was modified, reloaded the SSL contexts", secret.Name)
+	} else {
+		rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.RestartingCassandra,
+			"Keystore secret %s was modified and the SSL contexts can't be reloaded, doing a rolling restart", secret.Name)
+		dc.Status.LastRollingRestart = metav1.Now()
+		_ = rc.setCondition(
+			api.NewDatacenterCondition(api.DatacenterRollingRestart, corev1.ConditionTrue))
+	}
 
-	for _, pod := range startedPods {
-		if err := rc.NodeMgmtClient.CallReloadSeedsEndpoint(pod); err != nil {
-			return err
-		}
+	if err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch); err != nil {
+		logger.Error(err, "error patching datacenter status for keystore secret change")
+		return result.Error(err)
 	}
 
-	return nil
+	return result.Continue()
 }
 
-func (rc *ReconciliationContext) listPods(selector map[string]string) (*corev1.PodList, error) {
-	rc.ReqLogger.Info("reconcile_racks::listPods")
-
-	listOptions := &client.ListOptions{
-		Namespace:     rc.Datacenter.Namespace,
-		LabelSelector: labels.SelectorFromSet(selector),
+// reloadSslContexts reloads the SSL contexts of the started nodes. Nothing is reloaded and false is
+// returned if the management API of one of the nodes doesn't support it.
+func (rc *ReconciliationContext) reloadSslContexts() (bool, error) {
+	var pods []*corev1.Pod
+	for _, pod := range rc.dcPods {
+		if !isServerStarted(pod) || !isMgmtApiRunning(pod) {
+			continue
+		}
+		features, err := rc.NodeMgmtClient.FeatureSet(pod)
+		if err != nil {
+			return false, err
+		}
+		if !features.Supports(httphelper.ReloadSsl) {
+			return false, nil
+		}
+		pods = append(pods, pod)
 	}
 
-	podList := &corev1.PodList{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Pod",
-			APIVersion: "v1",
-		},
+	for _, pod := range pods {
+		if err := rc.NodeMgmtClient.CallReloadSslEndpoint(pod); err != nil {
+			return false, err
+		}
 	}
 
-	return podList, rc.Client.List(rc.Ctx, podList, listOptions)
+	return true, nil
 }
 
 func (rc *ReconciliationContext) CheckRollingRestart() result.ReconcileResult {
@@ -2043,13 +3549,34 @@ func (rc *ReconciliationContext) CheckRollingRestart() result.ReconcileResult {
 		}
 	}
 
+	if value, found := dc.Annotations[api.RollingRestartAnnotation]; found && isNewerAnnotationRequest(value, dc.Status.LastRollingRestartAnnotation) {
+		rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.RestartingCassandra,
+			"Rolling restart requested by the %s annotation", api.RollingRestartAnnotation)
+
+		dcPatch := client.MergeFrom(dc.DeepCopy())
+		dc.Status.LastRollingRestartAnnotation = value
+		dc.Status.LastRollingRestart = metav1.Now()
+		_ = rc.setCondition(
+			api.NewDatacenterCondition(api.DatacenterRollingRestart, corev1.ConditionTrue))
+		if err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch); err != nil {
+			logger.Error(err, "error patching datacenter status for rolling restart annotation")
+			return result.Error(err)
+		}
+	}
+
+	// The pods are restarted one at a time, rack by rack. The previous steps wait for each
+	// restarted pod to be ready before getting here again.
 	cutoff := &dc.Status.LastRollingRestart
-	for _, pod := range rc.dcPods {
+	for _, pod := range rc.podsInRackOrder() {
 		podStartTime := pod.GetCreationTimestamp()
 		if podStartTime.Before(cutoff) {
 			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.RestartingCassandra,
 				"Restarting Cassandra for pod %s", pod.Name)
 
+			if err := rc.setRollingOperation(api.RollingOperationRestart, pod.Name); err != nil {
+				return result.Error(err)
+			}
+
 			// drain the node
 			err := rc.NodeMgmtClient.CallDrainEndpoint(pod)
 			if err != nil {
@@ -2069,6 +3596,47 @@ func (rc *ReconciliationContext) CheckRollingRestart() result.ReconcileResult {
 	return result.Continue()
 }
 
+// isNewerAnnotationRequest returns true if the value of a request annotation, such as rolling-restart,
+// is a request that was not processed yet. Timestamps are compared, so that restoring an older value is ignored.
+func isNewerAnnotationRequest(value, lastValue string) bool {
+	if value == lastValue {
+		return false
+	}
+	requested, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return true
+	}
+	lastRequested, err := time.Parse(time.RFC3339, lastValue)
+	if err != nil {
+		return true
+	}
+	return requested.After(lastRequested)
+}
+
+// podsInRackOrder returns the pods of the datacenter ordered like the racks, then by name. The pods
+// of unknown racks come last.
+func (rc *ReconciliationContext) podsInRackOrder() []*corev1.Pod {
+	rackIndexes := map[string]int{}
+	for idx, rack := range rc.Datacenter.GetRacks() {
+		rackIndexes[rack.Name] = idx
+	}
+	rackIndex := func(pod *corev1.Pod) int {
+		if idx, found := rackIndexes[pod.Labels[api.RackLabel]]; found {
+			return idx
+		}
+		return len(rackIndexes)
+	}
+
+	pods := append([]*corev1.Pod{}, rc.dcPods...)
+	sort.SliceStable(pods, func(i, j int) bool {
+		if rackI, rackJ := rackIndex(pods[i]), rackIndex(pods[j]); rackI != rackJ {
+			return rackI < rackJ
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	return pods
+}
+
 func (rc *ReconciliationContext) setCondition(condition *api.DatacenterCondition) bool {
 	dc := rc.Datacenter
 	if dc.GetConditionStatus(condition.Type) != condition.Status {
@@ -2080,6 +3648,73 @@ func (rc *ReconciliationContext) setCondition(condition *api.DatacenterCondition
 	return false
 }
 
+// CheckClientConfigSecret creates or updates the secret with the configuration for CQL clients once
+// the datacenter is ready, if GenerateClientConfigSecret is enabled.
+func (rc *ReconciliationContext) CheckClientConfigSecret() result.ReconcileResult {
+	dc := rc.Datacenter
+	logger := rc.ReqLogger
+
+	if !dc.Spec.GenerateClientConfigSecret || dc.GetConditionStatus(api.DatacenterReady) != corev1.ConditionTrue {
+		return result.Continue()
+	}
+
+	superuserSecret, err := rc.retrieveSuperuserSecret()
+	if err != nil {
+		logger.Error(err, "error retrieving superuser secret for the client config secret")
+		return result.Error(err)
+	}
+
+	var caCert []byte
+	clientEncryption, err := dc.ClientEncryptionEnabled()
+	if err != nil {
+		return result.Error(err)
+	}
+	if clientEncryption {
+		caSecret, err := rc.retrieveSecret(rc.keystoreCASecret())
+		if err != nil {
+			logger.Error(err, "error retrieving CA secret for the client config secret")
+			return result.Error(err)
+		}
+		caCert = caSecret.Data["cert"]
+	}
+
+	desiredSecret := buildClientConfigSecret(dc, superuserSecret, caCert)
+	if err := setControllerReference(dc, desiredSecret, rc.Scheme); err != nil {
+		return result.Error(err)
+	}
+
+	currentSecret, err := rc.retrieveSecret(types.NamespacedName{Name: desiredSecret.Name, Namespace: desiredSecret.Namespace})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return result.Error(err)
+		}
+
+		logger.Info("Creating the client config secret", "secretName", desiredSecret.Name)
+		if err := rc.Client.Create(rc.Ctx, desiredSecret); err != nil {
+			logger.Error(err, "error creating the client config secret")
+			return result.Error(err)
+		}
+
+		rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.CreatedResource,
+			"Created client config secret %s", desiredSecret.Name)
+		return result.Continue()
+	}
+
+	if utils.ResourcesHaveSameHash(currentSecret, desiredSecret) {
+		return result.Continue()
+	}
+
+	logger.Info("Updating the client config secret", "secretName", desiredSecret.Name)
+	currentSecret.Data = desiredSecret.Data
+	currentSecret.Annotations = utils.MergeMap(map[string]string{}, currentSecret.Annotations, desiredSecret.Annotations)
+	if err := rc.Client.Update(rc.Ctx, currentSecret); err != nil {
+		logger.Error(err, "error updating the client config secret")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
 func (rc *ReconciliationContext) CheckConditionInitializedAndReady() result.ReconcileResult {
 	dc := rc.Datacenter
 	dcPatch := client.MergeFrom(dc.DeepCopy())
@@ -2219,6 +3854,52 @@ func (rc *ReconciliationContext) createTask(command taskapi.CassandraCommand) er
 	return rc.Client.Status().Patch(rc.Ctx, dc, dcPatch)
 }
 
+// CheckCleanupAnnotation starts a cleanup task when the cleanup annotation of the datacenter holds a
+// request that was not processed yet. The task runs the cleanup one node at a time.
+func (rc *ReconciliationContext) CheckCleanupAnnotation() result.ReconcileResult {
+	dc := rc.Datacenter
+
+	// The cleanup that follows a scale up tracks its own task
+	if dc.GetConditionStatus(api.DatacenterScalingUp) == corev1.ConditionTrue {
+		return result.Continue()
+	}
+
+	task, err := rc.findActiveTask(taskapi.CommandCleanup)
+	if err != nil {
+		return result.Error(err)
+	}
+	if task != nil {
+		if task.Status.CompletionTime == nil {
+			return result.Continue()
+		}
+		if res := rc.activeTaskCompleted(task); res.Completed() {
+			return res
+		}
+	}
+
+	value, found := dc.Annotations[api.CleanupAnnotation]
+	if !found || !isNewerAnnotationRequest(value, dc.Status.LastCleanupAnnotation) {
+		return result.Continue()
+	}
+
+	rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.CleaningUpNodes,
+		"Cleanup requested by the %s annotation", api.CleanupAnnotation)
+
+	if err := rc.createTask(taskapi.CommandCleanup); err != nil {
+		rc.ReqLogger.Error(err, "error creating the cleanup task")
+		return result.Error(err)
+	}
+
+	dcPatch := client.MergeFrom(dc.DeepCopy())
+	dc.Status.LastCleanupAnnotation = value
+	if err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch); err != nil {
+		rc.ReqLogger.Error(err, "error patching datacenter status for cleanup annotation")
+		return result.Error(err)
+	}
+
+	return result.Continue()
+}
+
 func (rc *ReconciliationContext) CheckClearActionConditions() result.ReconcileResult {
 	dc := rc.Datacenter
 	logger := rc.ReqLogger
@@ -2237,16 +3918,21 @@ func (rc *ReconciliationContext) CheckClearActionConditions() result.ReconcileRe
 		api.DatacenterValid,
 	}
 	updated := false
+	scaledUp := false
 
 	// Explicitly handle scaling up here because we want to run a cleanup afterwards
 	if dc.GetConditionStatus(api.DatacenterScalingUp) == corev1.ConditionTrue {
 		// Call the first node with cleanup, wait until it has finished and then move on to the next pod..
-		if res := rc.cleanupAfterScaling(); res.Completed() {
-			return res
+		if dc.ShouldCleanupAfterScaleUp() {
+			if res := rc.cleanupAfterScaling(); res.Completed() {
+				return res
+			}
 		}
 
 		updated = rc.setCondition(
 			api.NewDatacenterCondition(api.DatacenterScalingUp, corev1.ConditionFalse)) || updated
+		dc.Status.LastScaleOperation = metav1.Now()
+		scaledUp = true
 	}
 
 	// Make sure that the stopped condition matches the spec, because logically
@@ -2270,6 +3956,11 @@ func (rc *ReconciliationContext) CheckClearActionConditions() result.ReconcileRe
 			api.NewDatacenterCondition(conditionType, corev1.ConditionTrue)) || updated
 	}
 
+	if dc.RollingOperationInProgress() {
+		dc.Status.RollingOperation = nil
+		updated = true
+	}
+
 	if updated {
 		err := rc.Client.Status().Patch(rc.Ctx, dc, dcPatch)
 		if err != nil {
@@ -2277,6 +3968,11 @@ func (rc *ReconciliationContext) CheckClearActionConditions() result.ReconcileRe
 			return result.Error(err)
 		}
 
+		if scaledUp {
+			rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.ScaledUpDatacenter,
+				"Finished scaling up the datacenter to %d nodes", dc.Spec.Size)
+		}
+
 		// There may have been changes to the CassandraDatacenter resource that we ignored
 		// while executing some action on the cluster. For example, a user may have
 		// requested to scale up the node count while we were in the middle of a rolling
@@ -2381,14 +4077,50 @@ func (rc *ReconciliationContext) ReconcileAllRacks() (reconcile.Result, error) {
 		return recResult.Output()
 	}
 
+	if recResult := rc.CheckConfigHash(); recResult.Completed() {
+		return recResult.Output()
+	}
+
 	if recResult := rc.CheckRackCreation(); recResult.Completed() {
 		return recResult.Output()
 	}
 
+	if recResult := rc.CheckManagedObjectCounts(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckRackStatuses(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckUnhealthyPods(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckClockSkew(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckRackDown(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckProgressPercent(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckLabelMigrations(); recResult.Completed() {
+		return recResult.Output()
+	}
+
 	if recResult := rc.CheckRackLabels(); recResult.Completed() {
 		return recResult.Output()
 	}
 
+	if recResult := rc.CheckRackOwnerReferences(); recResult.Completed() {
+		return recResult.Output()
+	}
+
 	if recResult := rc.CheckDecommissioningNodes(endpointData); recResult.Completed() {
 		return recResult.Output()
 	}
@@ -2423,6 +4155,54 @@ func (rc *ReconciliationContext) ReconcileAllRacks() (reconcile.Result, error) {
 		return recResult.Output()
 	}
 
+	if recResult := rc.CheckReleasedVolumes(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckRemovedRackPVCs(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckVolumeExpansion(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckStalePVCSelectedNodes(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckSchedulingDeadlocks(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckUnschedulableRacks(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckImagePullFailures(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckOOMKilledPods(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckEvictedPods(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckConfigBuilderFailures(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckConfigBuilderFeatures(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckClusterFormationTimeout(); recResult.Completed() {
+		return recResult.Output()
+	}
+
 	if recResult := rc.CheckPodsReady(endpointData); recResult.Completed() {
 		return recResult.Output()
 	}
@@ -2435,6 +4215,14 @@ func (rc *ReconciliationContext) ReconcileAllRacks() (reconcile.Result, error) {
 		return recResult.Output()
 	}
 
+	if recResult := rc.CheckSuperuserSecretChange(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckKeystoreSecretChange(); recResult.Completed() {
+		return recResult.Output()
+	}
+
 	if recResult := rc.CheckRollingRestart(); recResult.Completed() {
 		return recResult.Output()
 	}
@@ -2443,6 +4231,14 @@ func (rc *ReconciliationContext) ReconcileAllRacks() (reconcile.Result, error) {
 		return recResult.Output()
 	}
 
+	if recResult := rc.CheckNetworkPolicy(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckReplaceAddressRemoval(endpointData); recResult.Completed() {
+		return recResult.Output()
+	}
+
 	if recResult := rc.CheckRackPodTemplate(); recResult.Completed() {
 		return recResult.Output()
 	}
@@ -2451,6 +4247,10 @@ func (rc *ReconciliationContext) ReconcileAllRacks() (reconcile.Result, error) {
 		return recResult.Output()
 	}
 
+	if recResult := rc.CheckAdditionalMetadataKeys(); recResult.Completed() {
+		return recResult.Output()
+	}
+
 	if recResult := rc.CreateUsers(); recResult.Completed() {
 		return recResult.Output()
 	}
@@ -2463,6 +4263,18 @@ func (rc *ReconciliationContext) ReconcileAllRacks() (reconcile.Result, error) {
 		return recResult.Output()
 	}
 
+	if recResult := rc.CheckCleanupAnnotation(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckClientConfigSecret(); recResult.Completed() {
+		return recResult.Output()
+	}
+
+	if recResult := rc.CheckPodRebalancing(); recResult.Completed() {
+		return recResult.Output()
+	}
+
 	if recResult := rc.CheckFullQueryLogging(); recResult.Completed() {
 		return recResult.Output()
 	}
@@ -4,9 +4,12 @@ package reconciliation
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/k8ssandra/cass-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 )
@@ -39,6 +42,30 @@ func FilterPodListByLabel(pods []*corev1.Pod, labelName string, labelVal string)
 	return FilterPodListByLabels(pods, labels)
 }
 
+// GroupPodsByLabel groups the pods by the value of the given label. Pods
+// without the label are left out.
+func GroupPodsByLabel(pods []*corev1.Pod, labelName string) map[string][]*corev1.Pod {
+	grouped := make(map[string][]*corev1.Pod)
+	for _, p := range pods {
+		if val, ok := p.Labels[labelName]; ok {
+			grouped[val] = append(grouped[val], p)
+		}
+	}
+	return grouped
+}
+
+// RackReplicaDiff returns how many ready pods the rack is missing to reach
+// the desired node count. It is negative when the rack has too many ready pods.
+func RackReplicaDiff(desired int, rackPods []*corev1.Pod) int {
+	ready := 0
+	for _, p := range rackPods {
+		if isServerReady(p) {
+			ready++
+		}
+	}
+	return desired - ready
+}
+
 func FilterPodListByCassNodeState(pods []*corev1.Pod, state string) []*corev1.Pod {
 	filtered := []*corev1.Pod{}
 	for _, p := range pods {
@@ -49,10 +76,106 @@ func FilterPodListByCassNodeState(pods []*corev1.Pod, state string) []*corev1.Po
 	return filtered
 }
 
+// imagePullError returns the waiting status of the first container of the pod
+// whose image can't be pulled
+func imagePullError(pod *corev1.Pod) (corev1.ContainerStatus, bool) {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil &&
+			(waiting.Reason == "ImagePullBackOff" || waiting.Reason == "ErrImagePull") {
+			return status, true
+		}
+	}
+	return corev1.ContainerStatus{}, false
+}
+
+// FilterPodsWithImagePullError returns the pods that have a container in
+// ImagePullBackOff or ErrImagePull.
+func FilterPodsWithImagePullError(pods []*corev1.Pod) []*corev1.Pod {
+	filtered := []*corev1.Pod{}
+	for _, p := range pods {
+		if _, failed := imagePullError(p); failed {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// FilterPodsInCrashLoop returns the pods that have a container in CrashLoopBackOff.
+func FilterPodsInCrashLoop(pods []*corev1.Pod) []*corev1.Pod {
+	filtered := []*corev1.Pod{}
+	for _, p := range pods {
+		statuses := append([]corev1.ContainerStatus{}, p.Status.InitContainerStatuses...)
+		statuses = append(statuses, p.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if waiting := status.State.Waiting; waiting != nil && waiting.Reason == "CrashLoopBackOff" {
+				filtered = append(filtered, p)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// oomKillWindow is how long an OOMKill of a cassandra container that was restarted since is reported
+const oomKillWindow = 15 * time.Minute
+
+// FilterPodsWithOOMKill returns the pods whose cassandra container is terminated with OOMKilled,
+// or last was within oomKillWindow before now.
+func FilterPodsWithOOMKill(pods []*corev1.Pod, now time.Time) []*corev1.Pod {
+	filtered := []*corev1.Pod{}
+	for _, p := range pods {
+		for _, status := range p.Status.ContainerStatuses {
+			if status.Name != CassandraContainerName {
+				continue
+			}
+			last := status.LastTerminationState.Terminated
+			if isOOMKilled(status.State.Terminated) ||
+				(isOOMKilled(last) && now.Sub(last.FinishedAt.Time) < oomKillWindow) {
+				filtered = append(filtered, p)
+			}
+		}
+	}
+	return filtered
+}
+
+// diskPressureEvictionMessages are the parts of the messages of the kubelet evictions caused by
+// the lack of local storage
+var diskPressureEvictionMessages = []string{"ephemeral-storage", "ephemeral local storage", "DiskPressure"}
+
+// FilterEvictedPods returns the pods that failed because the kubelet evicted them when their node
+// was under DiskPressure. The evictions for other resources, such as memory, are left out.
+func FilterEvictedPods(pods []*corev1.Pod) []*corev1.Pod {
+	filtered := []*corev1.Pod{}
+	for _, p := range pods {
+		if p.Status.Phase != corev1.PodFailed || p.Status.Reason != "Evicted" {
+			continue
+		}
+		for _, message := range diskPressureEvictionMessages {
+			if strings.Contains(p.Status.Message, message) {
+				filtered = append(filtered, p)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func isOOMKilled(terminated *corev1.ContainerStateTerminated) bool {
+	return terminated != nil && terminated.Reason == "OOMKilled"
+}
+
 func ListAllStartedPods(pods []*corev1.Pod) []*corev1.Pod {
 	return FilterPodListByCassNodeState(pods, stateStarted)
 }
 
+// SeedLabelDelta returns the pods that need the seed label added, and the ones that
+// need it removed, to go from the current seeds to the desired ones.
+func SeedLabelDelta(currentSeeds, desiredSeeds utils.StringSet) (toAdd, toRemove utils.StringSet) {
+	return utils.SubtractStringSet(desiredSeeds, currentSeeds), utils.SubtractStringSet(currentSeeds, desiredSeeds)
+}
+
 func FindIpForHostId(endpointData httphelper.CassMetadataEndpoints, hostId string) (string, error) {
 	// If there are no nodes to ask, then of course we will not find an IP. We
 	// treat this as an error since we have not way to determine the mapping.
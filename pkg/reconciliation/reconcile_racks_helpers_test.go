@@ -3,6 +3,7 @@ package reconciliation
 import (
 	"testing"
 
+	"github.com/k8ssandra/cass-operator/pkg/utils"
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
@@ -65,3 +66,16 @@ func TestPodPrtsFromPodList(t *testing.T) {
 	assert.ElementsMatch(t, expectedNames, actualNames)
 
 }
+
+func TestSeedLabelDelta(t *testing.T) {
+	current := utils.StringSet{"pod-0": true, "pod-1": true, "pod-2": true}
+	desired := utils.StringSet{"pod-1": true, "pod-2": true, "pod-3": true}
+
+	toAdd, toRemove := SeedLabelDelta(current, desired)
+	assert.Equal(t, utils.StringSet{"pod-3": true}, toAdd)
+	assert.Equal(t, utils.StringSet{"pod-0": true}, toRemove)
+
+	toAdd, toRemove = SeedLabelDelta(desired, desired)
+	assert.Empty(t, toAdd)
+	assert.Empty(t, toRemove)
+}
@@ -15,7 +15,9 @@ import (
 
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	taskapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/k8ssandra/cass-operator/pkg/events"
 	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	"github.com/k8ssandra/cass-operator/pkg/images"
 	"github.com/k8ssandra/cass-operator/pkg/internal/result"
 	"github.com/k8ssandra/cass-operator/pkg/mocks"
 	"github.com/k8ssandra/cass-operator/pkg/oplabels"
@@ -24,11 +26,17 @@ import (
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -286,6 +294,57 @@ func TestCheckRackPodTemplate_SetControllerRefOnStatefulSet(t *testing.T) {
 	assert.Equal(t, rc.statefulSets[0].Name, actualObject.GetName())
 }
 
+func TestCheckRackPodTemplate_PodManagementPolicy(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{
+		{Name: "rack1", Zone: "zone-1"},
+	}
+	assert.NoError(rc.CalculateRackInformation())
+	assert.False(rc.CheckRackCreation().Completed())
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+	assert.Equal(appsv1.ParallelPodManagement, rc.statefulSets[0].Spec.PodManagementPolicy)
+
+	// The policy can't be updated, the StatefulSet is deleted to be recreated with it
+	rc.Datacenter.Spec.PodManagementPolicy = "OrderedReady"
+	assert.Equal(result.RequeueSoon(10), rc.CheckRackPodTemplate())
+
+	sts := &appsv1.StatefulSet{}
+	err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: rc.statefulSets[0].Name, Namespace: rc.statefulSets[0].Namespace}, sts)
+	assert.True(errors.IsNotFound(err))
+
+	rc.statefulSets = nil
+	assert.NoError(rc.CalculateRackInformation())
+	assert.False(rc.CheckRackCreation().Completed())
+	assert.Equal(appsv1.OrderedReadyPodManagement, rc.statefulSets[0].Spec.PodManagementPolicy)
+}
+
+func TestFindStartingNodes_OneNodeBootstrapsAtATime(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	// With a Parallel policy, all the pods of a rack can be up and ready to start
+	starting := makeMockReadyStartedPod()
+	starting.Name = "pod-1"
+	starting.Labels[api.CassNodeState] = stateStarting
+	starting.Status.ContainerStatuses[0].Ready = false
+	waiting := makeMockReadyStartedPod()
+	waiting.Name = "pod-2"
+	waiting.Labels[api.CassNodeState] = stateReadyToStart
+	waiting.Status.ContainerStatuses[0].Ready = false
+	rc.clusterPods = []*corev1.Pod{starting, waiting}
+
+	// CheckPodsReady requeues instead of starting another node while one is starting
+	nodeIsStarting, nodeStarted, err := rc.findStartingNodes()
+	assert.NoError(err)
+	assert.True(nodeIsStarting)
+	assert.False(nodeStarted)
+	assert.Equal(stateReadyToStart, waiting.Labels[api.CassNodeState])
+}
+
 // Disabled due to a bug in the controller-runtime: https://github.com/kubernetes-sigs/controller-runtime/issues/1832
 // func TestCheckRackPodTemplate_CanaryUpgrade(t *testing.T) {
 // 	rc, _, cleanpMockSrc := setupTest()
@@ -453,6 +512,45 @@ func TestReconcilePods_WithVolumes(t *testing.T) {
 	assert.NoErrorf(t, err, "Should not have returned an error")
 }
 
+func TestReconcilePods_AnnotatePodOrdinals(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.AnnotatePodOrdinals = true
+
+	statefulSet, err := newStatefulSetForCassandraDatacenter(
+		nil,
+		"default",
+		rc.Datacenter,
+		2,
+		false)
+	assert.NoErrorf(t, err, "error occurred creating statefulset")
+	statefulSet.Status.Replicas = int32(2)
+
+	trackObjects := []runtime.Object{}
+	for i := int32(0); i < statefulSet.Status.Replicas; i++ {
+		trackObjects = append(trackObjects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      getStatefulSetPodNameForIdx(statefulSet, i),
+				Namespace: statefulSet.Namespace,
+			},
+		})
+	}
+
+	rc.Client = fake.NewClientBuilder().WithRuntimeObjects(trackObjects...).Build()
+	err = rc.ReconcilePods(statefulSet)
+	assert.NoErrorf(t, err, "Should not have returned an error")
+
+	for i := int32(0); i < statefulSet.Status.Replicas; i++ {
+		pod := &corev1.Pod{}
+		err = rc.Client.Get(rc.Ctx, types.NamespacedName{Name: getStatefulSetPodNameForIdx(statefulSet, i), Namespace: statefulSet.Namespace}, pod)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%d", i), pod.Annotations[api.PodOrdinalAnnotation])
+	}
+	// The pods get the rack annotation from their template
+	assert.Equal(t, "default", statefulSet.Spec.Template.Annotations[api.RackAnnotation])
+}
+
 // Note: getStatefulSetForRack is currently just a query,
 // and there is really no logic to test.
 // We can add a unit test later, if needed.
@@ -560,6 +658,61 @@ func TestCalculateRackInformation_MultiRack(t *testing.T) {
 	// TODO add more RackInformation validation
 }
 
+func TestCalculateRackInformation_LargeDatacenterSeeds(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{{Name: "rack0"}, {Name: "rack1"}, {Name: "rack2"}}
+	rc.Datacenter.Spec.Size = 90
+
+	err := rc.CalculateRackInformation()
+	assert.NoError(t, err)
+
+	seedCount := 0
+	for _, rackInfo := range rc.desiredRackInformation {
+		assert.Equal(t, 3, rackInfo.SeedCount, "Seeds should be spread over the racks")
+		seedCount += rackInfo.SeedCount
+	}
+	assert.Equal(t, 9, seedCount, "Should have one seed per 10 nodes")
+
+	// The seed count is capped
+	rc.Datacenter.Spec.Size = 300
+	assert.NoError(t, rc.CalculateRackInformation())
+	assert.Equal(t, []int{4, 3, 3}, seedCountsOf(rc.desiredRackInformation))
+
+	// An explicit number of seeds per rack wins
+	rc.Datacenter.Spec.SeedsPerRack = 2
+	assert.NoError(t, rc.CalculateRackInformation())
+	assert.Equal(t, []int{2, 2, 2}, seedCountsOf(rc.desiredRackInformation))
+}
+
+func seedCountsOf(rackInfos []*RackInformation) []int {
+	counts := []int{}
+	for _, rackInfo := range rackInfos {
+		counts = append(counts, rackInfo.SeedCount)
+	}
+	return counts
+}
+
+func Test_desiredSeedCount(t *testing.T) {
+	tests := []struct {
+		nodeCount, rackCount, seedsPerRack int
+		want                               int
+	}{
+		{nodeCount: 1, rackCount: 1, want: 1},
+		{nodeCount: 6, rackCount: 3, want: 3},
+		{nodeCount: 8, rackCount: 4, want: 4},
+		{nodeCount: 45, rackCount: 3, want: 4},
+		{nodeCount: 500, rackCount: 3, want: maxAutoSeeds},
+		{nodeCount: 24, rackCount: 12, want: 12},
+		{nodeCount: 6, rackCount: 3, seedsPerRack: 3, want: 6},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, desiredSeedCount(tt.nodeCount, tt.rackCount, tt.seedsPerRack),
+			"nodes %d, racks %d, seeds per rack %d", tt.nodeCount, tt.rackCount, tt.seedsPerRack)
+	}
+}
+
 func TestReconcileRacks(t *testing.T) {
 	rc, _, cleanupMockScr := setupTest()
 	defer cleanupMockScr()
@@ -799,6 +952,48 @@ func TestReconcileRacks_NeedToPark(t *testing.T) {
 	assert.Equal(t, int32(0), *currentStatefulSet.Spec.Replicas, "The statefulset should be set to zero replicas")
 }
 
+func TestCheckRackStoppedState_KeepsPVCs(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	statefulSet, err := newStatefulSetForCassandraDatacenter(
+		nil,
+		"default",
+		rc.Datacenter,
+		1,
+		false)
+	assert.NoError(err)
+	assert.NoError(rc.Client.Create(rc.Ctx, statefulSet))
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "server-data-" + statefulSet.Name + "-0",
+			Namespace: statefulSet.Namespace,
+			Labels:    rc.Datacenter.GetRackLabels("default"),
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pvc))
+
+	rc.Datacenter.Spec.Stopped = true
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	rc.desiredRackInformation = []*RackInformation{{RackName: "default", NodeCount: 1}}
+	rc.statefulSets = []*appsv1.StatefulSet{statefulSet}
+
+	res := rc.CheckRackStoppedState()
+	assert.Equal(result.Done(), res)
+
+	currentStatefulSet := &appsv1.StatefulSet{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: statefulSet.Name, Namespace: statefulSet.Namespace}, currentStatefulSet))
+	assert.Equal(int32(0), *currentStatefulSet.Spec.Replicas)
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterStopped))
+
+	// Stopping only scales the racks down, the data of the nodes is kept for when they resume
+	currentPvc := &corev1.PersistentVolumeClaim{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, currentPvc))
+}
+
 func TestReconcileRacks_AlreadyReconciled(t *testing.T) {
 	t.Skip("FIXME - Skipping this test")
 
@@ -1553,6 +1748,87 @@ func TestCleanupAfterScaling(t *testing.T) {
 	assert.Equal(0, len(rc.Datacenter.Status.TrackedTasks))
 }
 
+func TestCheckCleanupAnnotation(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	assert.NoError(taskapi.AddToScheme(scheme.Scheme))
+
+	// Without the annotation, nothing happens
+	assert.Equal(result.Continue(), rc.CheckCleanupAnnotation())
+	assert.Empty(rc.Datacenter.Status.TrackedTasks)
+
+	requested := time.Now().UTC().Format(time.RFC3339)
+	metav1.SetMetaDataAnnotation(&rc.Datacenter.ObjectMeta, api.CleanupAnnotation, requested)
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	assert.Equal(result.Continue(), rc.CheckCleanupAnnotation())
+	assert.Equal(requested, rc.Datacenter.Status.LastCleanupAnnotation)
+	assert.Len(rc.Datacenter.Status.TrackedTasks, 1)
+
+	task := &taskapi.CassandraTask{}
+	taskKey := types.NamespacedName{Name: rc.Datacenter.Status.TrackedTasks[0].Name, Namespace: rc.Datacenter.Namespace}
+	assert.NoError(rc.Client.Get(rc.Ctx, taskKey, task))
+	assert.Equal(taskapi.CommandCleanup, task.Spec.Jobs[0].Command)
+
+	// The task is running, it is not started again
+	assert.Equal(result.Continue(), rc.CheckCleanupAnnotation())
+	assert.Len(rc.Datacenter.Status.TrackedTasks, 1)
+
+	// Once completed, the task is no longer tracked and the processed request is not run again
+	completed := metav1.Now()
+	task.Status.CompletionTime = &completed
+	assert.NoError(rc.Client.Status().Update(rc.Ctx, task))
+
+	assert.Equal(result.Continue(), rc.CheckCleanupAnnotation())
+	assert.Empty(rc.Datacenter.Status.TrackedTasks)
+	tasks := &taskapi.CassandraTaskList{}
+	assert.NoError(rc.Client.List(rc.Ctx, tasks))
+	assert.Len(tasks.Items, 1)
+}
+
+func TestCheckClearActionConditions_AutoCleanupAfterScaleUp(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	assert.NoError(taskapi.AddToScheme(scheme.Scheme))
+
+	rc.Datacenter.SetCondition(*api.NewDatacenterCondition(api.DatacenterInitialized, corev1.ConditionTrue))
+	rc.Datacenter.SetCondition(*api.NewDatacenterCondition(api.DatacenterScalingUp, corev1.ConditionTrue))
+	assert.NoError(rc.Client.Status().Update(rc.Ctx, rc.Datacenter))
+
+	// By default, the scale up only completes once the cleanup task has run
+	assert.Nil(rc.Datacenter.Spec.AutoCleanupAfterScaleUp)
+	assert.Equal(result.RequeueSoon(10), rc.CheckClearActionConditions())
+	assert.Len(rc.Datacenter.Status.TrackedTasks, 1)
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterScalingUp))
+
+	task := &taskapi.CassandraTask{}
+	taskKey := types.NamespacedName{Name: rc.Datacenter.Status.TrackedTasks[0].Name, Namespace: rc.Datacenter.Namespace}
+	assert.NoError(rc.Client.Get(rc.Ctx, taskKey, task))
+	assert.Equal(taskapi.CommandCleanup, task.Spec.Jobs[0].Command)
+
+	completed := metav1.Now()
+	task.Status.CompletionTime = &completed
+	assert.NoError(rc.Client.Status().Update(rc.Ctx, task))
+	assert.Equal(result.RequeueSoon(0), rc.CheckClearActionConditions())
+	assert.Empty(rc.Datacenter.Status.TrackedTasks)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterScalingUp))
+
+	// Disabled, the scale up completes without a cleanup
+	rc.Datacenter.Spec.AutoCleanupAfterScaleUp = boolPtr(false)
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+	rc.Datacenter.SetCondition(*api.NewDatacenterCondition(api.DatacenterScalingUp, corev1.ConditionTrue))
+	assert.NoError(rc.Client.Status().Update(rc.Ctx, rc.Datacenter))
+	assert.Equal(result.RequeueSoon(0), rc.CheckClearActionConditions())
+	assert.Empty(rc.Datacenter.Status.TrackedTasks)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterScalingUp))
+
+	tasks := &taskapi.CassandraTaskList{}
+	assert.NoError(rc.Client.List(rc.Ctx, tasks))
+	assert.Len(tasks.Items, 1)
+}
+
 func TestStripPassword(t *testing.T) {
 	rc, _, cleanupMockScr := setupTest()
 	defer cleanupMockScr()
@@ -1653,6 +1929,17 @@ func TestFailedStart(t *testing.T) {
 	}
 
 	mockHttpClient := &mocks.HttpClient{}
+	// the management API is reachable, only the start fails
+	mockHttpClient.On("Do",
+		mock.MatchedBy(
+			func(req *http.Request) bool {
+				return req != nil && req.URL.Path == "/api/v0/probes/liveness"
+			})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("OK")),
+		}, nil).
+		Once()
 	mockHttpClient.On("Do",
 		mock.MatchedBy(
 			func(req *http.Request) bool {
@@ -1674,6 +1961,7 @@ func TestFailedStart(t *testing.T) {
 	}
 
 	pod := makeReloadTestPod()
+	pod.Status.PodIP = "1.2.3.4"
 
 	fakeRecorder := record.NewFakeRecorder(5)
 	rc.Recorder = fakeRecorder
@@ -1687,3 +1975,1746 @@ func TestFailedStart(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestCheckSeedLabels_SplitBrainRecovery(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.desiredRackInformation = []*RackInformation{
+		{RackName: "r1", NodeCount: 2, SeedCount: 1},
+		{RackName: "r2", NodeCount: 2, SeedCount: 1},
+	}
+
+	// The second pod of each rack is labelled as a seed, which shares nothing with
+	// the first ready pod of each rack that the operator would choose
+	rc.dcPods = []*corev1.Pod{}
+	for _, rackName := range []string{"r1", "r2"} {
+		for i := 0; i < 2; i++ {
+			pod := makeMockReadyStartedPod()
+			pod.Name = fmt.Sprintf("%s-sts-%d", rackName, i)
+			pod.Namespace = rc.Datacenter.Namespace
+			utils.MergeMap(pod.Labels, rc.Datacenter.GetRackLabels(rackName))
+			if i == 1 {
+				pod.Labels[api.SeedNodeLabel] = "true"
+			}
+			assert.NoError(rc.Client.Create(rc.Ctx, pod))
+			rc.dcPods = append(rc.dcPods, pod)
+		}
+	}
+
+	seedCount, err := rc.checkSeedLabels()
+	assert.NoError(err)
+	assert.Equal(2, seedCount)
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterRecoveringSeeds))
+
+	for _, pod := range rc.dcPods {
+		current := &corev1.Pod{}
+		assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, current))
+		_, isSeed := current.Labels[api.SeedNodeLabel]
+		assert.Equal(strings.HasSuffix(pod.Name, "-0"), isSeed, "unexpected seed label on pod %s", pod.Name)
+	}
+
+	// Once the labels have converged, the condition is cleared
+	_, err = rc.checkSeedLabels()
+	assert.NoError(err)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterRecoveringSeeds))
+}
+
+func TestCheckSeedLabels_PromoteSeedOnDeletion(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.desiredRackInformation = []*RackInformation{{RackName: "r1", NodeCount: 3, SeedCount: 1}}
+
+	rc.dcPods = []*corev1.Pod{}
+	for i := 2; i >= 0; i-- {
+		pod := makeMockReadyStartedPod()
+		pod.Name = fmt.Sprintf("r1-sts-%d", i)
+		pod.Namespace = rc.Datacenter.Namespace
+		utils.MergeMap(pod.Labels, rc.Datacenter.GetRackLabels("r1"))
+		assert.NoError(rc.Client.Create(rc.Ctx, pod))
+		rc.dcPods = append(rc.dcPods, pod)
+	}
+
+	seedNames := func() []string {
+		return utils.GetPodNameSet(utils.FilterPodsWithLabel(rc.dcPods, api.SeedNodeLabel, "true")).ToSlice()
+	}
+
+	// The seeds are the first ready pods by name, whatever the order of the pods
+	_, err := rc.checkSeedLabels()
+	assert.NoError(err)
+	assert.Equal([]string{"r1-sts-0"}, seedNames())
+
+	// The seed pod is deleted, the next ready pod is promoted
+	assert.NoError(rc.Client.Delete(rc.Ctx, rc.dcPods[2]))
+	rc.dcPods = rc.dcPods[:2]
+
+	_, err = rc.checkSeedLabels()
+	assert.NoError(err)
+	assert.Equal([]string{"r1-sts-1"}, seedNames())
+}
+
+func TestCheckRackScale_PausedRack(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{
+		{Name: "rack1", Paused: true},
+		{Name: "rack2"},
+	}
+
+	rc.desiredRackInformation = []*RackInformation{}
+	rc.statefulSets = []*appsv1.StatefulSet{}
+	for _, rack := range rc.Datacenter.Spec.Racks {
+		sts, err := newStatefulSetForCassandraDatacenter(nil, rack.Name, rc.Datacenter, 1, false)
+		assert.NoError(err)
+		assert.NoError(rc.Client.Create(rc.Ctx, sts))
+
+		rc.statefulSets = append(rc.statefulSets, sts)
+		rc.desiredRackInformation = append(rc.desiredRackInformation, &RackInformation{
+			RackName:  rack.Name,
+			NodeCount: 2,
+		})
+	}
+
+	r := rc.CheckRackScale()
+	assert.Equal(result.Continue(), r)
+
+	expectedReplicas := map[string]int32{
+		"rack1": 1,
+		"rack2": 2,
+	}
+	for idx, rackInfo := range rc.desiredRackInformation {
+		sts := &appsv1.StatefulSet{}
+		assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(rc.statefulSets[idx]), sts))
+		assert.Equal(expectedReplicas[rackInfo.RackName], *sts.Spec.Replicas, "unexpected replicas for rack %s", rackInfo.RackName)
+	}
+}
+
+func TestCheckRackScale_ScaleCooldown(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.ScaleCooldownSeconds = 60
+	rc.Datacenter.Status.LastScaleOperation = metav1.NewTime(time.Now().Add(-10 * time.Second))
+	rc.Datacenter.SetCondition(*api.NewDatacenterCondition(api.DatacenterReady, corev1.ConditionTrue))
+
+	sts, err := newStatefulSetForCassandraDatacenter(nil, "rack1", rc.Datacenter, 1, false)
+	assert.NoError(err)
+	assert.NoError(rc.Client.Create(rc.Ctx, sts))
+	rc.statefulSets = []*appsv1.StatefulSet{sts}
+	rc.desiredRackInformation = []*RackInformation{{RackName: "rack1", NodeCount: 2}}
+
+	// The previous scale finished 10 seconds ago, the next one is deferred
+	r := rc.CheckRackScale()
+	assert.True(r.Completed())
+	res, err := r.Output()
+	assert.NoError(err)
+	assert.True(res.RequeueAfter > 0 && res.RequeueAfter <= 51*time.Second, "unexpected requeue %v", res.RequeueAfter)
+	assert.NotEqual(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterScalingUp))
+
+	current := &appsv1.StatefulSet{}
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(sts), current))
+	assert.Equal(int32(1), *current.Spec.Replicas)
+
+	// Once the cooldown is over, the rack is scaled up
+	rc.Datacenter.Status.LastScaleOperation = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	assert.Equal(result.Continue(), rc.CheckRackScale())
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterScalingUp))
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(sts), current))
+	assert.Equal(int32(2), *current.Spec.Replicas)
+}
+
+func TestCheckNetworkPolicy(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	// Nothing is created unless enabled
+	assert.Equal(result.Continue(), rc.CheckNetworkPolicy())
+	policies := &networkingv1.NetworkPolicyList{}
+	assert.NoError(rc.Client.List(rc.Ctx, policies))
+	assert.Empty(policies.Items)
+
+	rc.Datacenter.Spec.CreateNetworkPolicy = true
+	rc.Datacenter.Spec.NetworkPolicyClientNamespaceLabels = map[string]string{"cassandra-client": "true"}
+	assert.Equal(result.Continue(), rc.CheckNetworkPolicy())
+
+	policy := &networkingv1.NetworkPolicy{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: rc.Datacenter.Name + "-network-policy", Namespace: rc.Datacenter.Namespace}, policy))
+	assert.Equal(rc.Datacenter.GetDatacenterLabels(), policy.Spec.PodSelector.MatchLabels)
+	assert.Equal(3, len(policy.Spec.Ingress))
+
+	rulePorts := func(rule networkingv1.NetworkPolicyIngressRule) []int {
+		ports := []int{}
+		for _, port := range rule.Ports {
+			ports = append(ports, port.Port.IntValue())
+		}
+		return ports
+	}
+
+	internodeRule := policy.Spec.Ingress[0]
+	assert.Equal([]int{7000, 7001, 8609, 9042, 9142}, rulePorts(internodeRule))
+	assert.Equal(rc.Datacenter.GetClusterLabels(), internodeRule.From[0].PodSelector.MatchLabels)
+
+	clientRule := policy.Spec.Ingress[1]
+	assert.Equal([]int{9042, 9142}, rulePorts(clientRule))
+	assert.Equal(map[string]string{"cassandra-client": "true"}, clientRule.From[0].NamespaceSelector.MatchLabels)
+
+	// The operator and the monitoring need to reach the management API and the metrics
+	assert.Empty(policy.Spec.Ingress[2].From)
+	assert.Equal([]int{8080, 9103}, rulePorts(policy.Spec.Ingress[2]))
+
+	// Client namespaces changes are applied
+	rc.Datacenter.Spec.NetworkPolicyClientNamespaceLabels = map[string]string{"app": "client"}
+	assert.Equal(result.Continue(), rc.CheckNetworkPolicy())
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(policy), policy))
+	assert.Equal(map[string]string{"app": "client"}, policy.Spec.Ingress[1].From[0].NamespaceSelector.MatchLabels)
+
+	// Disabling the feature deletes the policy. The owner reference is not set by the mocked
+	// setControllerReference.
+	policy.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(rc.Datacenter, api.GroupVersion.WithKind("CassandraDatacenter"))}
+	assert.NoError(rc.Client.Update(rc.Ctx, policy))
+	rc.Datacenter.Spec.CreateNetworkPolicy = false
+	assert.Equal(result.Continue(), rc.CheckNetworkPolicy())
+	assert.True(errors.IsNotFound(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(policy), policy)))
+
+	// A policy of the same name the operator doesn't own is left alone
+	foreignPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: policy.Name, Namespace: policy.Namespace},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, foreignPolicy))
+	assert.Equal(result.Continue(), rc.CheckNetworkPolicy())
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(policy), policy))
+}
+
+func TestCheckSuperuserSecretChange_RestartOnCredentialChange(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.RestartOnCredentialChange = true
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	secretName := rc.Datacenter.GetSuperuserSecretNamespacedName()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName.Name,
+			Namespace: secretName.Namespace,
+		},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("secret"),
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, secret))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pod-1",
+			Namespace:         rc.Datacenter.Namespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pod))
+	rc.dcPods = []*corev1.Pod{pod}
+
+	// The first time the secret is seen, its version is only recorded
+	r := rc.CheckSuperuserSecretChange()
+	assert.Equal(result.Continue(), r)
+	assert.Equal(secret.ResourceVersion, rc.Datacenter.Status.SuperuserSecretResourceVersion)
+	assert.True(rc.Datacenter.Status.LastRollingRestart.IsZero())
+	assert.Equal(result.Continue(), rc.CheckRollingRestart())
+
+	secret.Data["password"] = []byte("new-secret")
+	assert.NoError(rc.Client.Update(rc.Ctx, secret))
+
+	r = rc.CheckSuperuserSecretChange()
+	assert.Equal(result.Continue(), r)
+	assert.Equal(secret.ResourceVersion, rc.Datacenter.Status.SuperuserSecretResourceVersion)
+	assert.False(rc.Datacenter.Status.LastRollingRestart.IsZero())
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterRollingRestart))
+
+	// The pod is older than the restart request and gets deleted
+	assert.Equal(result.Done(), rc.CheckRollingRestart())
+	err := rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{})
+	assert.True(errors.IsNotFound(err), "pod should have been deleted")
+}
+
+func TestCheckRollingRestart_RollingOperation(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pod-0",
+			Namespace:         rc.Datacenter.Namespace,
+			Labels:            rc.Datacenter.GetRackLabels("default"),
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pod))
+	rc.dcPods = []*corev1.Pod{pod}
+	assert.False(rc.Datacenter.RollingOperationInProgress())
+
+	metav1.SetMetaDataAnnotation(&rc.Datacenter.ObjectMeta, api.RollingRestartAnnotation,
+		time.Now().UTC().Format(time.RFC3339))
+	assert.Equal(result.Done(), rc.CheckRollingRestart())
+
+	assert.True(rc.Datacenter.RollingOperationInProgress())
+	assert.Equal(&api.RollingOperation{Type: api.RollingOperationRestart, Pod: pod.Name}, rc.Datacenter.Status.RollingOperation)
+
+	dc := &api.CassandraDatacenter{}
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(rc.Datacenter), dc))
+	assert.Equal(rc.Datacenter.Status.RollingOperation, dc.Status.RollingOperation)
+
+	// Once the pods are all back, the operation is cleared
+	recreated := pod.DeepCopy()
+	recreated.CreationTimestamp = metav1.NewTime(time.Now().Add(time.Minute))
+	rc.dcPods = []*corev1.Pod{recreated}
+	assert.Equal(result.Continue(), rc.CheckRollingRestart())
+	rc.CheckClearActionConditions()
+
+	assert.False(rc.Datacenter.RollingOperationInProgress())
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(rc.Datacenter), dc))
+	assert.Nil(dc.Status.RollingOperation)
+}
+
+func TestCheckRollingRestart_Annotation(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{{Name: "rack1"}, {Name: "rack2"}}
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	// Listed out of rack order, the restart follows the order of the racks
+	oldPods := []*corev1.Pod{}
+	for _, rackPod := range []struct{ name, rack string }{{"pod-a", "rack2"}, {"pod-b", "rack1"}} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              rackPod.name,
+				Namespace:         rc.Datacenter.Namespace,
+				Labels:            rc.Datacenter.GetRackLabels(rackPod.rack),
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, pod))
+		oldPods = append(oldPods, pod)
+	}
+	rc.dcPods = oldPods
+
+	// Nothing happens without the annotation
+	assert.Equal(result.Continue(), rc.CheckRollingRestart())
+
+	requested := time.Now().UTC().Format(time.RFC3339)
+	metav1.SetMetaDataAnnotation(&rc.Datacenter.ObjectMeta, api.RollingRestartAnnotation, requested)
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	assert.Equal(result.Done(), rc.CheckRollingRestart())
+	assert.Equal(requested, rc.Datacenter.Status.LastRollingRestartAnnotation)
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterRollingRestart))
+	lastRollingRestart := rc.Datacenter.Status.LastRollingRestart
+
+	err := rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(oldPods[1]), &corev1.Pod{})
+	assert.True(errors.IsNotFound(err), "pod of rack1 should have been deleted first")
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(oldPods[0]), &corev1.Pod{}))
+
+	// Once recreated, the next pod is restarted without requesting the restart again
+	recreated := oldPods[1].DeepCopy()
+	recreated.CreationTimestamp = metav1.NewTime(time.Now().Add(time.Minute))
+	rc.dcPods = []*corev1.Pod{oldPods[0], recreated}
+	assert.Equal(result.Done(), rc.CheckRollingRestart())
+	assert.Equal(lastRollingRestart, rc.Datacenter.Status.LastRollingRestart)
+	err = rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(oldPods[0]), &corev1.Pod{})
+	assert.True(errors.IsNotFound(err), "pod of rack2 should have been deleted")
+
+	// The request is processed only once
+	recreatedA := oldPods[0].DeepCopy()
+	recreatedA.CreationTimestamp = metav1.NewTime(time.Now().Add(time.Minute))
+	rc.dcPods = []*corev1.Pod{recreatedA, recreated}
+	assert.Equal(result.Continue(), rc.CheckRollingRestart())
+	assert.Equal(lastRollingRestart, rc.Datacenter.Status.LastRollingRestart)
+
+	// An older request is ignored
+	metav1.SetMetaDataAnnotation(&rc.Datacenter.ObjectMeta, api.RollingRestartAnnotation,
+		time.Now().Add(-time.Hour).UTC().Format(time.RFC3339))
+	assert.Equal(result.Continue(), rc.CheckRollingRestart())
+	assert.Equal(requested, rc.Datacenter.Status.LastRollingRestartAnnotation)
+}
+
+func TestCheckKeystoreSecretChange_HotReloadTLS(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.HotReloadTLS = true
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	features := `{"cassandra_version": "4.0.1", "features": ["reload_ssl"]}`
+	calledPaths := []string{}
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do", mock.Anything).
+		Return(func(req *http.Request) *http.Response {
+			calledPaths = append(calledPaths, req.URL.Path)
+			body := "OK"
+			if req.URL.Path == "/api/v0/metadata/versions/features" {
+				body = features
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}
+		}, nil)
+
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	secretName := rc.keystoreSecret()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName.Name,
+			Namespace: secretName.Namespace,
+		},
+		Data: map[string][]byte{
+			"node-keystore.jks": []byte("keystore"),
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, secret))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+			Labels: map[string]string{
+				api.CassNodeState: stateStarted,
+			},
+		},
+		Status: corev1.PodStatus{
+			PodIP: "192.168.101.11",
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "cassandra",
+				Ready: true,
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{
+						StartedAt: metav1.NewTime(time.Now().Add(-time.Minute)),
+					},
+				},
+			}},
+		},
+	}
+	rc.dcPods = []*corev1.Pod{pod}
+
+	// The first time the secret is seen, its version is only recorded
+	assert.Equal(result.Continue(), rc.CheckKeystoreSecretChange())
+	assert.Equal(secret.ResourceVersion, rc.Datacenter.Status.KeystoreSecretResourceVersion)
+	assert.Empty(calledPaths)
+
+	// A rotated certificate is reloaded instead of restarting the node
+	secret.Data["node-keystore.jks"] = []byte("rotated")
+	assert.NoError(rc.Client.Update(rc.Ctx, secret))
+
+	// The reload waits for the kubelet to update the mounted secret
+	recResult := rc.CheckKeystoreSecretChange()
+	assert.True(recResult.Completed())
+	res, err := recResult.Output()
+	assert.NoError(err)
+	assert.True(res.RequeueAfter > keystoreSyncDelay-time.Minute)
+	assert.Equal(secret.ResourceVersion, rc.Datacenter.Status.KeystoreSecretResourceVersion)
+	assert.Empty(calledPaths)
+
+	keystoreSynced := func() {
+		rc.Datacenter.Status.KeystoreSecretReloadTime = metav1.NewTime(time.Now().Add(-time.Second))
+		assert.NoError(rc.Client.Status().Update(rc.Ctx, rc.Datacenter))
+	}
+	keystoreSynced()
+
+	assert.Equal(result.Continue(), rc.CheckKeystoreSecretChange())
+	assert.True(rc.Datacenter.Status.KeystoreSecretReloadTime.IsZero())
+	assert.Equal([]string{
+		"/api/v0/metadata/versions/features",
+		"/api/v0/ops/node/ssl/reload",
+	}, calledPaths)
+	assert.True(rc.Datacenter.Status.LastRollingRestart.IsZero())
+	assert.NotEqual(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterRollingRestart))
+
+	// Without support for reloading the SSL contexts, the nodes are restarted
+	features = `{"cassandra_version": "3.11.11", "features": []}`
+	calledPaths = []string{}
+	secret.Data["node-keystore.jks"] = []byte("rotated again")
+	assert.NoError(rc.Client.Update(rc.Ctx, secret))
+
+	assert.True(rc.CheckKeystoreSecretChange().Completed())
+	assert.Empty(calledPaths)
+	keystoreSynced()
+
+	assert.Equal(result.Continue(), rc.CheckKeystoreSecretChange())
+	assert.Equal([]string{"/api/v0/metadata/versions/features"}, calledPaths)
+	assert.False(rc.Datacenter.Status.LastRollingRestart.IsZero())
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterRollingRestart))
+}
+
+func TestCheckClientConfigSecret(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.GenerateClientConfigSecret = true
+
+	secretName := rc.Datacenter.GetSuperuserSecretNamespacedName()
+	superuserSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName.Name,
+			Namespace: secretName.Namespace,
+		},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("secret"),
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, superuserSecret))
+
+	clientConfigName := types.NamespacedName{
+		Name:      rc.Datacenter.GetClientConfigSecretName(),
+		Namespace: rc.Datacenter.Namespace,
+	}
+
+	// Nothing is generated until the datacenter is ready
+	assert.Equal(result.Continue(), rc.CheckClientConfigSecret())
+	err := rc.Client.Get(rc.Ctx, clientConfigName, &corev1.Secret{})
+	assert.True(errors.IsNotFound(err), "client config secret should not exist yet")
+
+	rc.Datacenter.SetCondition(*api.NewDatacenterCondition(api.DatacenterReady, corev1.ConditionTrue))
+	assert.Equal(result.Continue(), rc.CheckClientConfigSecret())
+
+	secret := &corev1.Secret{}
+	assert.NoError(rc.Client.Get(rc.Ctx, clientConfigName, secret))
+	assert.Equal(fmt.Sprintf("%s.%s.svc", rc.Datacenter.GetDatacenterServiceName(), rc.Datacenter.Namespace),
+		string(secret.Data["contact-points"]))
+	assert.Equal("9042", string(secret.Data["port"]))
+	assert.Equal(rc.Datacenter.Name, string(secret.Data["local-datacenter"]))
+	assert.Equal("admin", string(secret.Data["username"]))
+	assert.Equal("secret", string(secret.Data["password"]))
+	assert.NotContains(secret.Data, "ca.crt")
+
+	// The secret follows the credentials
+	superuserSecret.Data["password"] = []byte("new-secret")
+	assert.NoError(rc.Client.Update(rc.Ctx, superuserSecret))
+	assert.Equal(result.Continue(), rc.CheckClientConfigSecret())
+	assert.NoError(rc.Client.Get(rc.Ctx, clientConfigName, secret))
+	assert.Equal("new-secret", string(secret.Data["password"]))
+}
+
+func TestCheckConfigBuilderFailures(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name: ServerConfigContainerName,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 1,
+						Reason:   "Error",
+						Message:  "invalid cassandra-yaml option",
+					},
+				},
+			}},
+		},
+	}
+	rc.dcPods = []*corev1.Pod{pod}
+
+	r := rc.CheckConfigBuilderFailures()
+	assert.Equal(result.Continue(), r)
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterConfigBuilderFailed))
+	condition, found := rc.Datacenter.GetCondition(api.DatacenterConfigBuilderFailed)
+	assert.True(found)
+	assert.Contains(condition.Message, "invalid cassandra-yaml option")
+
+	// Once the init container succeeds, the condition is cleared even though the previous run failed
+	pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+	}
+
+	r = rc.CheckConfigBuilderFailures()
+	assert.Equal(result.Continue(), r)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterConfigBuilderFailed))
+}
+
+func TestProgressPercent(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.desiredRackInformation = []*RackInformation{{RackName: "rack1", NodeCount: 4}}
+	rc.statefulSets = make([]*appsv1.StatefulSet, 1)
+
+	readyPod := makeMockReadyStartedPod()
+	readyPod.Name = "pod-0"
+	readyPod.Labels[api.RackLabel] = "rack1"
+	rc.dcPods = []*corev1.Pod{readyPod}
+	for i := 1; i < 4; i++ {
+		rc.dcPods = append(rc.dcPods, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("pod-%d", i),
+				Labels: map[string]string{api.RackLabel: "rack1"},
+			},
+		})
+	}
+
+	assert.Equal(25, rc.ProgressPercent())
+
+	// A ready pod that still runs the previous revision is not done
+	rc.statefulSets[0] = &appsv1.StatefulSet{
+		Status: appsv1.StatefulSetStatus{UpdateRevision: "rev-2"},
+	}
+	readyPod.Labels[appsv1.ControllerRevisionHashLabelKey] = "rev-1"
+	assert.Equal(0, rc.ProgressPercent())
+
+	readyPod.Labels[appsv1.ControllerRevisionHashLabelKey] = "rev-2"
+	assert.Equal(25, rc.ProgressPercent())
+
+	assert.Equal(result.Continue(), rc.CheckProgressPercent())
+	assert.Equal(25, rc.Datacenter.Status.ProgressPercent)
+}
+
+func TestPodsRemainingToCreate(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.desiredRackInformation = []*RackInformation{
+		{RackName: "rack1", NodeCount: 2},
+		{RackName: "rack2", NodeCount: 2},
+		{RackName: "rack3", NodeCount: 2},
+	}
+	rc.dcPods = nil
+	assert.Equal(6, rc.PodsRemainingToCreate())
+
+	// Partially created, the first rack is complete and the second has one pod
+	for i, rackName := range []string{"rack1", "rack1", "rack2"} {
+		rc.dcPods = append(rc.dcPods, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("pod-%d", i),
+				Labels: map[string]string{api.RackLabel: rackName},
+			},
+		})
+	}
+	assert.Equal(3, rc.PodsRemainingToCreate())
+
+	// Extra pods left over during a scale down never make it negative
+	rc.desiredRackInformation = []*RackInformation{{RackName: "rack1", NodeCount: 1}}
+	assert.Equal(0, rc.PodsRemainingToCreate())
+}
+
+func TestCheckRackPodTemplate_RackDown(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{
+		{Name: "rack1", Zone: "zone-1"},
+		{Name: "rack2", Zone: "zone-2"},
+	}
+	rc.Datacenter.Spec.Size = 2
+	rc.Datacenter.SetCondition(*api.NewDatacenterCondition(api.DatacenterInitialized, corev1.ConditionTrue))
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	if err := rc.CalculateRackInformation(); err != nil {
+		t.Fatalf("failed to calculate rack information: %s", err)
+	}
+
+	recResult := rc.CheckRackCreation()
+	assert.False(recResult.Completed(), "CheckRackCreation did not complete as expected")
+	assert.Len(rc.statefulSets, 2)
+
+	// rack1 is entirely down, its only pod is not ready
+	rc.statefulSets[0].Status = appsv1.StatefulSetStatus{
+		Replicas:        1,
+		ReadyReplicas:   0,
+		CurrentReplicas: 1,
+		UpdatedReplicas: 1,
+	}
+	downPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   rc.statefulSets[0].Name + "-0",
+			Labels: rc.Datacenter.GetRackLabels("rack1"),
+		},
+	}
+
+	// rack2 is healthy, but its statefulset is out of date
+	readyPod := makeMockReadyStartedPod()
+	readyPod.Name = rc.statefulSets[1].Name + "-0"
+	readyPod.Labels[api.RackLabel] = "rack2"
+	rc.dcPods = []*corev1.Pod{downPod, readyPod}
+	rc.statefulSets[1].Annotations[utils.ResourceHashAnnotationKey] = "stale"
+
+	assert.True(rc.isRackDown("rack1"))
+	assert.False(rc.isRackDown("rack2"))
+
+	assert.Equal(result.Continue(), rc.CheckRackDown())
+	cond, found := rc.Datacenter.GetCondition(api.DatacenterRackDown)
+	assert.True(found)
+	assert.Equal(corev1.ConditionTrue, cond.Status)
+	assert.Equal("Racks down: rack1", cond.Message)
+
+	// The healthy rack is updated instead of waiting for the down rack
+	recResult = rc.CheckRackPodTemplate()
+	assert.Equal(result.Done(), recResult)
+
+	sts := &appsv1.StatefulSet{}
+	err := rc.Client.Get(rc.Ctx, types.NamespacedName{Namespace: rc.statefulSets[1].Namespace, Name: rc.statefulSets[1].Name}, sts)
+	assert.NoError(err)
+	assert.NotEqual("stale", sts.Annotations[utils.ResourceHashAnnotationKey])
+}
+
+func TestCheckRackPodTemplate_RollingOperation(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{
+		{Name: "rack1", Zone: "zone-1"},
+	}
+	rc.Datacenter.Spec.Size = 1
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	if err := rc.CalculateRackInformation(); err != nil {
+		t.Fatalf("failed to calculate rack information: %s", err)
+	}
+
+	recResult := rc.CheckRackCreation()
+	assert.False(recResult.Completed(), "CheckRackCreation did not complete as expected")
+
+	// The statefulset is up to date, but its pod still runs the previous revision
+	rc.statefulSets[0].Status = appsv1.StatefulSetStatus{
+		Replicas:        1,
+		ReadyReplicas:   1,
+		CurrentReplicas: 1,
+		UpdatedReplicas: 0,
+		UpdateRevision:  "rev-2",
+	}
+	pod := makeMockReadyStartedPod()
+	pod.Name = rc.statefulSets[0].Name + "-0"
+	pod.Labels[api.RackLabel] = "rack1"
+	pod.Labels[appsv1.ControllerRevisionHashLabelKey] = "rev-1"
+	rc.dcPods = []*corev1.Pod{pod}
+
+	assert.Equal(result.RequeueSoon(10), rc.CheckRackPodTemplate())
+	assert.Equal(&api.RollingOperation{Type: api.RollingOperationUpgrade, Pod: pod.Name}, rc.Datacenter.Status.RollingOperation)
+
+	dc := &api.CassandraDatacenter{}
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(rc.Datacenter), dc))
+	assert.Equal(rc.Datacenter.Status.RollingOperation, dc.Status.RollingOperation)
+}
+
+func TestCheckRackStatuses(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.desiredRackInformation = []*RackInformation{
+		{RackName: "rack1", NodeCount: 2},
+		{RackName: "rack2", NodeCount: 1},
+	}
+
+	rc.dcPods = []*corev1.Pod{}
+	for i := 0; i < 2; i++ {
+		pod := makeMockReadyStartedPod()
+		pod.Name = fmt.Sprintf("rack1-pod-%d", i)
+		pod.Labels[api.RackLabel] = "rack1"
+		rc.dcPods = append(rc.dcPods, pod)
+	}
+	rc.dcPods = append(rc.dcPods, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "rack2-pod-0",
+			Labels: map[string]string{api.RackLabel: "rack2"},
+		},
+	})
+
+	assert.Equal(result.Continue(), rc.CheckRackStatuses())
+	assert.Equal([]api.RackStatus{
+		{Name: "rack1", DesiredNodes: 2, ReadyNodes: 2, Phase: api.RackPhaseReady},
+		{Name: "rack2", DesiredNodes: 1, ReadyNodes: 0, Phase: api.RackPhaseDown},
+	}, rc.Datacenter.Status.RackStatuses)
+
+	// rack1 is scaled down, while rack2 is scaled up and has one ready pod
+	rc.desiredRackInformation[0].NodeCount = 1
+	rc.desiredRackInformation[1].NodeCount = 2
+	ready := makeMockReadyStartedPod()
+	ready.Name = "rack2-pod-1"
+	ready.Labels[api.RackLabel] = "rack2"
+	rc.dcPods = append(rc.dcPods, ready)
+
+	assert.Equal(result.Continue(), rc.CheckRackStatuses())
+	assert.Equal([]api.RackStatus{
+		{Name: "rack1", DesiredNodes: 1, ReadyNodes: 2, Phase: api.RackPhaseScalingDown},
+		{Name: "rack2", DesiredNodes: 2, ReadyNodes: 1, Phase: api.RackPhasePending},
+	}, rc.Datacenter.Status.RackStatuses)
+}
+
+func TestCheckRackStatuses_ObservedImage(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.ServerImage = "cassandra:4.0.3"
+	rc.desiredRackInformation = []*RackInformation{
+		{RackName: "rack1", NodeCount: 2},
+		{RackName: "rack2", NodeCount: 1},
+	}
+
+	podWithImage := func(name, rack, image string) *corev1.Pod {
+		pod := makeMockReadyStartedPod()
+		pod.Name = name
+		pod.Labels[api.RackLabel] = rack
+		pod.Spec.Containers = []corev1.Container{
+			{Name: CassandraContainerName, Image: image},
+			{Name: SystemLoggerContainerName, Image: "system-logger:latest"},
+		}
+		return pod
+	}
+
+	// Mid-upgrade, rack1 has one upgraded pod and rack2 none
+	rc.dcPods = []*corev1.Pod{
+		podWithImage("rack1-pod-0", "rack1", "cassandra:4.0.3"),
+		podWithImage("rack1-pod-1", "rack1", "cassandra:4.0.1"),
+		podWithImage("rack2-pod-0", "rack2", "cassandra:4.0.1"),
+	}
+
+	assert.Equal(result.Continue(), rc.CheckRackStatuses())
+	assert.Equal("cassandra:4.0.3", rc.Datacenter.Status.ServerImage)
+	assert.Equal("cassandra:4.0.1", rc.Datacenter.Status.RackStatuses[0].ObservedImage)
+	assert.Equal("cassandra:4.0.1", rc.Datacenter.Status.RackStatuses[1].ObservedImage)
+
+	// Once all the pods of rack1 are upgraded, it observes the desired image
+	rc.dcPods[1] = podWithImage("rack1-pod-1", "rack1", "cassandra:4.0.3")
+
+	assert.Equal(result.Continue(), rc.CheckRackStatuses())
+	assert.Equal("cassandra:4.0.3", rc.Datacenter.Status.RackStatuses[0].ObservedImage)
+	assert.NotEqual(rc.Datacenter.Status.ServerImage, rc.Datacenter.Status.RackStatuses[1].ObservedImage)
+}
+
+func TestCheckUnhealthyPods(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	for name, status := range map[string]corev1.ConditionStatus{"node1": corev1.ConditionTrue, "node2": corev1.ConditionFalse} {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			},
+		}
+		assert.NoError(rc.Client.Create(rc.Ctx, node))
+	}
+
+	healthy := makeMockReadyStartedPod()
+	healthy.Name = "healthy"
+	healthy.Spec.NodeName = "node1"
+
+	crashing := makeMockReadyStartedPod()
+	crashing.Name = "crashing"
+	crashing.Spec.NodeName = "node1"
+	crashing.Status.ContainerStatuses[0].Ready = false
+	crashing.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+	}
+
+	onNotReadyNode := makeMockReadyStartedPod()
+	onNotReadyNode.Name = "on-not-ready-node"
+	onNotReadyNode.Spec.NodeName = "node2"
+
+	rc.dcPods = []*corev1.Pod{healthy, crashing, onNotReadyNode}
+
+	assert.Equal([]*corev1.Pod{crashing}, FilterPodsInCrashLoop(rc.dcPods))
+	assert.Equal(result.Continue(), rc.CheckUnhealthyPods())
+	assert.Equal([]string{"crashing", "on-not-ready-node"}, rc.Datacenter.Status.UnhealthyPods)
+
+	// Once the pods recover, the list is cleared
+	rc.dcPods = []*corev1.Pod{healthy}
+	assert.Equal(result.Continue(), rc.CheckUnhealthyPods())
+	assert.Empty(rc.Datacenter.Status.UnhealthyPods)
+}
+
+func TestCheckConfigHash(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	assert.Equal(result.Continue(), rc.CheckConfigHash())
+	assert.NotEmpty(rc.Datacenter.Status.ConfigHash)
+
+	podTemplate, err := buildPodTemplateSpec(rc.Datacenter, nil, "rack1")
+	assert.NoError(err)
+	assert.Equal(podTemplate.Annotations[api.ConfigHashAnnotation], rc.Datacenter.Status.ConfigHash)
+
+	// A configuration change updates the hash, the existing pods are then pending a restart
+	previousHash := rc.Datacenter.Status.ConfigHash
+	rc.Datacenter.Spec.Config = []byte(`{"cassandra-yaml": {"num_tokens": 16}}`)
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+	assert.Equal(result.Continue(), rc.CheckConfigHash())
+	assert.NotEqual(previousHash, rc.Datacenter.Status.ConfigHash)
+
+	podTemplate, err = buildPodTemplateSpec(rc.Datacenter, nil, "rack1")
+	assert.NoError(err)
+	assert.Equal(podTemplate.Annotations[api.ConfigHashAnnotation], rc.Datacenter.Status.ConfigHash)
+}
+
+func TestCheckImagePullFailures(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  CassandraContainerName,
+				Image: "private.registry/cassandra:4.0.1",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+				},
+			}},
+		},
+	}
+	rc.dcPods = []*corev1.Pod{pod, makeMockReadyStartedPod()}
+
+	assert.Equal([]*corev1.Pod{pod}, FilterPodsWithImagePullError(rc.dcPods))
+
+	r := rc.CheckImagePullFailures()
+	assert.Equal(result.Continue(), r)
+	condition, found := rc.Datacenter.GetCondition(api.DatacenterImagePullFailed)
+	assert.True(found)
+	assert.Equal(corev1.ConditionTrue, condition.Status)
+	assert.Equal("ImagePullBackOff", condition.Reason)
+	assert.Equal("pod-1: private.registry/cassandra:4.0.1", condition.Message)
+	assert.Equal(1, len(fakeRecorder.Events))
+
+	// Once the image is pulled, the condition is cleared
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Running: &corev1.ContainerStateRunning{},
+	}
+	assert.Empty(FilterPodsWithImagePullError(rc.dcPods))
+
+	r = rc.CheckImagePullFailures()
+	assert.Equal(result.Continue(), r)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterImagePullFailed))
+}
+
+func TestCheckClusterFormationTimeout(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	// The seed was started but its cassandra container keeps failing
+	seed := makeMockReadyStartedPod()
+	seed.Name = "pod-1"
+	seed.Labels[api.CassNodeState] = stateStarting
+	seed.Labels[api.SeedNodeLabel] = "true"
+	seed.Status.Phase = corev1.PodRunning
+	seed.Status.ContainerStatuses[0].Ready = false
+	seed.Status.ContainerStatuses[0].RestartCount = 2
+	seed.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{
+			Reason:   "Error",
+			ExitCode: 1,
+			Message:  "Unable to gossip with any peers\n",
+		},
+	}
+	other := makeMockReadyStartedPod()
+	other.Name = "pod-2"
+	other.Labels[api.CassNodeState] = stateReadyToStart
+	other.Status.ContainerStatuses[0].Ready = false
+	rc.dcPods = []*corev1.Pod{seed, other}
+	rc.clusterPods = rc.dcPods
+	rc.Datacenter.Status.LastServerNodeStarted = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+
+	// Disabled by default
+	assert.Equal(result.Continue(), rc.CheckClusterFormationTimeout())
+	assert.Equal(corev1.ConditionUnknown, rc.Datacenter.GetConditionStatus(api.DatacenterClusterFormationTimeout))
+
+	// Not timed out yet
+	rc.Datacenter.Spec.ClusterFormationTimeoutSeconds = 300
+	assert.Equal(result.Continue(), rc.CheckClusterFormationTimeout())
+	assert.Equal(corev1.ConditionUnknown, rc.Datacenter.GetConditionStatus(api.DatacenterClusterFormationTimeout))
+
+	rc.Datacenter.Spec.ClusterFormationTimeoutSeconds = 60
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+	assert.Equal(result.Continue(), rc.CheckClusterFormationTimeout())
+	condition, found := rc.Datacenter.GetCondition(api.DatacenterClusterFormationTimeout)
+	assert.True(found)
+	assert.Equal(corev1.ConditionTrue, condition.Status)
+	assert.Equal("SeedNotReady", condition.Reason)
+	assert.Equal("pod-1: phase Running, ready false, 2 restarts, last terminated: Error (exit code 1): Unable to gossip with any peers", condition.Message)
+	assert.Equal(1, len(fakeRecorder.Events))
+	assert.Contains(<-fakeRecorder.Events, "Warning ClusterFormationTimeout Cluster did not form within 1m0s")
+
+	// Once the seed is ready, the condition is cleared
+	seed.Labels[api.CassNodeState] = stateStarted
+	seed.Status.ContainerStatuses[0].Ready = true
+	assert.Equal(result.Continue(), rc.CheckClusterFormationTimeout())
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterClusterFormationTimeout))
+	assert.Empty(fakeRecorder.Events)
+}
+
+func TestCheckOOMKilledPods(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	pod := makeMockReadyStartedPod()
+	pod.Name = "pod-1"
+	pod.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{
+			Reason:     "OOMKilled",
+			ExitCode:   137,
+			FinishedAt: metav1.NewTime(time.Now().Add(-time.Minute)),
+		},
+	}
+	healthy := makeMockReadyStartedPod()
+	healthy.Name = "pod-2"
+	rc.dcPods = []*corev1.Pod{pod, healthy}
+
+	assert.Equal([]*corev1.Pod{pod}, FilterPodsWithOOMKill(rc.dcPods, time.Now()))
+	assert.Empty(FilterPodsWithOOMKill(rc.dcPods, time.Now().Add(oomKillWindow)))
+
+	r := rc.CheckOOMKilledPods()
+	assert.Equal(result.Continue(), r)
+	condition, found := rc.Datacenter.GetCondition(api.DatacenterCassandraOOMKilled)
+	assert.True(found)
+	assert.Equal(corev1.ConditionTrue, condition.Status)
+	assert.Equal("pod-1", condition.Message)
+	assert.Equal(1, len(fakeRecorder.Events))
+	assert.Contains(<-fakeRecorder.Events, "Warning CassandraOOMKilled")
+
+	// Once the container has been running for a while since the kill, the condition is cleared
+	pod.Status.ContainerStatuses[0].LastTerminationState.Terminated.FinishedAt = metav1.NewTime(time.Now().Add(-oomKillWindow))
+	r = rc.CheckOOMKilledPods()
+	assert.Equal(result.Continue(), r)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterCassandraOOMKilled))
+}
+
+func TestCheckEvictedPods(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: rc.Datacenter.Namespace,
+		},
+		Status: corev1.PodStatus{
+			Phase:   corev1.PodFailed,
+			Reason:  "Evicted",
+			Message: "The node was low on resource: ephemeral-storage.",
+		},
+	}
+	healthy := makeMockReadyStartedPod()
+	healthy.Name = "pod-2"
+	rc.dcPods = []*corev1.Pod{pod, healthy}
+
+	assert.Equal([]*corev1.Pod{pod}, FilterEvictedPods(rc.dcPods))
+
+	// Evictions for other resources are not reported
+	memoryEvicted := pod.DeepCopy()
+	memoryEvicted.Name = "pod-3"
+	memoryEvicted.Status.Message = "The node was low on resource: memory."
+	assert.Empty(FilterEvictedPods([]*corev1.Pod{memoryEvicted}))
+
+	r := rc.CheckEvictedPods()
+	assert.Equal(result.Continue(), r)
+	condition, found := rc.Datacenter.GetCondition(api.DatacenterPodEvictedDiskPressure)
+	assert.True(found)
+	assert.Equal(corev1.ConditionTrue, condition.Status)
+	assert.Equal("Evicted", condition.Reason)
+	assert.Equal("pod-1: The node was low on resource: ephemeral-storage.", condition.Message)
+	assert.Equal(1, len(fakeRecorder.Events))
+	assert.Contains(<-fakeRecorder.Events, "Warning PodEvicted")
+
+	// Once the evicted pod is replaced, the condition is cleared
+	rc.dcPods = []*corev1.Pod{healthy}
+	r = rc.CheckEvictedPods()
+	assert.Equal(result.Continue(), r)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterPodEvictedDiskPressure))
+}
+
+func TestStartCassandra_ManagementApiUnreachable(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do",
+		mock.MatchedBy(
+			func(req *http.Request) bool {
+				return req != nil
+			})).
+		Return(nil, fmt.Errorf("connection refused"))
+
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	epData := httphelper.CassMetadataEndpoints{
+		Entity: []httphelper.EndpointState{},
+	}
+
+	pod := makeReloadTestPod()
+	pod.Status.PodIP = "1.2.3.4"
+	err := rc.startCassandra(epData, pod)
+	assert.Error(err)
+	assert.IsType(&managementApiUnreachableError{}, err)
+	assert.Equal(result.RequeueSoon(10), startFailureResult(err))
+
+	// Only the reachability check was issued, no lifecycle command
+	mockHttpClient.AssertNumberOfCalls(t, "Do", 1)
+
+	condition, found := rc.Datacenter.GetCondition(api.DatacenterManagementApiUnreachable)
+	assert.True(found)
+	assert.Equal(corev1.ConditionTrue, condition.Status)
+	assert.Contains(condition.Message, "mypod")
+	assert.Len(fakeRecorder.Events, 1)
+	assert.Contains(<-fakeRecorder.Events, events.ManagementApiUnreachable)
+}
+
+func TestStartCassandra_ManagementApiStarting(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do",
+		mock.MatchedBy(
+			func(req *http.Request) bool {
+				return req != nil
+			})).
+		Return(nil, fmt.Errorf("connection refused"))
+
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	pod := makeReloadTestPod()
+	pod.Status.PodIP = "1.2.3.4"
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: CassandraContainerName,
+		State: corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Now().Add(-30 * time.Second))},
+		},
+	}}
+
+	// The pod is still starting, the start is retried without flagging the datacenter
+	err := rc.startCassandra(httphelper.CassMetadataEndpoints{}, pod)
+	assert.IsType(&managementApiUnreachableError{}, err)
+	assert.Equal(result.RequeueSoon(10), startFailureResult(err))
+	assert.Equal(corev1.ConditionUnknown, rc.Datacenter.GetConditionStatus(api.DatacenterManagementApiUnreachable))
+	assert.Len(fakeRecorder.Events, 0)
+}
+
+func TestFindStartedNotReadyNodes_ManagementApiUnreachable(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+
+	reachable := false
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do",
+		mock.MatchedBy(
+			func(req *http.Request) bool {
+				return req != nil
+			})).
+		Return(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("OK")),
+			}
+		}, func(req *http.Request) error {
+			if !reachable {
+				return fmt.Errorf("connection refused")
+			}
+			return nil
+		})
+
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	// A node that was started long ago and is no longer ready
+	pod := makeReloadTestPod()
+	pod.Labels[api.CassNodeState] = stateStartedNotReady
+	pod.Status.PodIP = "1.2.3.4"
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: CassandraContainerName,
+		State: corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Now().Add(-time.Hour))},
+		},
+	}}
+	assert.NoError(rc.Client.Create(rc.Ctx, pod))
+	rc.dcPods = []*corev1.Pod{pod}
+
+	nodeStartedNotReady, err := rc.findStartedNotReadyNodes()
+	assert.NoError(err)
+	assert.False(nodeStartedNotReady)
+	assert.Equal(corev1.ConditionTrue, rc.Datacenter.GetConditionStatus(api.DatacenterManagementApiUnreachable))
+	assert.Len(fakeRecorder.Events, 1)
+	assert.Contains(<-fakeRecorder.Events, events.ManagementApiUnreachable)
+
+	// The node is ready again, the condition is cleared
+	reachable = true
+	pod.Status.ContainerStatuses[0].Ready = true
+	_, err = rc.findStartedNotReadyNodes()
+	assert.NoError(err)
+	assert.Equal(stateStarted, pod.Labels[api.CassNodeState])
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterManagementApiUnreachable))
+}
+
+func TestCheckLabelMigrations(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	oplabels.SetLabelMigrations(map[string]string{"cassandra.example.com/rack": api.RackLabel})
+	defer oplabels.SetLabelMigrations(nil)
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sts-rack1",
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    map[string]string{"cassandra.example.com/rack": "rack1"},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"cassandra.example.com/rack": "rack1"},
+			},
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, sts))
+	rc.statefulSets = []*appsv1.StatefulSet{sts}
+
+	assert.Equal(result.Continue(), rc.CheckLabelMigrations())
+
+	updated := &appsv1.StatefulSet{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: sts.Name, Namespace: sts.Namespace}, updated))
+	assert.Equal("rack1", updated.Labels["cassandra.example.com/rack"])
+	assert.Equal("rack1", updated.Labels[api.RackLabel])
+	assert.Equal(map[string]string{"cassandra.example.com/rack": "rack1"}, updated.Spec.Selector.MatchLabels)
+
+	// Running the migration again doesn't change anything
+	assert.False(oplabels.MigrateLabels(updated.Labels))
+}
+
+func TestCheckLabelMigrations_OtherResources(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	oldDatacenterLabel := "cassandra.example.com/datacenter"
+	oplabels.SetLabelMigrations(map[string]string{oldDatacenterLabel: api.DatacenterLabel})
+	defer oplabels.SetLabelMigrations(nil)
+
+	oldLabels := func(dcName string) map[string]string {
+		return utils.MergeMap(rc.Datacenter.GetClusterLabels(), map[string]string{oldDatacenterLabel: dcName})
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc-service",
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    oldLabels(rc.Datacenter.Name),
+		},
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "server-data-pod-0",
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    oldLabels(rc.Datacenter.Name),
+		},
+	}
+	budget := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc-pdb",
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    oldLabels(rc.Datacenter.Name),
+		},
+	}
+	// Belongs to another datacenter of the cluster
+	otherPvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "server-data-other-pod-0",
+			Namespace: rc.Datacenter.Namespace,
+			Labels:    oldLabels("otherdc"),
+		},
+	}
+	for _, obj := range []client.Object{service, pvc, budget, otherPvc} {
+		assert.NoError(rc.Client.Create(rc.Ctx, obj))
+	}
+
+	assert.Equal(result.Continue(), rc.CheckLabelMigrations())
+
+	for _, obj := range []client.Object{service, pvc, budget} {
+		assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(obj), obj))
+		assert.Equal(rc.Datacenter.Name, obj.GetLabels()[api.DatacenterLabel], obj.GetName())
+		assert.Equal(rc.Datacenter.Name, obj.GetLabels()[oldDatacenterLabel], obj.GetName())
+	}
+
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(otherPvc), otherPvc))
+	assert.NotContains(otherPvc.Labels, api.DatacenterLabel)
+}
+
+func TestCheckRackPodTemplate_SkipUnchangedStatefulSets(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{
+		{Name: "rack1", Zone: "zone-1"},
+	}
+	rc.Datacenter.Spec.SkipUnchangedStatefulSets = true
+	rc.Datacenter.Generation = 2
+	rc.Datacenter.Status.ObservedGeneration = 2
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	if err := rc.CalculateRackInformation(); err != nil {
+		t.Fatalf("failed to calculate rack information: %s", err)
+	}
+
+	recResult := rc.CheckRackCreation()
+	assert.False(recResult.Completed(), "CheckRackCreation did not complete as expected")
+
+	// count how many times the desired statefulset is built
+	builds := 0
+	setControllerReference = func(owner, object metav1.Object, scheme *runtime.Scheme) error {
+		builds++
+		return nil
+	}
+
+	assert.Equal(result.Continue(), rc.CheckRackPodTemplate())
+	assert.Equal(1, builds)
+	assert.Equal("2/0", rc.statefulSets[0].Annotations[api.ReconciledGenerationAnnotation])
+
+	// Nothing changed, the statefulset is not built again
+	assert.Equal(result.Continue(), rc.CheckRackPodTemplate())
+	assert.Equal(1, builds)
+
+	// A new generation of the datacenter is reconciled
+	rc.Datacenter.Generation = 3
+	rc.Datacenter.Status.ObservedGeneration = 3
+	assert.Equal(result.Continue(), rc.CheckRackPodTemplate())
+	assert.Equal(2, builds)
+
+	// So is a statefulset that was modified
+	rc.statefulSets[0].Generation = 1
+	assert.Equal(result.Continue(), rc.CheckRackPodTemplate())
+	assert.Equal(3, builds)
+}
+
+func TestCheckReplaceAddressRemoval(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{
+		{Name: "rack1", Zone: "zone-1"},
+	}
+	rc.Datacenter.Spec.Size = 1
+	rc.Datacenter.Spec.RemoveReplaceAddressAfterReplace = true
+	rc.Datacenter.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: CassandraContainerName,
+					Env: []corev1.EnvVar{
+						{Name: "REPLACE_ADDRESS_FIRST_BOOT", Value: "10.0.0.5"},
+						{Name: "JVM_EXTRA_OPTS", Value: "-Dcassandra.replace_address=10.0.0.5 -Xss512k"},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	if err := rc.CalculateRackInformation(); err != nil {
+		t.Fatalf("failed to calculate rack information: %s", err)
+	}
+
+	recResult := rc.CheckRackCreation()
+	assert.False(recResult.Completed(), "CheckRackCreation did not complete as expected")
+
+	pod := makeMockReadyStartedPod()
+	pod.Name = rc.statefulSets[0].Name + "-0"
+	pod.Labels = utils.MergeMap(pod.Labels, rc.Datacenter.GetRackLabels("rack1"))
+	pod.Status.PodIP = "10.0.0.1"
+	rc.dcPods = []*corev1.Pod{pod}
+
+	epData := httphelper.CassMetadataEndpoints{
+		Entity: []httphelper.EndpointState{
+			{
+				RpcAddress: "10.0.0.1",
+				IsAlive:    "true",
+				Status:     "BOOT_REPLACE",
+			},
+		},
+	}
+
+	// The node is still joining, the replace address is kept
+	assert.Equal(result.Continue(), rc.CheckReplaceAddressRemoval(epData))
+	assert.NotContains(rc.statefulSets[0].Annotations, api.ReplaceAddressRemovedAnnotation)
+	assert.Equal(result.Continue(), rc.CheckRackPodTemplate())
+
+	// Once the node is UN, the replace address is removed from the pod template
+	epData.Entity[0].Status = string(httphelper.StatusNormal)
+	assert.Equal(result.Continue(), rc.CheckReplaceAddressRemoval(epData))
+	assert.Equal("REPLACE_ADDRESS_FIRST_BOOT=10.0.0.5,JVM_EXTRA_OPTS=-Dcassandra.replace_address=10.0.0.5 -Xss512k",
+		rc.statefulSets[0].Annotations[api.ReplaceAddressRemovedAnnotation])
+	assert.Equal(result.Done(), rc.CheckRackPodTemplate())
+
+	sts := &appsv1.StatefulSet{}
+	err := rc.Client.Get(rc.Ctx, types.NamespacedName{Namespace: rc.statefulSets[0].Namespace, Name: rc.statefulSets[0].Name}, sts)
+	assert.NoError(err)
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name != CassandraContainerName {
+			continue
+		}
+		for _, env := range container.Env {
+			assert.NotEqual("REPLACE_ADDRESS_FIRST_BOOT", env.Name)
+			if env.Name == "JVM_EXTRA_OPTS" {
+				assert.Equal("-Xss512k", env.Value)
+			}
+		}
+	}
+
+	// The statefulset is not updated again
+	assert.Equal(result.Continue(), rc.CheckRackPodTemplate())
+}
+
+func TestEffectivePodSpec(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{{Name: "rack1", Zone: "zone-1"}}
+	rc.Datacenter.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			PriorityClassName: "cassandra-critical",
+			Containers: []corev1.Container{
+				{
+					Name: CassandraContainerName,
+					Env:  []corev1.EnvVar{{Name: "USER_ENV", Value: "user"}},
+				},
+				{
+					Name:  "sidecar",
+					Image: "busybox",
+				},
+			},
+		},
+	}
+
+	podSpec, err := rc.EffectivePodSpec("rack1")
+	assert.NoError(err)
+
+	// user fields
+	assert.Equal("cassandra-critical", podSpec.PriorityClassName)
+	containerNames := []string{}
+	for _, container := range podSpec.Containers {
+		containerNames = append(containerNames, container.Name)
+	}
+	assert.Contains(containerNames, "sidecar")
+
+	// operator-managed fields
+	assert.Contains(containerNames, SystemLoggerContainerName)
+	assert.Equal("default", podSpec.ServiceAccountName)
+	assert.NotNil(podSpec.Affinity.NodeAffinity)
+	assert.NotEmpty(podSpec.InitContainers)
+
+	cassandra := podSpec.Containers[0]
+	assert.Equal(CassandraContainerName, cassandra.Name)
+	assert.NotEmpty(cassandra.Image)
+	assert.NotEmpty(cassandra.Ports)
+	envNames := []string{}
+	for _, env := range cassandra.Env {
+		envNames = append(envNames, env.Name)
+	}
+	assert.Contains(envNames, "USER_ENV")
+	assert.Contains(envNames, "DS_LICENSE")
+
+	_, err = rc.EffectivePodSpec("unknown")
+	assert.Error(err)
+}
+
+func TestCheckRackOwnerReferences(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	assert := assert.New(t)
+	setControllerReference = controllerutil.SetControllerReference
+
+	rc.Datacenter.UID = "dc-uid"
+	rc.desiredRackInformation = []*RackInformation{{RackName: "default", NodeCount: 1}}
+
+	sts, err := newStatefulSetForCassandraDatacenter(nil, "default", rc.Datacenter, 1, false)
+	assert.NoError(err)
+	assert.Nil(metav1.GetControllerOf(sts))
+	assert.NoError(rc.Client.Create(rc.Ctx, sts))
+	rc.statefulSets = []*appsv1.StatefulSet{sts}
+
+	assert.Equal(result.Continue(), rc.CheckRackOwnerReferences())
+
+	repaired := &appsv1.StatefulSet{}
+	assert.NoError(rc.Client.Get(rc.Ctx, client.ObjectKeyFromObject(sts), repaired))
+	owner := metav1.GetControllerOf(repaired)
+	if assert.NotNil(owner) {
+		assert.Equal(rc.Datacenter.UID, owner.UID)
+		assert.Equal(rc.Datacenter.Name, owner.Name)
+	}
+	assert.Contains(<-rc.Recorder.(*record.FakeRecorder).Events, events.RestoredOwnerReference)
+
+	// Already owned, nothing to do
+	rc.statefulSets = []*appsv1.StatefulSet{repaired}
+	assert.Equal(result.Continue(), rc.CheckRackOwnerReferences())
+	assert.Len(rc.Recorder.(*record.FakeRecorder).Events, 0)
+}
+
+func TestCheckRackLabels_RemovesDroppedAdditionalMetadata(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+	assert := assert.New(t)
+
+	rc.Datacenter.Spec.AdditionalLabels = map[string]string{"cost-center": "db", "team": "a"}
+	rc.Datacenter.Spec.AdditionalAnnotations = map[string]string{"billing/owner": "team-a", "billing/project": "p1"}
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+	rc.desiredRackInformation = []*RackInformation{{RackName: "default", NodeCount: 1}}
+
+	sts, err := newStatefulSetForCassandraDatacenter(nil, "default", rc.Datacenter, 1, false)
+	assert.NoError(err)
+	sts.Status.Replicas = 1
+	assert.NoError(rc.Client.Create(rc.Ctx, sts))
+	rc.statefulSets = []*appsv1.StatefulSet{sts}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "server-data-" + sts.Name + "-0",
+			Namespace:   sts.Namespace,
+			Labels:      sts.Spec.VolumeClaimTemplates[0].Labels,
+			Annotations: sts.Spec.VolumeClaimTemplates[0].Annotations,
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sts.Name + "-0",
+			Namespace: sts.Namespace,
+			Labels:    sts.Spec.Template.Labels,
+		},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name: PvcName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+				},
+			}},
+		},
+	}
+	assert.NoError(rc.Client.Create(rc.Ctx, pvc))
+	assert.NoError(rc.Client.Create(rc.Ctx, pod))
+
+	assert.False(rc.CheckRackLabels().Completed())
+	assert.False(rc.CheckRackPodLabels().Completed())
+	assert.False(rc.CheckAdditionalMetadataKeys().Completed())
+	assert.Equal([]string{"cost-center", "team"}, rc.Datacenter.Status.AdditionalLabelKeys)
+	assert.Equal([]string{"billing/owner", "billing/project"}, rc.Datacenter.Status.AdditionalAnnotationKeys)
+
+	// Drop a label and an annotation from the spec, they're removed from the resources
+	rc.Datacenter.Spec.AdditionalLabels = map[string]string{"cost-center": "db"}
+	rc.Datacenter.Spec.AdditionalAnnotations = map[string]string{"billing/owner": "team-b"}
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	assert.False(rc.CheckRackLabels().Completed())
+	assert.False(rc.CheckRackPodLabels().Completed())
+	assert.False(rc.CheckAdditionalMetadataKeys().Completed())
+
+	updatedSts := &appsv1.StatefulSet{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: sts.Name, Namespace: sts.Namespace}, updatedSts))
+	assert.Equal("db", updatedSts.Labels["cost-center"])
+	assert.NotContains(updatedSts.Labels, "team")
+	assert.Equal(oplabels.ManagedByLabelValue, updatedSts.Labels[oplabels.ManagedByLabel])
+	assert.Equal("team-b", updatedSts.Annotations["billing/owner"])
+	assert.NotContains(updatedSts.Annotations, "billing/project")
+	assert.Contains(updatedSts.Annotations, utils.ResourceHashAnnotationKey)
+
+	updatedPvc := &corev1.PersistentVolumeClaim{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, updatedPvc))
+	assert.NotContains(updatedPvc.Labels, "team")
+	assert.Equal(map[string]string{"billing/owner": "team-b"}, updatedPvc.Annotations)
+
+	updatedPod := &corev1.Pod{}
+	assert.NoError(rc.Client.Get(rc.Ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, updatedPod))
+	assert.NotContains(updatedPod.Labels, "team")
+
+	assert.Equal([]string{"cost-center"}, rc.Datacenter.Status.AdditionalLabelKeys)
+	assert.Equal([]string{"billing/owner"}, rc.Datacenter.Status.AdditionalAnnotationKeys)
+}
+
+func TestCheckGossipConsistency(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	fakeRecorder := record.NewFakeRecorder(5)
+	rc.Recorder = fakeRecorder
+	rc.Datacenter.Spec.GossipHealthCheck = true
+	assert.NoError(rc.Client.Update(rc.Ctx, rc.Datacenter))
+
+	// The gossip state as seen by each node, 10.0.0.2 doesn't see 10.0.0.3
+	alive := map[string]map[string]string{
+		"10.0.0.1": {"10.0.0.1": "true", "10.0.0.2": "true", "10.0.0.3": "true"},
+		"10.0.0.2": {"10.0.0.1": "true", "10.0.0.2": "true", "10.0.0.3": "false"},
+		"10.0.0.3": {"10.0.0.1": "true", "10.0.0.2": "true", "10.0.0.3": "true"},
+	}
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do", mock.Anything).
+		Return(func(req *http.Request) *http.Response {
+			entities := []string{
+				// the nodes of other datacenters are ignored
+				`{"DC": "dc2", "RPC_ADDRESS": "10.1.0.1", "IS_ALIVE": "false", "STATUS": "NORMAL,-1"}`,
+			}
+			for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+				entities = append(entities, fmt.Sprintf(`{"DC": "%s", "RPC_ADDRESS": "%s", "IS_ALIVE": "%s", "STATUS": "NORMAL,-1"}`,
+					rc.Datacenter.Name, ip, alive[req.URL.Hostname()][ip]))
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"entity": [` + strings.Join(entities, ",") + `]}`)),
+			}
+		}, nil)
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	rc.dcPods = []*corev1.Pod{}
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		pod := makeMockReadyStartedPod()
+		pod.Name = []string{"pod-1", "pod-2", "pod-3"}[i]
+		pod.Status.PodIP = ip
+		rc.dcPods = append(rc.dcPods, pod)
+	}
+
+	dcName := types.NamespacedName{Name: rc.Datacenter.Name, Namespace: rc.Datacenter.Namespace}
+	gossipProbes.reset(dcName)
+	defer gossipProbes.reset(dcName)
+
+	consistent, err := rc.checkGossipConsistency()
+	assert.NoError(err)
+	assert.False(consistent)
+	condition, found := rc.Datacenter.GetCondition(api.DatacenterGossipInconsistency)
+	assert.True(found)
+	assert.Equal(corev1.ConditionTrue, condition.Status)
+	assert.Equal("pod pod-2 sees 10.0.0.3 as unreachable", condition.Message)
+	assert.Len(fakeRecorder.Events, 1)
+	assert.Contains(<-fakeRecorder.Events, events.GossipInconsistency)
+
+	// The nodes are not probed again within the interval
+	alive["10.0.0.2"]["10.0.0.3"] = "true"
+	calls := len(mockHttpClient.Calls)
+	consistent, err = rc.checkGossipConsistency()
+	assert.NoError(err)
+	assert.False(consistent)
+	assert.Len(mockHttpClient.Calls, calls)
+
+	// Once the nodes agree, the condition is cleared
+	gossipProbes.reset(dcName)
+	consistent, err = rc.checkGossipConsistency()
+	assert.NoError(err)
+	assert.True(consistent)
+	assert.Equal(corev1.ConditionFalse, rc.Datacenter.GetConditionStatus(api.DatacenterGossipInconsistency))
+
+	// A node is expectedly down during a rolling restart, the gossip state is not read
+	alive["10.0.0.2"]["10.0.0.3"] = "false"
+	rc.Datacenter.Status.RollingOperation = &api.RollingOperation{Type: api.RollingOperationRestart, Pod: "pod-3"}
+	gossipProbes.reset(dcName)
+	calls = len(mockHttpClient.Calls)
+	consistent, err = rc.checkGossipConsistency()
+	assert.NoError(err)
+	assert.True(consistent)
+	assert.Len(mockHttpClient.Calls, calls)
+	assert.Len(fakeRecorder.Events, 0)
+
+	// Nor while a pod is not ready
+	rc.Datacenter.Status.RollingOperation = nil
+	rc.dcPods[2].Status.ContainerStatuses[0].Ready = false
+	consistent, err = rc.checkGossipConsistency()
+	assert.NoError(err)
+	assert.True(consistent)
+	assert.Len(mockHttpClient.Calls, calls)
+	assert.Len(fakeRecorder.Events, 0)
+}
+
+func TestCheckConfigBuilderFeatures(t *testing.T) {
+	assert := assert.New(t)
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	sts := &appsv1.StatefulSet{}
+	sts.Spec.Template.Spec.InitContainers = []corev1.Container{{
+		Name:  ServerConfigContainerName,
+		Image: "datastax/cass-config-builder:1.0.3-ubi7",
+	}}
+	rc.statefulSets = []*appsv1.StatefulSet{sts}
+
+	assert.Equal(result.Continue(), rc.CheckConfigBuilderFeatures())
+	assert.Equal([]string{images.ConfigBuilderEncryption}, rc.Datacenter.Status.ConfigBuilderFeatures)
+
+	sts.Spec.Template.Spec.InitContainers[0].Image = "datastax/cass-config-builder:1.0.4-ubi7"
+	assert.Equal(result.Continue(), rc.CheckConfigBuilderFeatures())
+	assert.Equal([]string{images.ConfigBuilderEncryption, images.ConfigBuilderCustomConfigSections}, rc.Datacenter.Status.ConfigBuilderFeatures)
+
+	// An image without any of the features leaves the field unset, as read back from the API
+	sts.Spec.Template.Spec.InitContainers[0].Image = "datastax/cass-config-builder:1.0.2"
+	assert.Equal(result.Continue(), rc.CheckConfigBuilderFeatures())
+	assert.Nil(rc.Datacenter.Status.ConfigBuilderFeatures)
+}
@@ -4,7 +4,10 @@
 package reconciliation
 
 import (
+	"reflect"
+
 	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/pkg/events"
 	"github.com/k8ssandra/cass-operator/pkg/internal/result"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -36,7 +39,7 @@ func (rc *ReconciliationContext) CreateHeadlessServices() result.ReconcileResult
 			return result.Error(err)
 		}
 
-		rc.Recorder.Eventf(rc.Datacenter, "Normal", "CreatedResource", "Created service %s", service.Name)
+		rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.CreatedResource, "Created service %s", service.Name)
 	}
 
 	// at this point we had previously been saying this reconcile call was over, we're done
@@ -97,7 +100,10 @@ func (rc *ReconciliationContext) CheckHeadlessServices() result.ReconcileResult
 
 		} else {
 			// if we found the service already, check if they need updating
-			if !utils.ResourcesHaveSameHash(currentService, desiredSvc) {
+			// the selector is compared too, in case it was edited without the hash annotation, so
+			// that the seed service keeps selecting exactly the seed pods
+			if !utils.ResourcesHaveSameHash(currentService, desiredSvc) ||
+				!reflect.DeepEqual(currentService.Spec.Selector, desiredSvc.Spec.Selector) {
 				resourceVersion := currentService.GetResourceVersion()
 
 				// ClusterIP may have been updated for the NodePort service
@@ -11,8 +11,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
+	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/cass-operator/pkg/mocks"
 	"github.com/k8ssandra/cass-operator/pkg/utils"
 )
@@ -33,6 +36,52 @@ func TestReconcileHeadlessService(t *testing.T) {
 	assert.False(t, recResult.Completed(), "Reconcile loop should not be completed")
 }
 
+func TestReconcileHeadlessService_PerPodDNS(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	owners := map[string]metav1.Object{}
+	setControllerReference = func(owner, object metav1.Object, scheme *runtime.Scheme) error {
+		owners[object.GetName()] = owner
+		return nil
+	}
+
+	assert.False(t, rc.CheckHeadlessServices().Completed())
+
+	service := &corev1.Service{}
+	nsName := types.NamespacedName{Name: rc.Datacenter.GetAllPodsServiceName(), Namespace: rc.Datacenter.Namespace}
+	assert.NoError(t, rc.Client.Get(rc.Ctx, nsName, service))
+	assert.Equal(t, corev1.ClusterIPNone, service.Spec.ClusterIP)
+	assert.True(t, service.Spec.PublishNotReadyAddresses)
+	assert.Equal(t, rc.Datacenter.GetDatacenterLabels(), service.Spec.Selector)
+	assert.Equal(t, rc.Datacenter, owners[service.Name])
+
+	// The pods get their DNS name from the governing service of their StatefulSet
+	statefulSet, err := newStatefulSetForCassandraDatacenter(nil, "default", rc.Datacenter, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, service.Name, statefulSet.Spec.ServiceName)
+}
+
+func TestReconcileHeadlessService_SeedServiceSelector(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	assert.False(t, rc.CheckHeadlessServices().Completed())
+
+	service := &corev1.Service{}
+	nsName := types.NamespacedName{Name: rc.Datacenter.GetSeedServiceName(), Namespace: rc.Datacenter.Namespace}
+	assert.NoError(t, rc.Client.Get(rc.Ctx, nsName, service))
+	assert.Equal(t, "true", service.Spec.Selector[api.SeedNodeLabel])
+
+	// The selector was edited, the hash annotation is unchanged
+	delete(service.Spec.Selector, api.SeedNodeLabel)
+	assert.NoError(t, rc.Client.Update(rc.Ctx, service))
+
+	assert.False(t, rc.CheckHeadlessServices().Completed())
+	assert.NoError(t, rc.Client.Get(rc.Ctx, nsName, service))
+	assert.Equal(t, buildLabelSelectorForSeedService(rc.Datacenter), service.Spec.Selector)
+}
+
 func TestReconcileHeadlessService_UpdateLabelsAndAnnotations(t *testing.T) {
 	rc, _, cleanupMockScr := setupTest()
 	defer cleanupMockScr()
@@ -84,6 +84,44 @@ func buildDefaultSuperuserSecret(dc *api.CassandraDatacenter) (*corev1.Secret, e
 	return secret, nil
 }
 
+// buildClientConfigSecret builds the secret with the configuration for CQL clients of the datacenter.
+// The CA certificate is only added if it's not empty.
+func buildClientConfigSecret(dc *api.CassandraDatacenter, superuserSecret *corev1.Secret, caCert []byte) *corev1.Secret {
+	labels := dc.GetDatacenterLabels()
+	oplabels.AddOperatorLabels(labels, dc)
+
+	nativePort := api.DefaultNativePort
+	if dc.IsNodePortEnabled() {
+		nativePort = dc.GetNodePortNativePort()
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dc.GetClientConfigSecretName(),
+			Namespace: dc.Namespace,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{
+			"contact-points":   []byte(fmt.Sprintf("%s.%s.svc", dc.GetDatacenterServiceName(), dc.Namespace)),
+			"port":             []byte(fmt.Sprintf("%d", nativePort)),
+			"local-datacenter": []byte(dc.Name),
+			"username":         superuserSecret.Data["username"],
+			"password":         superuserSecret.Data["password"],
+		},
+	}
+	if len(caCert) > 0 {
+		secret.Data["ca.crt"] = caCert
+	}
+
+	utils.AddHashAnnotation(secret)
+
+	return secret
+}
+
 func (rc *ReconciliationContext) retrieveSecret(secretNamespacedName types.NamespacedName) (*corev1.Secret, error) {
 	secret := &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
@@ -195,6 +233,10 @@ func (rc *ReconciliationContext) keystoreCASecret() types.NamespacedName {
 	return types.NamespacedName{Name: fmt.Sprintf("%s-ca-keystore", rc.Datacenter.Name), Namespace: rc.Datacenter.Namespace}
 }
 
+func (rc *ReconciliationContext) keystoreSecret() types.NamespacedName {
+	return types.NamespacedName{Name: fmt.Sprintf("%s-keystore", rc.Datacenter.Name), Namespace: rc.Datacenter.Namespace}
+}
+
 func (rc *ReconciliationContext) retrieveInternodeCredentialSecretOrCreateDefault() (*corev1.Secret, error) {
 	secret, retrieveErr := rc.retrieveSecret(rc.keystoreCASecret())
 	if retrieveErr != nil {
@@ -0,0 +1,42 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	snapshotOperationDecommission = "decommission"
+	snapshotOperationUpgrade      = "upgrade"
+)
+
+// TakeSnapshot takes a snapshot on each of the given pods that are up, if SnapshotBeforeDestructive
+// is enabled, and records the snapshot name in the status. The snapshot is named after the
+// operation it precedes so it can be found again if the operation needs to be rolled back.
+func (rc *ReconciliationContext) TakeSnapshot(pods []*corev1.Pod, operation string) error {
+	dc := rc.Datacenter
+	if !dc.Spec.SnapshotBeforeDestructive {
+		return nil
+	}
+
+	snapshotName := fmt.Sprintf("cass-operator-%s-%d", operation, time.Now().Unix())
+	for _, pod := range pods {
+		if !isServerReady(pod) {
+			continue
+		}
+		if err := rc.NodeMgmtClient.CallTakeSnapshotEndpoint(pod, snapshotName); err != nil {
+			rc.ReqLogger.Error(err, "failed to take snapshot", "pod", pod.Name, "snapshotName", snapshotName)
+			return err
+		}
+	}
+
+	dcPatch := client.MergeFrom(dc.DeepCopy())
+	dc.Status.LastSnapshotName = snapshotName
+	return rc.Client.Status().Patch(rc.Ctx, dc, dcPatch)
+}
@@ -6,6 +6,8 @@ package utils
 import (
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"hash/fnv"
 
 	"k8s.io/kubernetes/pkg/util/hash"
 )
@@ -43,3 +45,11 @@ func deepHashString(obj interface{}) string {
 	b64Hash := base64.StdEncoding.EncodeToString(hashBytes)
 	return b64Hash
 }
+
+// HashAnnotationValue returns a short hash of obj, stable across runs, that can be used as a label
+// or annotation value: 16 lowercase hexadecimal characters.
+func HashAnnotationValue(obj interface{}) string {
+	hasher := fnv.New64a()
+	hash.DeepHashObject(hasher, obj)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
@@ -1,8 +1,10 @@
 package utils
 
 import (
-	appsv1 "k8s.io/api/apps/v1"
+	"regexp"
 	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
 )
 
 func Test_deepHashString(t *testing.T) {
@@ -40,3 +42,29 @@ func Test_deepHashString(t *testing.T) {
 		}
 	})
 }
+
+func Test_HashAnnotationValue(t *testing.T) {
+	labelValue := regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+	var ss1 appsv1.StatefulSet
+	ss1.Labels = map[string]string{"abc": "123", "def": "456"}
+	var ss2 appsv1.StatefulSet
+	ss2.Labels = map[string]string{"def": "456", "abc": "123"}
+
+	hash1 := HashAnnotationValue(&ss1)
+	if hash1 != HashAnnotationValue(&ss2) {
+		t.Errorf("HashAnnotationValue should have produced the same hash for equal objects")
+	}
+	if !labelValue.MatchString(hash1) {
+		t.Errorf("HashAnnotationValue produced %s, which is not a valid label value", hash1)
+	}
+
+	ss2.Labels["abc"] = "789"
+	if hash1 == HashAnnotationValue(&ss2) {
+		t.Errorf("HashAnnotationValue did not produce different hashes %s", hash1)
+	}
+
+	if got := HashAnnotationValue("cluster_name: test"); !labelValue.MatchString(got) {
+		t.Errorf("HashAnnotationValue produced %s, which is not a valid label value", got)
+	}
+}
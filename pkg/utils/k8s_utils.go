@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +27,20 @@ func UnionStringSet(a, b StringSet) StringSet {
 	return result
 }
 
+// UnionStringSets returns the union of all the sets. Keys with a false value are not members of
+// their set and are left out.
+func UnionStringSets(sets ...StringSet) StringSet {
+	result := StringSet{}
+	for _, set := range sets {
+		for k, v := range set {
+			if v {
+				result[k] = true
+			}
+		}
+	}
+	return result
+}
+
 func SubtractStringSet(a, b StringSet) StringSet {
 	result := StringSet{}
 	for k := range a {
@@ -46,6 +61,45 @@ func IntersectionStringSet(a, b StringSet) StringSet {
 	return result
 }
 
+// SymmetricDifferenceStringSet returns the members that are in exactly one of the two sets
+func SymmetricDifferenceStringSet(a, b StringSet) StringSet {
+	result := StringSet{}
+	for k, v := range a {
+		if v && !b[k] {
+			result[k] = true
+		}
+	}
+	for k, v := range b {
+		if v && !a[k] {
+			result[k] = true
+		}
+	}
+	return result
+}
+
+// Len returns the number of members in the set, keys with a false value are not counted
+func (s StringSet) Len() int {
+	count := 0
+	for _, v := range s {
+		if v {
+			count++
+		}
+	}
+	return count
+}
+
+// ToSlice returns the members of the set sorted, so that they can be logged deterministically
+func (s StringSet) ToSlice() []string {
+	result := make([]string, 0, len(s))
+	for k, v := range s {
+		if v {
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
 //
 // k8s Node helper functions
 //
@@ -78,12 +132,65 @@ func FilterNodesWithFn(nodes []*corev1.Node, fn func(*corev1.Node) bool) []*core
 	return result
 }
 
+func IsNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func FilterNodesWithTaintKeyValueEffect(nodes []*corev1.Node, taintKey, value string, effect corev1.TaintEffect) []*corev1.Node {
 	return FilterNodesWithFn(nodes, func(node *corev1.Node) bool {
 		return hasTaint(node, taintKey, value, effect)
 	})
 }
 
+// hasTaintKeyEffect reports whether the node has a taint with the key and the effect, whatever its
+// value. An empty key or effect matches any.
+func hasTaintKeyEffect(node *corev1.Node, taintKey string, effect corev1.TaintEffect) bool {
+	for _, taint := range node.Spec.Taints {
+		if (taintKey == "" || taint.Key == taintKey) && (effect == "" || taint.Effect == effect) {
+			return true
+		}
+	}
+	return false
+}
+
+// PartitionNodesByTaint splits the nodes between the ones with a taint of the key and effect, and the
+// others. An empty key or effect matches any, so an empty key with corev1.TaintEffectNoSchedule
+// matches all the nodes that can't be scheduled on.
+func PartitionNodesByTaint(nodes []*corev1.Node, taintKey string, effect corev1.TaintEffect) ([]*corev1.Node, []*corev1.Node) {
+	tainted, untainted := []*corev1.Node{}, []*corev1.Node{}
+	for _, node := range nodes {
+		if hasTaintKeyEffect(node, taintKey, effect) {
+			tainted = append(tainted, node)
+		} else {
+			untainted = append(untainted, node)
+		}
+	}
+	return tainted, untainted
+}
+
+// FilterNodesWithoutTaint returns the nodes without a taint of the key and effect, see PartitionNodesByTaint
+func FilterNodesWithoutTaint(nodes []*corev1.Node, taintKey string, effect corev1.TaintEffect) []*corev1.Node {
+	_, untainted := PartitionNodesByTaint(nodes, taintKey, effect)
+	return untainted
+}
+
+// CountNodesByLabel returns how many of the nodes have each value of the label. Nodes without the
+// label are not counted.
+func CountNodesByLabel(nodes []*corev1.Node, label string) map[string]int {
+	result := map[string]int{}
+	for _, node := range nodes {
+		if value, ok := node.Labels[label]; ok {
+			result[value]++
+		}
+	}
+	return result
+}
+
 //
 // k8s Pod helper functions
 //
@@ -107,6 +214,17 @@ func GetPodNameSet(pods []*corev1.Pod) StringSet {
 	return names
 }
 
+// GetPodIPSet returns the IPs of the running pods
+func GetPodIPSet(pods []*corev1.Pod) StringSet {
+	ips := StringSet{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" {
+			ips[pod.Status.PodIP] = true
+		}
+	}
+	return ips
+}
+
 func GetPodNodeNameSet(pods []*corev1.Pod) StringSet {
 	names := StringSet{}
 	for _, pod := range pods {
@@ -153,6 +271,39 @@ func FilterPodsWithLabel(pods []*corev1.Pod, label, value string) []*corev1.Pod
 	})
 }
 
+// FilterPodsOnNotReadyNodes returns the pods scheduled to one of the given nodes that does
+// not report the Ready condition as True. Pods on nodes not in the list are not returned.
+func FilterPodsOnNotReadyNodes(pods []*corev1.Pod, nodes []*corev1.Node) []*corev1.Pod {
+	notReadyNodes := FilterNodesWithFn(nodes, func(node *corev1.Node) bool {
+		return !IsNodeReady(node)
+	})
+	return FilterPodsWithNodeInNameSet(pods, GetNodeNameSet(notReadyNodes))
+}
+
+// IsPodReady returns true if the pod is running, not terminating and reports the Ready
+// condition as True
+func IsPodReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// FilterPodsReady returns the pods that are ready, see IsPodReady
+func FilterPodsReady(pods []*corev1.Pod) []*corev1.Pod {
+	return FilterPodsWithFn(pods, IsPodReady)
+}
+
+// CountReadyPods returns the number of pods that are ready, see IsPodReady
+func CountReadyPods(pods []*corev1.Pod) int {
+	return len(FilterPodsReady(pods))
+}
+
 //
 // k8s PVC helpers
 //
@@ -166,12 +317,16 @@ func FilterPVCsWithFn(pvcs []*corev1.PersistentVolumeClaim, fn func(*corev1.Pers
 	return result
 }
 
+// PVCSelectedNodeAnnotation is set by the scheduler on PVCs with delayed binding, to the node
+// where the volume should be provisioned
+const PVCSelectedNodeAnnotation = "volume.kubernetes.io/selected-node"
+
 func GetPVCSelectedNodeName(pvc *corev1.PersistentVolumeClaim) string {
 	annos := pvc.Annotations
 	if annos == nil {
 		annos = map[string]string{}
 	}
-	pvcNode := annos["volume.kubernetes.io/selected-node"]
+	pvcNode := annos[PVCSelectedNodeAnnotation]
 	return pvcNode
 }
 
@@ -208,6 +363,28 @@ func GetWatchNamespace() (string, error) {
 	return ns, nil
 }
 
+// GetWatchNamespaces returns the namespaces the operator should be watching for changes, from a
+// comma-separated WATCH_NAMESPACE. Whitespace, empty entries and duplicates are dropped, so no
+// namespace means all of them.
+func GetWatchNamespaces() ([]string, error) {
+	ns, err := GetWatchNamespace()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{}
+	seen := StringSet{}
+	for _, namespace := range strings.Split(ns, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces, nil
+}
+
 // ErrNoNamespace indicates that a namespace could not be found for the current
 // environment
 var ErrNoNamespace = fmt.Errorf("namespace not found for current environment")
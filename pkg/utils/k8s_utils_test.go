@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeNode(name string, readyStatus corev1.ConditionStatus) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	if readyStatus != "" {
+		node.Status.Conditions = []corev1.NodeCondition{{
+			Type:   corev1.NodeReady,
+			Status: readyStatus,
+		}}
+	}
+	return node
+}
+
+func makePodOnNode(name, nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func TestFilterPodsOnNotReadyNodes(t *testing.T) {
+	nodes := []*corev1.Node{
+		makeNode("node-ready", corev1.ConditionTrue),
+		makeNode("node-not-ready", corev1.ConditionFalse),
+		makeNode("node-unknown", corev1.ConditionUnknown),
+		makeNode("node-no-condition", ""),
+	}
+
+	pods := []*corev1.Pod{
+		makePodOnNode("pod-0", "node-ready"),
+		makePodOnNode("pod-1", "node-not-ready"),
+		makePodOnNode("pod-2", "node-unknown"),
+		makePodOnNode("pod-3", "node-no-condition"),
+		makePodOnNode("pod-4", "node-missing"),
+		makePodOnNode("pod-5", ""),
+	}
+
+	filtered := FilterPodsOnNotReadyNodes(pods, nodes)
+	assert.Equal(t, StringSet{"pod-1": true, "pod-2": true, "pod-3": true}, GetPodNameSet(filtered))
+
+	assert.Empty(t, FilterPodsOnNotReadyNodes(pods, nodes[:1]))
+	assert.Empty(t, FilterPodsOnNotReadyNodes(nil, nodes))
+}
+
+func TestUnionStringSets(t *testing.T) {
+	a := StringSet{"pod-0": true, "pod-1": true}
+	b := StringSet{"pod-1": true, "pod-2": false}
+	c := StringSet{"pod-3": true, "pod-4": false}
+
+	assert.Equal(t, StringSet{"pod-0": true, "pod-1": true, "pod-3": true}, UnionStringSets(a, b, c))
+	assert.Equal(t, StringSet{"pod-1": true}, UnionStringSets(b))
+	assert.Empty(t, UnionStringSets())
+}
+
+func TestSymmetricDifferenceStringSet(t *testing.T) {
+	a := StringSet{"rack1": true, "rack2": true, "rack4": false}
+	b := StringSet{"rack2": true, "rack3": true}
+
+	assert.Equal(t, StringSet{"rack1": true, "rack3": true}, SymmetricDifferenceStringSet(a, b))
+	assert.Equal(t, SymmetricDifferenceStringSet(a, b), SymmetricDifferenceStringSet(b, a))
+	assert.Empty(t, SymmetricDifferenceStringSet(a, a))
+
+	// the inputs are left untouched
+	assert.Equal(t, StringSet{"rack1": true, "rack2": true, "rack4": false}, a)
+	assert.Equal(t, StringSet{"rack2": true, "rack3": true}, b)
+}
+
+func TestStringSet_LenAndToSlice(t *testing.T) {
+	s := StringSet{"rack3": true, "rack1": true, "rack2": true, "rack0": false}
+
+	assert.Equal(t, 3, s.Len())
+	assert.Equal(t, []string{"rack1", "rack2", "rack3"}, s.ToSlice())
+	assert.Equal(t, StringSet{"rack3": true, "rack1": true, "rack2": true, "rack0": false}, s)
+
+	assert.Equal(t, 0, StringSet{}.Len())
+	assert.Equal(t, []string{}, StringSet(nil).ToSlice())
+}
+
+func TestFilterPodsReady(t *testing.T) {
+	makePod := func(name string, phase corev1.PodPhase, conditions ...corev1.PodCondition) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.PodStatus{Phase: phase, Conditions: conditions},
+		}
+	}
+	ready := corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionTrue}
+	notReady := corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionFalse}
+	scheduled := corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}
+
+	terminating := makePod("terminating", corev1.PodRunning, ready)
+	now := metav1.Now()
+	terminating.DeletionTimestamp = &now
+
+	tests := []struct {
+		name  string
+		pod   *corev1.Pod
+		ready bool
+	}{
+		{name: "ready", pod: makePod("ready", corev1.PodRunning, scheduled, ready), ready: true},
+		{name: "no conditions", pod: makePod("no-conditions", corev1.PodRunning)},
+		{name: "not ready", pod: makePod("not-ready", corev1.PodRunning, notReady)},
+		{name: "not running", pod: makePod("pending", corev1.PodPending, ready)},
+		{name: "terminating", pod: terminating},
+	}
+
+	pods := []*corev1.Pod{}
+	expected := StringSet{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.ready, IsPodReady(tt.pod))
+		})
+		pods = append(pods, tt.pod)
+		if tt.ready {
+			expected[tt.pod.Name] = true
+		}
+	}
+
+	assert.Equal(t, expected, GetPodNameSet(FilterPodsReady(pods)))
+	assert.Equal(t, len(expected), CountReadyPods(pods))
+	assert.Equal(t, 0, CountReadyPods(nil))
+}
+
+func TestCountNodesByLabel(t *testing.T) {
+	nodes := []*corev1.Node{
+		makeNode("node-0", corev1.ConditionTrue),
+		makeNode("node-1", corev1.ConditionTrue),
+		makeNode("node-2", corev1.ConditionTrue),
+		makeNode("node-3", corev1.ConditionTrue),
+	}
+	nodes[0].Labels = map[string]string{"zone": "zone-a"}
+	nodes[1].Labels = map[string]string{"zone": "zone-b"}
+	nodes[2].Labels = map[string]string{"zone": "zone-a"}
+
+	assert.Equal(t, map[string]int{"zone-a": 2, "zone-b": 1}, CountNodesByLabel(nodes, "zone"))
+	assert.Empty(t, CountNodesByLabel(nodes, "rack"))
+}
+
+func TestGetWatchNamespaces(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		value      string
+		namespaces []string
+	}{
+		{"", []string{}},
+		{" , ,", []string{}},
+		{"ns1", []string{"ns1"}},
+		{" ns1 , ns2,,ns3 ", []string{"ns1", "ns2", "ns3"}},
+		{"ns1,ns2,ns1, ns2", []string{"ns1", "ns2"}},
+	}
+	for _, tt := range tests {
+		t.Setenv(WatchNamespaceEnvVar, tt.value)
+		namespaces, err := GetWatchNamespaces()
+		assert.NoError(err, tt.value)
+		assert.Equal(tt.namespaces, namespaces, tt.value)
+
+		// the single namespace is still returned as is
+		ns, err := GetWatchNamespace()
+		assert.NoError(err)
+		assert.Equal(tt.value, ns)
+	}
+
+	os.Unsetenv(WatchNamespaceEnvVar)
+	_, err := GetWatchNamespaces()
+	assert.Error(err)
+}
+
+func TestPartitionNodesByTaint(t *testing.T) {
+	assert := assert.New(t)
+
+	nodes := []*corev1.Node{
+		makeNode("plain", corev1.ConditionTrue),
+		makeNode("cordoned", corev1.ConditionTrue),
+		makeNode("special", corev1.ConditionTrue),
+		makeNode("multiple", corev1.ConditionTrue),
+	}
+	nodes[1].Spec.Taints = []corev1.Taint{{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}}
+	nodes[2].Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "spark", Effect: corev1.TaintEffectNoExecute}}
+	nodes[3].Spec.Taints = []corev1.Taint{
+		{Key: "dedicated", Value: "kafka", Effect: corev1.TaintEffectPreferNoSchedule},
+		{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule},
+	}
+
+	names := func(nodes []*corev1.Node) []string {
+		result := []string{}
+		for _, node := range nodes {
+			result = append(result, node.Name)
+		}
+		return result
+	}
+
+	tainted, untainted := PartitionNodesByTaint(nodes, corev1.TaintNodeUnschedulable, corev1.TaintEffectNoSchedule)
+	assert.Equal([]string{"cordoned", "multiple"}, names(tainted))
+	assert.Equal([]string{"plain", "special"}, names(untainted))
+
+	// the value of the taint doesn't matter, the effect does
+	tainted, untainted = PartitionNodesByTaint(nodes, "dedicated", corev1.TaintEffectNoExecute)
+	assert.Equal([]string{"special"}, names(tainted))
+	assert.Equal([]string{"plain", "cordoned", "multiple"}, names(untainted))
+
+	// effect-only matching
+	assert.Equal([]string{"plain", "cordoned", "multiple"}, names(FilterNodesWithoutTaint(nodes, "", corev1.TaintEffectNoExecute)))
+	assert.Equal([]string{"plain", "special"}, names(FilterNodesWithoutTaint(nodes, "", corev1.TaintEffectNoSchedule)))
+
+	// key-only matching
+	assert.Equal([]string{"plain", "cordoned"}, names(FilterNodesWithoutTaint(nodes, "dedicated", "")))
+
+	assert.Empty(FilterNodesWithoutTaint(nil, "dedicated", corev1.TaintEffectNoSchedule))
+}
+
+func TestGetPodIPSet(t *testing.T) {
+	running := makePodOnNode("running", "node-0")
+	running.Status = corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"}
+	pending := makePodOnNode("pending", "node-0")
+	pending.Status = corev1.PodStatus{Phase: corev1.PodPending, PodIP: "10.0.0.2"}
+	noIP := makePodOnNode("no-ip", "node-0")
+	noIP.Status = corev1.PodStatus{Phase: corev1.PodRunning}
+
+	assert.Equal(t, StringSet{"10.0.0.1": true}, GetPodIPSet([]*corev1.Pod{running, pending, noIP}))
+}
@@ -0,0 +1,37 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package utils
+
+import (
+	"sync/atomic"
+)
+
+// leader is 1 while this operator instance holds the leader election lease
+var leader int32
+
+// SetLeader records whether this operator instance is the leader. The manager sets it once it
+// is elected, which is right away when leader election is disabled, and clears it when the
+// operator shuts down.
+func SetLeader(isLeader bool) {
+	var value int32
+	if isLeader {
+		value = 1
+	}
+	atomic.StoreInt32(&leader, value)
+}
+
+// IsLeader returns true if this operator instance is the leader
+func IsLeader() bool {
+	return atomic.LoadInt32(&leader) == 1
+}
+
+// RunIfLeader calls fn only if this operator instance is the leader, so that background tasks
+// don't run on the standby instances. It returns whether fn was called.
+func RunIfLeader(fn func()) bool {
+	if !IsLeader() {
+		return false
+	}
+	fn()
+	return true
+}
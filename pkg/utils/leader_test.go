@@ -0,0 +1,32 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunIfLeader(t *testing.T) {
+	defer SetLeader(IsLeader())
+
+	runs := 0
+	task := func() { runs++ }
+
+	SetLeader(false)
+	assert.False(t, IsLeader())
+	assert.False(t, RunIfLeader(task))
+	assert.Equal(t, 0, runs)
+
+	SetLeader(true)
+	assert.True(t, IsLeader())
+	assert.True(t, RunIfLeader(task))
+	assert.Equal(t, 1, runs)
+
+	// Losing the lease stops the background tasks again
+	SetLeader(false)
+	assert.False(t, RunIfLeader(task))
+	assert.Equal(t, 1, runs)
+}
@@ -15,6 +15,16 @@ func IsPSPEnabled() bool {
 	return exists && "true" == strings.TrimSpace(value)
 }
 
+// DryRunEnvVar enables the dry run mode of the operator when set to true, see IsDryRunEnabled
+const DryRunEnvVar = "DRY_RUN"
+
+// IsDryRunEnabled returns true if the operator should compute the desired resources of the
+// datacenters without writing them to the API server
+func IsDryRunEnabled() bool {
+	value, exists := os.LookupEnv(DryRunEnvVar)
+	return exists && "true" == strings.TrimSpace(value)
+}
+
 func RangeInt(min, max, step int) []int {
 	size := int(math.Ceil(float64((max - min)) / float64(step)))
 	l := make([]int, size)